@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type DevWorkspacePrebuildSpec struct {
+	// Name of the DevWorkspaceTemplate whose projects should be prebuilt. The DevWorkspaceTemplate must be
+	// in the same namespace as the DevWorkspacePrebuild. DevWorkspaces that import this template as a parent
+	// or plugin start from the latest successful prebuild instead of cloning projects from scratch.
+	Template string `json:"template"`
+	// Schedule for running prebuilds, in Cron format. If unset, prebuilds are run only when the
+	// DevWorkspacePrebuild is created, when the DevWorkspaceTemplate is updated, when triggered
+	// via the 'controller.devfile.io/prebuild-trigger' annotation, or on git push (if GitPushTrigger
+	// is set).
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// GitPushTrigger enables running prebuilds when changes are pushed to the git repositories of
+	// the DevWorkspaceTemplate's projects, via push event webhooks sent to the DevWorkspace Operator.
+	// +optional
+	GitPushTrigger *PrebuildGitPushTrigger `json:"gitPushTrigger,omitempty"`
+	// StorageSize is the size of the persistent volume claim used to store prebuilt projects. If unset,
+	// the default size for per-workspace storage is used.
+	// +optional
+	StorageSize *resource.Quantity `json:"storageSize,omitempty"`
+}
+
+type PrebuildGitPushTrigger struct {
+	// SecretName is the name of a secret in the same namespace as the DevWorkspacePrebuild. The value
+	// of the secret's 'token' key is used to verify push events. Events sent by GitHub must be signed
+	// with the token, and events sent by GitLab must include the token.
+	SecretName string `json:"secretName"`
+}
+
+type DevWorkspacePrebuildStatus struct {
+	// Phase of the most recent prebuild
+	Phase DevWorkspacePrebuildPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase (e.g. reason for failure)
+	Message string `json:"message,omitempty"`
+	// ClaimName is the name of the persistent volume claim used to store prebuilt projects
+	ClaimName string `json:"claimName,omitempty"`
+	// LatestPrebuild is the name of the most recent successful prebuild. Prebuilt projects are stored in the
+	// subpath with this name in the prebuild's persistent volume claim.
+	LatestPrebuild string `json:"latestPrebuild,omitempty"`
+	// LastCompletionTime is the time the most recent successful prebuild completed
+	LastCompletionTime *metav1.Time `json:"lastCompletionTime,omitempty"`
+	// ObservedTemplateGeneration is the generation of the DevWorkspaceTemplate used for the most recent prebuild
+	ObservedTemplateGeneration int64 `json:"observedTemplateGeneration,omitempty"`
+	// ObservedTrigger is the value of the 'controller.devfile.io/prebuild-trigger' annotation when the most
+	// recent prebuild was started
+	ObservedTrigger string `json:"observedTrigger,omitempty"`
+}
+
+type DevWorkspacePrebuildPhase string
+
+const (
+	PrebuildPending   DevWorkspacePrebuildPhase = "Pending"
+	PrebuildRunning   DevWorkspacePrebuildPhase = "Running"
+	PrebuildSucceeded DevWorkspacePrebuildPhase = "Succeeded"
+	PrebuildFailed    DevWorkspacePrebuildPhase = "Failed"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DevWorkspacePrebuild is the Schema for the devworkspaceprebuilds API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspaceprebuilds,scope=Namespaced,shortName=dwpb
+// +kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.template",description="The DevWorkspaceTemplate being prebuilt"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of the most recent prebuild"
+// +kubebuilder:printcolumn:name="Latest",type="string",JSONPath=".status.latestPrebuild",description="The most recent successful prebuild"
+// +kubebuilder:printcolumn:name="Info",type="string",JSONPath=".status.message",description="Additional info about DevWorkspacePrebuild state"
+type DevWorkspacePrebuild struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspacePrebuildSpec   `json:"spec,omitempty"`
+	Status DevWorkspacePrebuildStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DevWorkspacePrebuildList contains a list of DevWorkspacePrebuild
+type DevWorkspacePrebuildList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspacePrebuild `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspacePrebuild{}, &DevWorkspacePrebuildList{})
+}
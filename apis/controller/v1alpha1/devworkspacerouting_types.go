@@ -31,6 +31,9 @@ type DevWorkspaceRoutingSpec struct {
 	Endpoints map[string]EndpointList `json:"endpoints"`
 	// Selector that should be used by created services to point to the devworkspace Pod
 	PodSelector map[string]string `json:"podSelector"`
+	// Annotations to be added to the service that exposes the devworkspace Pod
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
 }
 
 type DevWorkspaceRoutingClass string
@@ -162,6 +165,11 @@ type Endpoint struct {
 	// +optional
 	Path string `json:"path,omitempty"`
 
+	// Annotations to be added to the Kubernetes objects (e.g. Ingress, Route, Service)
+	// created to expose this endpoint
+	// +optional
+	Annotations map[string]string `json:"annotation,omitempty"`
+
 	// Map of implementation-dependant string-based free-form attributes.
 	//
 	// Examples of Che-specific attributes:
@@ -45,6 +45,33 @@ func (in Attributes) DeepCopy() Attributes {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CreatorQuota) DeepCopyInto(out *CreatorQuota) {
+	*out = *in
+	if in.MaxRunningWorkspaces != nil {
+		in, out := &in.MaxRunningWorkspaces, &out.MaxRunningWorkspaces
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CreatorQuota.
+func (in *CreatorQuota) DeepCopy() *CreatorQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(CreatorQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevWorkspaceOperatorConfig) DeepCopyInto(out *DevWorkspaceOperatorConfig) {
 	*out = *in
@@ -107,6 +134,109 @@ func (in *DevWorkspaceOperatorConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspacePrebuild) DeepCopyInto(out *DevWorkspacePrebuild) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspacePrebuild.
+func (in *DevWorkspacePrebuild) DeepCopy() *DevWorkspacePrebuild {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspacePrebuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspacePrebuild) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspacePrebuildList) DeepCopyInto(out *DevWorkspacePrebuildList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspacePrebuild, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspacePrebuildList.
+func (in *DevWorkspacePrebuildList) DeepCopy() *DevWorkspacePrebuildList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspacePrebuildList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspacePrebuildList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspacePrebuildSpec) DeepCopyInto(out *DevWorkspacePrebuildSpec) {
+	*out = *in
+	if in.GitPushTrigger != nil {
+		in, out := &in.GitPushTrigger, &out.GitPushTrigger
+		*out = new(PrebuildGitPushTrigger)
+		**out = **in
+	}
+	if in.StorageSize != nil {
+		in, out := &in.StorageSize, &out.StorageSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspacePrebuildSpec.
+func (in *DevWorkspacePrebuildSpec) DeepCopy() *DevWorkspacePrebuildSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspacePrebuildSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspacePrebuildStatus) DeepCopyInto(out *DevWorkspacePrebuildStatus) {
+	*out = *in
+	if in.LastCompletionTime != nil {
+		in, out := &in.LastCompletionTime, &out.LastCompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspacePrebuildStatus.
+func (in *DevWorkspacePrebuildStatus) DeepCopy() *DevWorkspacePrebuildStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspacePrebuildStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevWorkspaceRouting) DeepCopyInto(out *DevWorkspaceRouting) {
 	*out = *in
@@ -193,6 +323,13 @@ func (in *DevWorkspaceRoutingSpec) DeepCopyInto(out *DevWorkspaceRoutingSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceRoutingSpec.
@@ -245,6 +382,13 @@ func (in *DevWorkspaceRoutingStatus) DeepCopy() *DevWorkspaceRoutingStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Attributes != nil {
 		in, out := &in.Attributes, &out.Attributes
 		*out = make(Attributes, len(*in))
@@ -328,6 +472,31 @@ func (in ExposedEndpointList) DeepCopy() ExposedEndpointList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBuildConfig) DeepCopyInto(out *ImageBuildConfig) {
+	*out = *in
+	if in.AllowRootBuilds != nil {
+		in, out := &in.AllowRootBuilds, &out.AllowRootBuilds
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowPrivilegedBuilds != nil {
+		in, out := &in.AllowPrivilegedBuilds, &out.AllowPrivilegedBuilds
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildConfig.
+func (in *ImageBuildConfig) DeepCopy() *ImageBuildConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBuildConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyNotFoundError) DeepCopyInto(out *KeyNotFoundError) {
 	*out = *in
@@ -356,6 +525,11 @@ func (in *OperatorConfiguration) DeepCopyInto(out *OperatorConfiguration) {
 		*out = new(WorkspaceConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Registry != nil {
+		in, out := &in.Registry, &out.Registry
+		*out = new(RegistryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EnableExperimentalFeatures != nil {
 		in, out := &in.EnableExperimentalFeatures, &out.EnableExperimentalFeatures
 		*out = new(bool)
@@ -442,6 +616,21 @@ func (in *PodAdditions) DeepCopy() *PodAdditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrebuildGitPushTrigger) DeepCopyInto(out *PrebuildGitPushTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrebuildGitPushTrigger.
+func (in *PrebuildGitPushTrigger) DeepCopy() *PrebuildGitPushTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(PrebuildGitPushTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Proxy) DeepCopyInto(out *Proxy) {
 	*out = *in
@@ -457,9 +646,85 @@ func (in *Proxy) DeepCopy() *Proxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfig.
+func (in *RegistryConfig) DeepCopy() *RegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
 	*out = *in
+	if in.AllowedEndpointAnnotations != nil {
+		in, out := &in.AllowedEndpointAnnotations, &out.AllowedEndpointAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ProxyConfig != nil {
 		in, out := &in.ProxyConfig, &out.ProxyConfig
 		*out = new(Proxy)
@@ -530,6 +795,55 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = new(v1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImageBuild != nil {
+		in, out := &in.ImageBuild, &out.ImageBuild
+		*out = new(ImageBuildConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DevfileVariables != nil {
+		in, out := &in.DevfileVariables, &out.DevfileVariables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DefaultContainerResources != nil {
+		in, out := &in.DefaultContainerResources, &out.DefaultContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerResourceBounds != nil {
+		in, out := &in.ContainerResourceBounds, &out.ContainerResourceBounds
+		*out = new(ResourceBounds)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CreatorQuota != nil {
+		in, out := &in.CreatorQuota, &out.CreatorQuota
+		*out = new(CreatorQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedPriorityClassNames != nil {
+		in, out := &in.AllowedPriorityClassNames, &out.AllowedPriorityClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailedStartLogLines != nil {
+		in, out := &in.FailedStartLogLines, &out.FailedStartLogLines
+		*out = new(int)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceConfig.
@@ -0,0 +1,369 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceprebuild
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// DevWorkspacePrebuildReconciler reconciles a DevWorkspacePrebuild object
+type DevWorkspacePrebuildReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceprebuilds,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceprebuilds/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+
+func (r *DevWorkspacePrebuildReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	prebuild := &controllerv1alpha1.DevWorkspacePrebuild{}
+	err := r.Get(ctx, req.NamespacedName, prebuild)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			// Owned objects are automatically garbage collected
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if prebuild.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+	reqLogger.Info("Reconciling DevWorkspacePrebuild")
+
+	clusterAPI := sync.ClusterAPI{
+		Client: r.Client,
+		Scheme: r.Scheme,
+		Logger: reqLogger,
+		Ctx:    ctx,
+	}
+	status := prebuild.Status.DeepCopy()
+
+	template := &dw.DevWorkspaceTemplate{}
+	err = r.Get(ctx, types.NamespacedName{Name: prebuild.Spec.Template, Namespace: prebuild.Namespace}, template)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			status.Phase = controllerv1alpha1.PrebuildFailed
+			status.Message = fmt.Sprintf("DevWorkspaceTemplate %s not found", prebuild.Spec.Template)
+			return reconcile.Result{}, r.updateStatus(prebuild, status)
+		}
+		return reconcile.Result{}, err
+	}
+
+	devfileVariables, err := nsconfig.GetDevfileVariables(prebuild.Namespace, clusterAPI)
+	if err != nil {
+		status.Phase = controllerv1alpha1.PrebuildFailed
+		status.Message = fmt.Sprintf("Error reading devfile variables: %s", err)
+		return reconcile.Result{}, r.updateStatus(prebuild, status)
+	}
+	flattenHelpers := flatten.ResolverTools{
+		WorkspaceNamespace: prebuild.Namespace,
+		Context:            ctx,
+		K8sClient:          r.Client,
+		HttpClient:         registryClient,
+		DefaultVariables:   devfileVariables,
+	}
+	flattened, _, err := flatten.ResolveDevWorkspace(&template.Spec, flattenHelpers)
+	if err != nil {
+		status.Phase = controllerv1alpha1.PrebuildFailed
+		status.Message = fmt.Sprintf("Error processing DevWorkspaceTemplate: %s", err)
+		return reconcile.Result{}, r.updateStatus(prebuild, status)
+	}
+
+	pvc, err := getSpecPrebuildPVC(prebuild)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if ok, err := r.syncPrebuildObject(prebuild, pvc, clusterAPI); !ok {
+		return reconcile.Result{Requeue: true}, err
+	}
+	clusterPVC := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, clusterPVC); err != nil {
+		return reconcile.Result{}, err
+	}
+	if !hasAccessMode(clusterPVC, corev1.ReadWriteMany) {
+		// Prebuilds are mounted by workspaces on any node, so a ReadWriteOnce claim would prevent workspaces from starting
+		status.Phase = controllerv1alpha1.PrebuildFailed
+		status.Message = fmt.Sprintf("Persistent volume claim %s does not support ReadWriteMany access, which is required for prebuilds", pvc.Name)
+		status.ClaimName = ""
+		return reconcile.Result{}, r.updateStatus(prebuild, status)
+	}
+	status.ClaimName = pvc.Name
+
+	metadataCM, err := getSpecPrebuildMetadataConfigMap(prebuild, flattened)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if ok, err := r.syncPrebuildObject(prebuild, metadataCM, clusterAPI); !ok {
+		return reconcile.Result{Requeue: true}, err
+	}
+
+	jobTemplate, err := getSpecPrebuildJobTemplate(prebuild, flattened, clusterAPI)
+	if err != nil {
+		status.Phase = controllerv1alpha1.PrebuildFailed
+		status.Message = fmt.Sprintf("Failed to prepare prebuild job: %s", err)
+		return reconcile.Result{}, r.updateStatus(prebuild, status)
+	}
+
+	if prebuild.Spec.Schedule != "" {
+		cronJob := getSpecPrebuildCronJob(prebuild, jobTemplate)
+		if ok, err := r.syncPrebuildObject(prebuild, cronJob, clusterAPI); !ok {
+			return reconcile.Result{Requeue: true}, err
+		}
+	} else {
+		cronJob := &batchv1.CronJob{}
+		cronJob.Name = prebuild.Name
+		cronJob.Namespace = prebuild.Namespace
+		err := r.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(prebuild.Namespace), client.MatchingLabels{constants.DevWorkspacePrebuildLabel: prebuild.Name}); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.updateStatusFromJobs(prebuild, jobs.Items, status); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	trigger := prebuild.Annotations[constants.DevWorkspacePrebuildTriggerAnnotation]
+	needsRun := template.Generation != status.ObservedTemplateGeneration || trigger != status.ObservedTrigger
+	if needsRun && !hasActiveJob(jobs.Items) {
+		job := getSpecPrebuildJob(prebuild, jobTemplate)
+		if err := controllerutil.SetControllerReference(prebuild, job, r.Scheme); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := r.Create(ctx, job); err != nil {
+			return reconcile.Result{}, err
+		}
+		reqLogger.Info("Started prebuild", "job", job.Name)
+		status.ObservedTemplateGeneration = template.Generation
+		status.ObservedTrigger = trigger
+		status.Phase = controllerv1alpha1.PrebuildPending
+		status.Message = fmt.Sprintf("Started prebuild %s", job.Name)
+	}
+
+	return reconcile.Result{}, r.updateStatus(prebuild, status)
+}
+
+// syncPrebuildObject syncs obj to the cluster, setting the prebuild as its owner. Returns false if obj is not yet
+// ready, along with any error that occurred.
+func (r *DevWorkspacePrebuildReconciler) syncPrebuildObject(prebuild *controllerv1alpha1.DevWorkspacePrebuild, obj client.Object, clusterAPI sync.ClusterAPI) (ok bool, err error) {
+	if err := controllerutil.SetControllerReference(prebuild, obj, r.Scheme); err != nil {
+		return false, err
+	}
+	_, err = sync.SyncObjectWithCluster(obj, clusterAPI)
+	switch t := err.(type) {
+	case nil:
+		return true, nil
+	case *sync.NotInSyncError:
+		return false, nil
+	case *sync.UnrecoverableSyncError:
+		return false, t.Cause
+	default:
+		return false, err
+	}
+}
+
+// updateStatusFromJobs sets the phase of the prebuild based on the most recent prebuild job and records the most
+// recent successful run. Finished jobs that are no longer relevant are deleted.
+func (r *DevWorkspacePrebuildReconciler) updateStatusFromJobs(
+	prebuild *controllerv1alpha1.DevWorkspacePrebuild,
+	jobs []batchv1.Job,
+	status *controllerv1alpha1.DevWorkspacePrebuildStatus) error {
+
+	if len(jobs) == 0 {
+		if status.Phase == "" {
+			status.Phase = controllerv1alpha1.PrebuildPending
+		}
+		return nil
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.Before(&jobs[j].CreationTimestamp)
+	})
+
+	var latestSucceeded *batchv1.Job
+	for idx := range jobs {
+		job := &jobs[idx]
+		if job.Status.CompletionTime != nil && isJobConditionTrue(job, batchv1.JobComplete) {
+			if latestSucceeded == nil || latestSucceeded.Status.CompletionTime.Before(job.Status.CompletionTime) {
+				latestSucceeded = job
+			}
+		}
+	}
+	if latestSucceeded != nil && (status.LastCompletionTime == nil || status.LastCompletionTime.Before(latestSucceeded.Status.CompletionTime)) {
+		status.LatestPrebuild = latestSucceeded.Name
+		status.LastCompletionTime = latestSucceeded.Status.CompletionTime
+	}
+
+	newest := &jobs[len(jobs)-1]
+	switch {
+	case isJobConditionTrue(newest, batchv1.JobComplete):
+		status.Phase = controllerv1alpha1.PrebuildSucceeded
+		status.Message = fmt.Sprintf("Prebuild %s completed", newest.Name)
+	case isJobConditionTrue(newest, batchv1.JobFailed):
+		status.Phase = controllerv1alpha1.PrebuildFailed
+		status.Message = fmt.Sprintf("Prebuild %s failed: see logs for job %q for details", newest.Name, newest.Name)
+	case newest.Status.Active > 0:
+		status.Phase = controllerv1alpha1.PrebuildRunning
+		status.Message = fmt.Sprintf("Prebuild %s is running", newest.Name)
+	default:
+		status.Phase = controllerv1alpha1.PrebuildPending
+		status.Message = fmt.Sprintf("Prebuild %s is pending", newest.Name)
+	}
+
+	for idx := range jobs {
+		job := &jobs[idx]
+		if job == newest || job == latestSucceeded || !isJobFinished(job) {
+			continue
+		}
+		err := r.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DevWorkspacePrebuildReconciler) updateStatus(prebuild *controllerv1alpha1.DevWorkspacePrebuild, status *controllerv1alpha1.DevWorkspacePrebuildStatus) error {
+	if equality.Semantic.DeepEqual(prebuild.Status, *status) {
+		return nil
+	}
+	prebuild.Status = *status
+	return r.Status().Update(context.TODO(), prebuild)
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	return isJobConditionTrue(job, batchv1.JobComplete) || isJobConditionTrue(job, batchv1.JobFailed)
+}
+
+func hasAccessMode(pvc *corev1.PersistentVolumeClaim, accessMode corev1.PersistentVolumeAccessMode) bool {
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == accessMode {
+			return true
+		}
+	}
+	return false
+}
+
+func hasActiveJob(jobs []batchv1.Job) bool {
+	for idx := range jobs {
+		if !isJobFinished(&jobs[idx]) {
+			return true
+		}
+	}
+	return false
+}
+
+// prebuildJobsHandler maps prebuild jobs, including those created by prebuild CronJobs, to the DevWorkspacePrebuild
+// that created them.
+func prebuildJobsHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		prebuildName, ok := obj.GetLabels()[constants.DevWorkspacePrebuildLabel]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Name:      prebuildName,
+					Namespace: obj.GetNamespace(),
+				},
+			},
+		}
+	})
+}
+
+// templateToPrebuildsHandler maps DevWorkspaceTemplates to all DevWorkspacePrebuilds for that template, in order
+// to start a new prebuild whenever the template is updated.
+func (r *DevWorkspacePrebuildReconciler) templateToPrebuildsHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		prebuilds := &controllerv1alpha1.DevWorkspacePrebuildList{}
+		if err := r.List(context.TODO(), prebuilds, client.InNamespace(obj.GetNamespace())); err != nil {
+			r.Log.Error(err, "Failed to list DevWorkspacePrebuilds", "namespace", obj.GetNamespace())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, prebuild := range prebuilds.Items {
+			if prebuild.Spec.Template == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      prebuild.Name,
+						Namespace: prebuild.Namespace,
+					},
+				})
+			}
+		}
+		return requests
+	})
+}
+
+func (r *DevWorkspacePrebuildReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	setupHttpClient()
+
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspacePrebuild{}).
+		Owns(&batchv1.CronJob{}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, prebuildJobsHandler()).
+		Watches(&source.Kind{Type: &dw.DevWorkspaceTemplate{}}, r.templateToPrebuildsHandler()).
+		Complete(r)
+}
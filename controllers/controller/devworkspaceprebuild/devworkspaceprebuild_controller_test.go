@@ -0,0 +1,188 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceprebuild
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const testNamespace = "test-namespace"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(controllerv1alpha1.AddToScheme(scheme))
+	config.SetConfigForTesting(nil)
+}
+
+func TestUpdateStatusFromJobs(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name                   string
+		jobs                   []batchv1.Job
+		expectedPhase          controllerv1alpha1.DevWorkspacePrebuildPhase
+		expectedLatestPrebuild string
+		expectedRemainingJobs  []string
+	}{
+		{
+			name:          "No prebuild jobs",
+			expectedPhase: controllerv1alpha1.PrebuildPending,
+		},
+		{
+			name: "Running prebuild after successful prebuild",
+			jobs: []batchv1.Job{
+				getTestJob("prebuild-1", now.Add(-2*time.Hour), batchv1.JobComplete, &now),
+				getTestJob("prebuild-2", now.Add(-time.Hour), "", nil),
+			},
+			expectedPhase:          controllerv1alpha1.PrebuildRunning,
+			expectedLatestPrebuild: "prebuild-1",
+			expectedRemainingJobs:  []string{"prebuild-1", "prebuild-2"},
+		},
+		{
+			name: "Failed prebuild keeps latest successful prebuild and removes outdated jobs",
+			jobs: []batchv1.Job{
+				getTestJob("prebuild-1", now.Add(-3*time.Hour), batchv1.JobComplete, timePtr(now.Add(-3*time.Hour))),
+				getTestJob("prebuild-2", now.Add(-2*time.Hour), batchv1.JobComplete, timePtr(now.Add(-2*time.Hour))),
+				getTestJob("prebuild-3", now.Add(-time.Hour), batchv1.JobFailed, nil),
+			},
+			expectedPhase:          controllerv1alpha1.PrebuildFailed,
+			expectedLatestPrebuild: "prebuild-2",
+			expectedRemainingJobs:  []string{"prebuild-2", "prebuild-3"},
+		},
+		{
+			name: "Succeeded prebuild",
+			jobs: []batchv1.Job{
+				getTestJob("prebuild-1", now.Add(-time.Hour), batchv1.JobComplete, &now),
+			},
+			expectedPhase:          controllerv1alpha1.PrebuildSucceeded,
+			expectedLatestPrebuild: "prebuild-1",
+			expectedRemainingJobs:  []string{"prebuild-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			for idx := range tt.jobs {
+				objs = append(objs, &tt.jobs[idx])
+			}
+			r := &DevWorkspacePrebuildReconciler{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+				Log:    zap.New(),
+				Scheme: scheme,
+			}
+			prebuild := getTestPrebuild()
+			status := prebuild.Status.DeepCopy()
+
+			err := r.updateStatusFromJobs(prebuild, tt.jobs, status)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expectedPhase, status.Phase)
+			assert.Equal(t, tt.expectedLatestPrebuild, status.LatestPrebuild)
+
+			remainingJobs := &batchv1.JobList{}
+			if assert.NoError(t, r.List(context.Background(), remainingJobs)) {
+				var remainingNames []string
+				for _, job := range remainingJobs.Items {
+					remainingNames = append(remainingNames, job.Name)
+				}
+				assert.ElementsMatch(t, tt.expectedRemainingJobs, remainingNames)
+			}
+		})
+	}
+}
+
+func TestGetSpecPrebuildPVC(t *testing.T) {
+	size := resource.MustParse("5Gi")
+	prebuild := getTestPrebuild()
+	prebuild.Spec.StorageSize = &size
+
+	pvc, err := getSpecPrebuildPVC(prebuild)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, prebuildPVCName(prebuild.Name), pvc.Name)
+	assert.True(t, hasAccessMode(pvc, corev1.ReadWriteMany), "Prebuild PVC should be shareable between nodes")
+	assert.Equal(t, size, pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	assert.Equal(t, prebuild.Name, pvc.Labels[constants.DevWorkspacePrebuildLabel])
+}
+
+func TestHasActiveJob(t *testing.T) {
+	now := time.Now()
+	finished := []batchv1.Job{
+		getTestJob("prebuild-1", now, batchv1.JobComplete, &now),
+		getTestJob("prebuild-2", now, batchv1.JobFailed, nil),
+	}
+	assert.False(t, hasActiveJob(finished))
+	assert.True(t, hasActiveJob(append(finished, getTestJob("prebuild-3", now, "", nil))))
+}
+
+func getTestPrebuild() *controllerv1alpha1.DevWorkspacePrebuild {
+	return &controllerv1alpha1.DevWorkspacePrebuild{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-prebuild",
+			Namespace: testNamespace,
+		},
+		Spec: controllerv1alpha1.DevWorkspacePrebuildSpec{
+			Template: "test-template",
+		},
+	}
+}
+
+func getTestJob(name string, created time.Time, condition batchv1.JobConditionType, completed *time.Time) batchv1.Job {
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.Time{Time: created},
+			Labels: map[string]string{
+				constants.DevWorkspacePrebuildLabel: "test-prebuild",
+			},
+		},
+	}
+	if condition != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue}}
+	} else {
+		job.Status.Active = 1
+	}
+	if completed != nil {
+		job.Status.CompletionTime = &metav1.Time{Time: *completed}
+	}
+	return job
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
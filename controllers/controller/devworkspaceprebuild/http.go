@@ -0,0 +1,40 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceprebuild
+
+import (
+	"net/http"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/config/proxy"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+// registryClient is used to fetch devfiles and plugins from registries and URIs, caching results
+var registryClient *network.CachingHTTPGetter
+
+func setupHttpClient() {
+	var proxyConfig *controllerv1alpha1.Proxy
+	if config.Routing != nil {
+		proxyConfig = config.Routing.ProxyConfig
+	}
+	httpClient := &http.Client{
+		Transport: proxy.NewTransport(proxyConfig),
+	}
+	registryClient = network.NewCachingHTTPGetter(httpClient, config.GetRegistryCacheConfig, metrics.RegistryCacheObserver{})
+}
@@ -0,0 +1,316 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceprebuild
+
+import (
+	"fmt"
+	"path"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	devfileConstants "github.com/devfile/devworkspace-operator/pkg/library/constants"
+	containerlib "github.com/devfile/devworkspace-operator/pkg/library/container"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/metadata"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+const (
+	// prebuildRunEnvVar holds the name of the current prebuild run (i.e. the name of the prebuild job). Each run
+	// stores its projects in a subpath with this name in the prebuild PVC.
+	prebuildRunEnvVar = "PREBUILD_RUN"
+	// prebuildKeepEnvVar holds the name of the latest successful prebuild run when the job was created. This
+	// run may be in use by starting DevWorkspaces and is not removed when cleaning up old runs.
+	prebuildKeepEnvVar = "PREBUILD_KEEP"
+
+	prebuildStorageVolumeName  = "prebuild-storage"
+	prebuildMetadataVolumeName = "prebuild-metadata"
+	prebuildStorageMountPath   = "/prebuilds"
+	prebuildMetadataMountPath  = "/devworkspace-metadata"
+	flattenedYamlFilename      = "flattened.devworkspace.yaml"
+
+	prebuildCleanupContainerName = "prebuild-cleanup"
+	// prebuildCleanupCommand removes all prebuild runs except the current run and the latest successful run.
+	prebuildCleanupCommand = `cd "$0" && for run in *; do [ "$run" = "$PREBUILD_RUN" ] || [ "$run" = "$PREBUILD_KEEP" ] || rm -rf "$run"; done`
+)
+
+var (
+	prebuildJobBackoffLimit      = int32(0)
+	prebuildJobHistoryLimit      = int32(1)
+	prebuildCleanupMemoryLimit   = resource.MustParse(constants.PVCCleanupPodMemoryLimit)
+	prebuildCleanupMemoryRequest = resource.MustParse(constants.PVCCleanupPodMemoryRequest)
+	prebuildCleanupCPULimit      = resource.MustParse(constants.PVCCleanupPodCPULimit)
+	prebuildCleanupCPURequest    = resource.MustParse(constants.PVCCleanupPodCPURequest)
+)
+
+func prebuildPVCName(prebuildName string) string {
+	return fmt.Sprintf("%s-prebuild", prebuildName)
+}
+
+func prebuildMetadataConfigMapName(prebuildName string) string {
+	return fmt.Sprintf("%s-prebuild-metadata", prebuildName)
+}
+
+func prebuildLabels(prebuild *controllerv1alpha1.DevWorkspacePrebuild) map[string]string {
+	labels := constants.ControllerAppLabels()
+	labels[constants.DevWorkspacePrebuildLabel] = prebuild.Name
+	return labels
+}
+
+func getSpecPrebuildPVC(prebuild *controllerv1alpha1.DevWorkspacePrebuild) (*corev1.PersistentVolumeClaim, error) {
+	size := prebuild.Spec.StorageSize
+	if size == nil {
+		size = config.Workspace.DefaultStorageSize.PerWorkspace
+	}
+	if size == nil {
+		return nil, fmt.Errorf("storage size for prebuild is not configured")
+	}
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prebuildPVCName(prebuild.Name),
+			Namespace: prebuild.Namespace,
+			Labels:    prebuildLabels(prebuild),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			// Prebuilds are mounted by prebuild jobs and starting workspaces at the same time, which may run on
+			// different nodes
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteMany,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					"storage": *size,
+				},
+			},
+			StorageClassName: config.Workspace.StorageClassName,
+		},
+	}, nil
+}
+
+func getSpecPrebuildMetadataConfigMap(prebuild *controllerv1alpha1.DevWorkspacePrebuild, flattened *dw.DevWorkspaceTemplateSpec) (*corev1.ConfigMap, error) {
+	flattenedYaml, err := yaml.Marshal(flattened)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal flattened DevWorkspaceTemplate yaml: %w", err)
+	}
+	cmLabels := prebuildLabels(prebuild)
+	cmLabels[constants.DevWorkspaceWatchConfigMapLabel] = "true"
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prebuildMetadataConfigMapName(prebuild.Name),
+			Namespace: prebuild.Namespace,
+			Labels:    cmLabels,
+		},
+		Data: map[string]string{
+			flattenedYamlFilename: string(flattenedYaml),
+		},
+	}, nil
+}
+
+// getSpecPrebuildJobTemplate returns the template for prebuild jobs. Prebuild jobs clone projects into a new subpath of
+// the prebuild PVC using the project-clone init container, run the DevWorkspaceTemplate's preStart commands on the
+// cloned projects, and finally remove outdated prebuild runs from the PVC.
+func getSpecPrebuildJobTemplate(
+	prebuild *controllerv1alpha1.DevWorkspacePrebuild,
+	flattened *dw.DevWorkspaceTemplateSpec,
+	clusterAPI sync.ClusterAPI) (*batchv1.JobTemplateSpec, error) {
+
+	cloneContainer, err := projects.GetProjectCloneInitContainer(flattened)
+	if err != nil {
+		return nil, err
+	}
+	if cloneContainer == nil {
+		return nil, fmt.Errorf("DevWorkspaceTemplate %s does not define any projects to prebuild", prebuild.Spec.Template)
+	}
+	cloneContainer.Env = append(cloneContainer.Env, corev1.EnvVar{
+		Name:  metadata.FlattenedDevfileMountPathEnvVar,
+		Value: path.Join(prebuildMetadataMountPath, flattenedYamlFilename),
+	})
+	cloneContainer.VolumeMounts = append(cloneContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      prebuildMetadataVolumeName,
+		ReadOnly:  true,
+		MountPath: prebuildMetadataMountPath,
+	})
+
+	devfilePodAdditions, err := containerlib.GetKubeContainersFromDevfile(flattened)
+	if err != nil {
+		return nil, err
+	}
+
+	initContainers := append([]corev1.Container{*cloneContainer}, devfilePodAdditions.InitContainers...)
+	for idx := range initContainers {
+		container := &initContainers[idx]
+		container.Env = append(container.Env, getPrebuildRunEnvVar())
+		for vmIdx, vm := range container.VolumeMounts {
+			if vm.Name == devfileConstants.ProjectsVolumeName {
+				container.VolumeMounts[vmIdx].Name = prebuildStorageVolumeName
+				container.VolumeMounts[vmIdx].SubPathExpr = fmt.Sprintf("$(%s)", prebuildRunEnvVar)
+			}
+		}
+	}
+
+	defaultMode := int32(0644)
+	volumes := []corev1.Volume{
+		{
+			Name: prebuildStorageVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: prebuildPVCName(prebuild.Name),
+				},
+			},
+		},
+		{
+			Name: prebuildMetadataVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: prebuildMetadataConfigMapName(prebuild.Name),
+					},
+					DefaultMode: &defaultMode,
+				},
+			},
+		},
+	}
+	// Other volumes used by preStart commands are not part of the prebuild
+	for _, component := range flattened.Components {
+		if component.Volume == nil || component.Name == devfileConstants.ProjectsVolumeName {
+			continue
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name: component.Name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	cleanupContainer := corev1.Container{
+		Name:    prebuildCleanupContainerName,
+		Image:   images.GetPVCCleanupJobImage(),
+		Command: []string{"/bin/sh"},
+		Args:    []string{"-c", prebuildCleanupCommand, prebuildStorageMountPath},
+		Env: []corev1.EnvVar{
+			getPrebuildRunEnvVar(),
+			{
+				Name:  prebuildKeepEnvVar,
+				Value: prebuild.Status.LatestPrebuild,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: prebuildCleanupMemoryRequest,
+				corev1.ResourceCPU:    prebuildCleanupCPURequest,
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: prebuildCleanupMemoryLimit,
+				corev1.ResourceCPU:    prebuildCleanupCPULimit,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      prebuildStorageVolumeName,
+				MountPath: prebuildStorageMountPath,
+			},
+		},
+	}
+
+	// DevWorkspaceIDLabel is required for jobs to be visible in the controller's cache
+	jobLabels := prebuildLabels(prebuild)
+	jobLabels[constants.DevWorkspaceIDLabel] = prebuild.Name
+
+	jobTemplate := &batchv1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &prebuildJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: prebuildLabels(prebuild),
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:   corev1.RestartPolicyNever,
+					SecurityContext: wsprovision.GetDevWorkspaceSecurityContext(),
+					InitContainers:  initContainers,
+					Containers:      []corev1.Container{cleanupContainer},
+					Volumes:         volumes,
+				},
+			},
+		},
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(prebuild.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(podTolerations) > 0 {
+		jobTemplate.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		jobTemplate.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+
+	return jobTemplate, nil
+}
+
+func getSpecPrebuildCronJob(prebuild *controllerv1alpha1.DevWorkspacePrebuild, jobTemplate *batchv1.JobTemplateSpec) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      prebuild.Name,
+			Namespace: prebuild.Namespace,
+			Labels:    prebuildLabels(prebuild),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   prebuild.Spec.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &prebuildJobHistoryLimit,
+			FailedJobsHistoryLimit:     &prebuildJobHistoryLimit,
+			JobTemplate:                *jobTemplate,
+		},
+	}
+}
+
+func getSpecPrebuildJob(prebuild *controllerv1alpha1.DevWorkspacePrebuild, jobTemplate *batchv1.JobTemplateSpec) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", prebuild.Name),
+			Namespace:    prebuild.Namespace,
+			Labels:       jobTemplate.Labels,
+		},
+		Spec: jobTemplate.Spec,
+	}
+}
+
+func getPrebuildRunEnvVar() corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: prebuildRunEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: "metadata.labels['job-name']",
+			},
+		},
+	}
+}
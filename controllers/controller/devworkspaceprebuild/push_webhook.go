@@ -0,0 +1,382 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceprebuild
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// PushWebhookPath is the path on which push events from git providers are received.
+	PushWebhookPath = "/prebuilds/push"
+	// pushWebhookTokenKey is the key in a DevWorkspacePrebuild's git push trigger secret that holds the token used
+	// to verify push events.
+	pushWebhookTokenKey = "token"
+	// maxPushEventSize is the maximum size of push event payloads that are processed. GitHub caps payloads at 25MB,
+	// but only the repository and ref fields are required here.
+	maxPushEventSize = 5 * 1024 * 1024
+
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	gitlabEventHeader     = "X-Gitlab-Event"
+	gitlabTokenHeader     = "X-Gitlab-Token"
+
+	githubPushEvent = "push"
+	gitlabPushEvent = "Push Hook"
+)
+
+// pushEvent contains the fields of GitHub and GitLab push event payloads that are used to find matching
+// DevWorkspacePrebuilds.
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Repository struct {
+		// GitHub fields
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		GitURL        string `json:"git_url"`
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+		// GitLab fields
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+	} `json:"repository"`
+	// GitLab only
+	Project struct {
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		WebURL        string `json:"web_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+}
+
+// PushWebhookHandler triggers DevWorkspacePrebuilds when changes are pushed to the git repositories of their
+// DevWorkspaceTemplate's projects. Push events sent by GitHub and GitLab are supported. A DevWorkspacePrebuild is
+// only triggered if it enables the git push trigger and the event is verified using the token in the secret it
+// refers to.
+type PushWebhookHandler struct {
+	Client client.Client
+	// APIReader is used to read git push trigger secrets, which are not cached by the controller.
+	APIReader client.Reader
+	Log       logr.Logger
+}
+
+func (h *PushWebhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxPushEventSize+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPushEventSize {
+		http.Error(w, "request body is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var verify func(token string) bool
+	switch {
+	case req.Header.Get(githubEventHeader) != "":
+		if req.Header.Get(githubEventHeader) != githubPushEvent {
+			// Other events (e.g. the 'ping' event sent when a webhook is created) are acknowledged but ignored
+			fmt.Fprintf(w, "Ignoring GitHub event %q", req.Header.Get(githubEventHeader))
+			return
+		}
+		signature := req.Header.Get(githubSignatureHeader)
+		verify = func(token string) bool {
+			return verifyGitHubSignature(body, signature, token)
+		}
+	case req.Header.Get(gitlabEventHeader) != "":
+		if req.Header.Get(gitlabEventHeader) != gitlabPushEvent {
+			fmt.Fprintf(w, "Ignoring GitLab event %q", req.Header.Get(gitlabEventHeader))
+			return
+		}
+		eventToken := req.Header.Get(gitlabTokenHeader)
+		verify = func(token string) bool {
+			return hmac.Equal([]byte(eventToken), []byte(token))
+		}
+	default:
+		http.Error(w, "unsupported event: only GitHub and GitLab push events are supported", http.StatusBadRequest)
+		return
+	}
+
+	event := &pushEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse push event: %s", err), http.StatusBadRequest)
+		return
+	}
+	branch := strings.TrimPrefix(event.Ref, "refs/heads/")
+	if branch == event.Ref || isDeletedRevision(event.After) {
+		fmt.Fprintf(w, "Ignoring push event for %q", event.Ref)
+		return
+	}
+
+	triggered, err := h.triggerPrebuilds(req.Context(), event, branch, verify)
+	if err != nil {
+		h.Log.Error(err, "Failed to process push event")
+		http.Error(w, "failed to process push event", http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(w, "Triggered %d DevWorkspacePrebuilds", triggered)
+}
+
+// triggerPrebuilds sets the prebuild trigger annotation on all DevWorkspacePrebuilds that have a project matching the
+// push event and a token that verifies the event. Returns the number of DevWorkspacePrebuilds that were triggered.
+func (h *PushWebhookHandler) triggerPrebuilds(ctx context.Context, event *pushEvent, branch string, verify func(token string) bool) (int, error) {
+	prebuilds := &controllerv1alpha1.DevWorkspacePrebuildList{}
+	if err := h.Client.List(ctx, prebuilds); err != nil {
+		return 0, err
+	}
+	eventURLs := event.repositoryURLs()
+	defaultBranch := event.defaultBranch()
+	triggerValue := event.After
+	if triggerValue == "" {
+		triggerValue = time.Now().Format(time.RFC3339)
+	}
+
+	triggered := 0
+	for idx := range prebuilds.Items {
+		prebuild := &prebuilds.Items[idx]
+		if prebuild.Spec.GitPushTrigger == nil {
+			continue
+		}
+		log := h.Log.WithValues("namespace", prebuild.Namespace, "name", prebuild.Name)
+		projects, err := h.getPrebuildProjects(ctx, prebuild)
+		if err != nil {
+			log.Error(err, "Failed to read projects for DevWorkspacePrebuild")
+			continue
+		}
+		if !hasMatchingProject(projects, eventURLs, branch, defaultBranch) {
+			continue
+		}
+		token, err := h.getTriggerToken(ctx, prebuild)
+		if err != nil {
+			log.Info(fmt.Sprintf("Ignoring push event for DevWorkspacePrebuild: %s", err))
+			continue
+		}
+		if !verify(token) {
+			log.Info("Ignoring push event for DevWorkspacePrebuild: event could not be verified")
+			continue
+		}
+		if prebuild.Annotations[constants.DevWorkspacePrebuildTriggerAnnotation] == triggerValue {
+			// Event was already delivered
+			continue
+		}
+		patch := client.MergeFrom(prebuild.DeepCopy())
+		if prebuild.Annotations == nil {
+			prebuild.Annotations = map[string]string{}
+		}
+		prebuild.Annotations[constants.DevWorkspacePrebuildTriggerAnnotation] = triggerValue
+		if err := h.Client.Patch(ctx, prebuild, patch); err != nil {
+			return triggered, err
+		}
+		log.Info("Triggered prebuild on git push", "ref", event.Ref, "revision", event.After)
+		triggered++
+	}
+	return triggered, nil
+}
+
+// getPrebuildProjects returns the projects in the flattened DevWorkspaceTemplate stored in a DevWorkspacePrebuild's
+// metadata configmap. Using the flattened template ensures projects defined in parents and plugins are included.
+func (h *PushWebhookHandler) getPrebuildProjects(ctx context.Context, prebuild *controllerv1alpha1.DevWorkspacePrebuild) ([]dw.Project, error) {
+	cm := &corev1.ConfigMap{}
+	cmNamespacedName := types.NamespacedName{Name: prebuildMetadataConfigMapName(prebuild.Name), Namespace: prebuild.Namespace}
+	if err := h.Client.Get(ctx, cmNamespacedName, cm); err != nil {
+		return nil, err
+	}
+	flattened := &dw.DevWorkspaceTemplateSpec{}
+	if err := yaml.Unmarshal([]byte(cm.Data[flattenedYamlFilename]), flattened); err != nil {
+		return nil, fmt.Errorf("failed to read flattened DevWorkspaceTemplate: %w", err)
+	}
+	return flattened.Projects, nil
+}
+
+func (h *PushWebhookHandler) getTriggerToken(ctx context.Context, prebuild *controllerv1alpha1.DevWorkspacePrebuild) (string, error) {
+	secret := &corev1.Secret{}
+	secretNamespacedName := types.NamespacedName{Name: prebuild.Spec.GitPushTrigger.SecretName, Namespace: prebuild.Namespace}
+	if err := h.APIReader.Get(ctx, secretNamespacedName, secret); err != nil {
+		return "", fmt.Errorf("failed to read git push trigger secret %s: %w", secretNamespacedName.Name, err)
+	}
+	token := string(secret.Data[pushWebhookTokenKey])
+	if token == "" {
+		return "", fmt.Errorf("git push trigger secret %s does not define key '%s'", secretNamespacedName.Name, pushWebhookTokenKey)
+	}
+	return token, nil
+}
+
+// hasMatchingProject returns whether any project has a remote in eventURLs and checks out the pushed branch. Projects
+// that do not specify a revision match pushes to the repository's default branch.
+func hasMatchingProject(projects []dw.Project, eventURLs map[string]bool, branch, defaultBranch string) bool {
+	for _, project := range projects {
+		if project.Git == nil {
+			continue
+		}
+		remotes := project.Git.Remotes
+		revision := ""
+		if checkoutFrom := project.Git.CheckoutFrom; checkoutFrom != nil {
+			revision = checkoutFrom.Revision
+			if remoteURL, ok := remotes[checkoutFrom.Remote]; ok {
+				remotes = map[string]string{checkoutFrom.Remote: remoteURL}
+			}
+		}
+		if revision == "" {
+			revision = defaultBranch
+		}
+		if revision != "" && revision != branch {
+			continue
+		}
+		for _, remoteURL := range remotes {
+			if eventURLs[normalizeGitURL(remoteURL)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *pushEvent) repositoryURLs() map[string]bool {
+	urls := map[string]bool{}
+	for _, rawURL := range []string{
+		e.Repository.CloneURL, e.Repository.SSHURL, e.Repository.GitURL, e.Repository.HTMLURL,
+		e.Repository.GitHTTPURL, e.Repository.GitSSHURL,
+		e.Project.GitHTTPURL, e.Project.GitSSHURL, e.Project.WebURL,
+	} {
+		if rawURL != "" {
+			urls[normalizeGitURL(rawURL)] = true
+		}
+	}
+	return urls
+}
+
+func (e *pushEvent) defaultBranch() string {
+	if e.Repository.DefaultBranch != "" {
+		return e.Repository.DefaultBranch
+	}
+	return e.Project.DefaultBranch
+}
+
+// normalizeGitURL converts git remote URLs into a form that can be compared regardless of the protocol used, e.g.
+// 'https://github.com/org/repo.git', 'git@github.com:org/repo' and 'ssh://git@github.com/org/repo' are all converted
+// to 'github.com/org/repo'.
+func normalizeGitURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	var host, repoPath string
+	if strings.Contains(rawURL, "://") {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return strings.ToLower(rawURL)
+		}
+		host, repoPath = parsed.Hostname(), parsed.Path
+	} else if idx := strings.Index(rawURL, ":"); idx > 0 {
+		// scp-like syntax, e.g. git@github.com:org/repo.git
+		host, repoPath = rawURL[:idx], rawURL[idx+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	} else {
+		return strings.ToLower(rawURL)
+	}
+	if splitHost, _, err := net.SplitHostPort(host); err == nil {
+		host = splitHost
+	}
+	repoPath = strings.Trim(repoPath, "/")
+	repoPath = strings.TrimSuffix(repoPath, ".git")
+	return strings.ToLower(host + "/" + repoPath)
+}
+
+// verifyGitHubSignature checks that signature is the HMAC-SHA256 hex digest of body using token as the key, as
+// described in https://docs.github.com/en/developers/webhooks-and-events/webhooks/securing-your-webhooks
+func verifyGitHubSignature(body []byte, signature, token string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// isDeletedRevision returns whether revision is the all-zeros commit ID used by git providers when a branch is deleted.
+func isDeletedRevision(revision string) bool {
+	return revision != "" && strings.Trim(revision, "0") == ""
+}
+
+// PushWebhookServer serves the PushWebhookHandler. It is added to the controller manager as a runnable and runs on
+// all replicas, as receiving push events does not require leader election.
+type PushWebhookServer struct {
+	// Addr is the address the server listens on
+	Addr    string
+	Handler *PushWebhookHandler
+}
+
+func (s *PushWebhookServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(PushWebhookPath, s.Handler)
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		s.Handler.Log.Info("Starting prebuild push webhook server", "addr", s.Addr, "path", PushWebhookPath)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- err
+		}
+		close(errs)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errs:
+		return err
+	}
+}
+
+func (s *PushWebhookServer) NeedLeaderElection() bool {
+	return false
+}
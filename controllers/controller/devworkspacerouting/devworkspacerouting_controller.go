@@ -121,9 +121,10 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 	}
 
 	workspaceMeta := solvers.DevWorkspaceMetadata{
-		DevWorkspaceId: instance.Spec.DevWorkspaceId,
-		Namespace:      instance.Namespace,
-		PodSelector:    instance.Spec.PodSelector,
+		DevWorkspaceId:     instance.Spec.DevWorkspaceId,
+		Namespace:          instance.Namespace,
+		PodSelector:        instance.Spec.PodSelector,
+		ServiceAnnotations: instance.Spec.ServiceAnnotations,
 	}
 
 	restrictedAccess, setRestrictedAccess := instance.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]
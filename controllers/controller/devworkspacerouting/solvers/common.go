@@ -16,8 +16,14 @@
 package solvers
 
 import (
+	"path"
+	"sort"
+	"strings"
+
 	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	maputils "github.com/devfile/devworkspace-operator/internal/map"
 	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 
 	routeV1 "github.com/openshift/api/route/v1"
@@ -27,10 +33,46 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// controllerAnnotationPrefix is the prefix of annotations managed by the DevWorkspace Operator
+const controllerAnnotationPrefix = "controller.devfile.io/"
+
 type DevWorkspaceMetadata struct {
-	DevWorkspaceId string
-	Namespace      string
-	PodSelector    map[string]string
+	DevWorkspaceId     string
+	Namespace          string
+	PodSelector        map[string]string
+	ServiceAnnotations map[string]string
+}
+
+// withEndpointAnnotations returns a copy of annotations merged with the annotations defined on an endpoint. Only
+// endpoint annotations allowed by the operator configuration are applied, and annotations already present in
+// annotations (i.e. set by the controller) cannot be overridden.
+func withEndpointAnnotations(annotations map[string]string, endpoint controllerv1alpha1.Endpoint) map[string]string {
+	merged := map[string]string{}
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	for k, v := range endpoint.Annotations {
+		if _, isSet := annotations[k]; isSet || !isEndpointAnnotationAllowed(k) {
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// isEndpointAnnotationAllowed returns whether an annotation defined on an endpoint matches the allowed endpoint
+// annotations in the operator configuration. Annotations that inject configuration snippets into the ingress
+// controller and annotations used by the DevWorkspace Operator are never allowed.
+func isEndpointAnnotationAllowed(key string) bool {
+	if strings.HasSuffix(key, "-snippet") || strings.HasPrefix(key, controllerAnnotationPrefix) {
+		return false
+	}
+	for _, pattern := range config.Routing.AllowedEndpointAnnotations {
+		if matched, err := path.Match(pattern, key); err == nil && matched {
+			return true
+		}
+	}
+	return false
 }
 
 // GetDiscoverableServicesForEndpoints converts the endpoint list into a set of services, each corresponding to a single discoverable
@@ -60,9 +102,8 @@ func GetDiscoverableServicesForEndpoints(endpoints map[string]controllerv1alpha1
 						Labels: map[string]string{
 							constants.DevWorkspaceIDLabel: meta.DevWorkspaceId,
 						},
-						Annotations: map[string]string{
-							constants.DevWorkspaceDiscoverableServiceAnnotation: "true",
-						},
+						Annotations: maputils.Append(withEndpointAnnotations(nil, endpoint),
+							constants.DevWorkspaceDiscoverableServiceAnnotation, "true"),
 					},
 					Spec: corev1.ServiceSpec{
 						Ports:    []corev1.ServicePort{servicePort},
@@ -76,6 +117,35 @@ func GetDiscoverableServicesForEndpoints(endpoints map[string]controllerv1alpha1
 	return services
 }
 
+// getServiceAnnotationsForEndpoints returns the annotations for the service exposing endpoints with the given exposure
+// types: the service annotations from meta and the allowed annotations defined on exposed endpoints. Annotations from
+// meta take precedence; if endpoints define conflicting annotations, the first endpoint (ordered by component name)
+// takes precedence.
+func getServiceAnnotationsForEndpoints(endpoints map[string]controllerv1alpha1.EndpointList, meta DevWorkspaceMetadata,
+	includeDiscoverable bool, validExposures map[controllerv1alpha1.EndpointExposure]bool) map[string]string {
+	annotations := withEndpointAnnotations(meta.ServiceAnnotations, controllerv1alpha1.Endpoint{})
+	var componentNames []string
+	for componentName := range endpoints {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+	for _, componentName := range componentNames {
+		for _, endpoint := range endpoints[componentName] {
+			if !validExposures[endpoint.Exposure] {
+				continue
+			}
+			if !includeDiscoverable && endpoint.Attributes.GetBoolean(string(controllerv1alpha1.DiscoverableAttribute), nil) {
+				continue
+			}
+			annotations = withEndpointAnnotations(annotations, endpoint)
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
 // GetServiceForEndpoints returns a single service that exposes all endpoints of given exposure types, possibly also including the discoverable types.
 // `nil` is returned if the service would expose no ports satisfying the provided criteria.
 func GetServiceForEndpoints(endpoints map[string]controllerv1alpha1.EndpointList, meta DevWorkspaceMetadata, includeDiscoverable bool, exposureType ...controllerv1alpha1.EndpointExposure) *corev1.Service {
@@ -122,6 +192,8 @@ func GetServiceForEndpoints(endpoints map[string]controllerv1alpha1.EndpointList
 		return nil
 	}
 
+	annotations := getServiceAnnotationsForEndpoints(endpoints, meta, includeDiscoverable, validExposures)
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      common.ServiceName(meta.DevWorkspaceId),
@@ -129,6 +201,7 @@ func GetServiceForEndpoints(endpoints map[string]controllerv1alpha1.EndpointList
 			Labels: map[string]string{
 				constants.DevWorkspaceIDLabel: meta.DevWorkspaceId,
 			},
+			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
 			Selector: meta.PodSelector,
@@ -189,7 +262,7 @@ func getRouteForEndpoint(routingSuffix string, endpoint controllerv1alpha1.Endpo
 			Labels: map[string]string{
 				constants.DevWorkspaceIDLabel: meta.DevWorkspaceId,
 			},
-			Annotations: routeAnnotations(endpointName),
+			Annotations: withEndpointAnnotations(routeAnnotations(endpointName), endpoint),
 		},
 		Spec: routeV1.RouteSpec{
 			Host: common.WorkspaceHostname(routingSuffix, meta.DevWorkspaceId),
@@ -220,7 +293,7 @@ func getIngressForEndpoint(routingSuffix string, endpoint controllerv1alpha1.End
 			Labels: map[string]string{
 				constants.DevWorkspaceIDLabel: meta.DevWorkspaceId,
 			},
-			Annotations: nginxIngressAnnotations(endpoint.Name),
+			Annotations: withEndpointAnnotations(nginxIngressAnnotations(endpoint.Name), endpoint),
 		},
 		Spec: networkingv1.IngressSpec{
 			Rules: []networkingv1.IngressRule{
@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package solvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+var testMeta = DevWorkspaceMetadata{
+	DevWorkspaceId: "test-id",
+	Namespace:      "test-namespace",
+	PodSelector:    map[string]string{constants.DevWorkspaceIDLabel: "test-id"},
+}
+
+func TestEndpointAnnotationsAreAppliedToIngressAndRoute(t *testing.T) {
+	config.SetConfigForTesting(nil)
+	endpoint := controllerv1alpha1.Endpoint{
+		Name:       "test-endpoint",
+		TargetPort: 8080,
+		Exposure:   controllerv1alpha1.PublicEndpointExposure,
+		Annotations: map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-body-size": "50m",
+			"nginx.ingress.kubernetes.io/rewrite-target":  "/custom",
+			constants.DevWorkspaceEndpointNameAnnotation:  "other-endpoint",
+		},
+	}
+
+	ingress := getIngressForEndpoint("test-suffix", endpoint, testMeta)
+	assert.Equal(t, "50m", ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.Equal(t, "/", ingress.Annotations["nginx.ingress.kubernetes.io/rewrite-target"],
+		"Endpoint annotations should not override controller annotations")
+	assert.Equal(t, "nginx", ingress.Annotations["kubernetes.io/ingress.class"])
+	assert.Equal(t, "test-endpoint", ingress.Annotations[constants.DevWorkspaceEndpointNameAnnotation],
+		"Endpoint name annotation should not be overridden")
+
+	route := getRouteForEndpoint("test-suffix", endpoint, testMeta)
+	assert.Equal(t, "50m", route.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.Equal(t, "test-endpoint", route.Annotations[constants.DevWorkspaceEndpointNameAnnotation],
+		"Endpoint name annotation should not be overridden")
+}
+
+func TestEndpointAnnotationsAreFiltered(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		key      string
+		expected bool
+	}{
+		{
+			name:     "Allows annotations matching default allowlist",
+			key:      "nginx.ingress.kubernetes.io/proxy-read-timeout",
+			expected: true,
+		},
+		{
+			name:     "Denies annotations not in default allowlist",
+			key:      "nginx.ingress.kubernetes.io/auth-url",
+			expected: false,
+		},
+		{
+			name:     "Allows annotations in configured allowlist",
+			allowed:  []string{"example.com/*"},
+			key:      "example.com/owner",
+			expected: true,
+		},
+		{
+			name:     "Denies annotations not in configured allowlist",
+			allowed:  []string{"example.com/*"},
+			key:      "nginx.ingress.kubernetes.io/proxy-read-timeout",
+			expected: false,
+		},
+		{
+			name:     "Denies snippet annotations",
+			allowed:  []string{"nginx.ingress.kubernetes.io/*"},
+			key:      "nginx.ingress.kubernetes.io/configuration-snippet",
+			expected: false,
+		},
+		{
+			name:     "Denies controller annotations",
+			allowed:  []string{"*/*"},
+			key:      constants.DevWorkspaceDiscoverableServiceAnnotation,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var testConfig *controllerv1alpha1.OperatorConfiguration
+			if tt.allowed != nil {
+				testConfig = &controllerv1alpha1.OperatorConfiguration{
+					Routing: &controllerv1alpha1.RoutingConfig{AllowedEndpointAnnotations: tt.allowed},
+				}
+			}
+			config.SetConfigForTesting(testConfig)
+			endpoint := controllerv1alpha1.Endpoint{
+				Name:        "test-endpoint",
+				TargetPort:  8080,
+				Exposure:    controllerv1alpha1.PublicEndpointExposure,
+				Annotations: map[string]string{tt.key: "test"},
+			}
+			ingress := getIngressForEndpoint("test-suffix", endpoint, testMeta)
+			_, applied := ingress.Annotations[tt.key]
+			assert.Equal(t, tt.expected, applied)
+		})
+	}
+	config.SetConfigForTesting(nil)
+}
+
+func TestServiceAnnotations(t *testing.T) {
+	config.SetConfigForTesting(nil)
+	endpoints := map[string]controllerv1alpha1.EndpointList{
+		"test-component": {
+			{
+				Name:       "discoverable-endpoint",
+				TargetPort: 8080,
+				Exposure:   controllerv1alpha1.InternalEndpointExposure,
+				Attributes: controllerv1alpha1.Attributes{}.PutBoolean(string(controllerv1alpha1.DiscoverableAttribute), true),
+				Annotations: map[string]string{
+					"nginx.ingress.kubernetes.io/proxy-body-size": "50m",
+					"service-annotation":                          "from-endpoint",
+					"not-allowed":                                 "test",
+				},
+			},
+		},
+	}
+	meta := testMeta
+	meta.ServiceAnnotations = map[string]string{"service-annotation": "test"}
+
+	service := GetServiceForEndpoints(endpoints, meta, true, controllerv1alpha1.InternalEndpointExposure)
+	if !assert.NotNil(t, service) {
+		return
+	}
+	assert.Equal(t, map[string]string{
+		"service-annotation":                          "test",
+		"nginx.ingress.kubernetes.io/proxy-body-size": "50m",
+	}, service.Annotations, "Should apply service annotations and allowed endpoint annotations")
+
+	service = GetServiceForEndpoints(endpoints, meta, false, controllerv1alpha1.InternalEndpointExposure)
+	assert.Nil(t, service, "Should not expose discoverable endpoints")
+
+	discoverable := GetDiscoverableServicesForEndpoints(endpoints, meta)
+	if !assert.Len(t, discoverable, 1) {
+		return
+	}
+	assert.Equal(t, "50m", discoverable[0].Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	assert.NotContains(t, discoverable[0].Annotations, "not-allowed")
+	assert.Equal(t, "true", discoverable[0].Annotations[constants.DevWorkspaceDiscoverableServiceAnnotation])
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -36,7 +37,7 @@ func (r *DevWorkspaceRoutingReconciler) syncServices(routing *controllerv1alpha1
 		return false, nil, err
 	}
 
-	toDelete := getServicesToDelete(clusterServices, specServices)
+	toDelete := getServicesToDelete(routing, clusterServices, specServices)
 	for _, service := range toDelete {
 		err := r.Delete(context.TODO(), &service)
 		if err != nil {
@@ -89,9 +90,14 @@ func (r *DevWorkspaceRoutingReconciler) getClusterServices(routing *controllerv1
 	return found.Items, nil
 }
 
-func getServicesToDelete(clusterServices, specServices []corev1.Service) []corev1.Service {
+// getServicesToDelete returns the services owned by the routing that are not in specServices. Services for the same
+// DevWorkspace that are not owned by the routing (e.g. services for dedicated pods) are ignored.
+func getServicesToDelete(routing *controllerv1alpha1.DevWorkspaceRouting, clusterServices, specServices []corev1.Service) []corev1.Service {
 	var toDelete []corev1.Service
 	for _, clusterService := range clusterServices {
+		if !metav1.IsControlledBy(&clusterService, routing) {
+			continue
+		}
 		if contains, _ := listContainsByName(clusterService, specServices); !contains {
 			toDelete = append(toDelete, clusterService)
 		}
@@ -19,6 +19,7 @@ import (
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -72,6 +73,18 @@ func (r *DevWorkspaceReconciler) deleteWorkspaceOwnedObjects(ctx context.Context
 		return false, err
 	}
 	for _, cm := range cmList.Items {
+		if cm.Name == common.FailedStartLogsConfigMapName(workspace.Status.DevWorkspaceId) {
+			// Logs of a failed start are kept so that they are available after the workspace is stopped
+			continue
+		}
+		if cm.Name == common.ContainerStatusConfigMapName(workspace.Status.DevWorkspaceId) {
+			// The state of containers is kept to preserve it when a workspace fails to start
+			continue
+		}
+		if cm.Name == common.PinnedImportsConfigMapName(workspace.Status.DevWorkspaceId) {
+			// Pinned content is reused when the workspace is started again
+			continue
+		}
 		didDelete = true
 		if err := deleteObj(&cm); err != nil {
 			return false, err
@@ -89,6 +102,17 @@ func (r *DevWorkspaceReconciler) deleteWorkspaceOwnedObjects(ctx context.Context
 		}
 	}
 
+	serviceList := &corev1.ServiceList{}
+	if err := r.Client.List(ctx, serviceList, listOptions); err != nil {
+		return false, err
+	}
+	for _, service := range serviceList.Items {
+		didDelete = true
+		if err := deleteObj(&service); err != nil {
+			return false, err
+		}
+	}
+
 	routingList := &controllerv1alpha1.DevWorkspaceRoutingList{}
 	if err := r.Client.List(ctx, routingList, listOptions); err != nil {
 		return false, err
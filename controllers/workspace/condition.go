@@ -16,6 +16,8 @@
 package controllers
 
 import (
+	"fmt"
+
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
 
@@ -29,7 +31,9 @@ var conditionOrder = []dw.DevWorkspaceConditionType{
 	dw.DevWorkspaceRoutingReady,
 	dw.DevWorkspaceServiceAccountReady,
 	conditions.PullSecretsReady,
+	conditions.ImageBuildReady,
 	conditions.DeploymentReady,
+	conditions.EndpointsReady,
 	dw.DevWorkspaceReady,
 }
 
@@ -54,6 +58,15 @@ func (c *workspaceConditions) setConditionTrueWithReason(conditionType dw.DevWor
 	}
 }
 
+// addWarning sets the DevWorkspaceWarning condition to true, appending msg to the condition's message if
+// warnings are already present.
+func (c *workspaceConditions) addWarning(msg string) {
+	if existing, ok := c.conditions[conditions.DevWorkspaceWarning]; ok && existing.Status == corev1.ConditionTrue {
+		msg = fmt.Sprintf("%s; %s", existing.Message, msg)
+	}
+	c.setConditionTrue(conditions.DevWorkspaceWarning, msg)
+}
+
 func (c *workspaceConditions) setCondition(conditionType dw.DevWorkspaceConditionType, dwCondition dw.DevWorkspaceCondition) {
 	if c.conditions == nil {
 		c.conditions = map[dw.DevWorkspaceConditionType]dw.DevWorkspaceCondition{}
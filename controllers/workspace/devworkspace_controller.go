@@ -35,7 +35,10 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
 	"github.com/devfile/devworkspace-operator/pkg/library/projects"
 	"github.com/devfile/devworkspace-operator/pkg/provision/automount"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/imagebuild"
 	"github.com/devfile/devworkspace-operator/pkg/provision/metadata"
+	"github.com/devfile/devworkspace-operator/pkg/provision/prebuild"
 	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
@@ -50,6 +53,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -69,8 +74,11 @@ const (
 type DevWorkspaceReconciler struct {
 	client.Client
 	NonCachingClient client.Client
+	Clientset        kubernetes.Interface
 	Log              logr.Logger
 	Scheme           *runtime.Scheme
+	// Recorder is used to record events for DevWorkspace lifecycle transitions
+	Recorder record.EventRecorder
 }
 
 /////// CRD-related RBAC roles
@@ -81,6 +89,8 @@ type DevWorkspaceReconciler struct {
 // +kubebuilder:rbac:groups=apps;extensions,resources=deployments;replicasets,verbs=*
 // +kubebuilder:rbac:groups="",resources=pods;serviceaccounts;secrets;configmaps;persistentvolumeclaims,verbs=*
 // +kubebuilder:rbac:groups="",resources=namespaces;events,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;create;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews;localsubjectaccessreviews,verbs=create
@@ -102,6 +112,7 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	clusterAPI := sync.ClusterAPI{
 		Client:           r.Client,
 		NonCachingClient: r.NonCachingClient,
+		Clientset:        r.Clientset,
 		Scheme:           r.Scheme,
 		Logger:           reqLogger,
 		Ctx:              ctx,
@@ -176,6 +187,13 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// If this is the first reconcile for a starting workspace, mark it as starting now. This is done outside the regular
 	// updateWorkspaceStatus function to ensure it gets set immediately
 	if workspace.Status.Phase != dw.DevWorkspaceStatusStarting && workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+		// Logs from a previous failed start are no longer relevant once the workspace is restarted
+		if err := wsprovision.DeleteFailedStartLogs(workspace, clusterAPI); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := wsprovision.DeleteContainerStatus(workspace, clusterAPI); err != nil {
+			return reconcile.Result{}, err
+		}
 		// Set 'Started' condition as early as possible to get accurate timing metrics
 		workspace.Status.Phase = dw.DevWorkspaceStatusStarting
 		workspace.Status.Message = "Initializing DevWorkspace"
@@ -190,6 +208,7 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		err = r.Status().Update(ctx, workspace)
 		if err == nil {
 			metrics.WorkspaceStarted(workspace, reqLogger)
+			r.Recorder.Event(workspace, corev1.EventTypeNormal, string(dw.DevWorkspaceStatusStarting), "DevWorkspace is starting")
 		}
 		return reconcile.Result{}, err
 	}
@@ -208,7 +227,7 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		// encountered in main reconcile loop.
 		if err == nil {
 			if timeoutErr := checkForStartTimeout(clusterWorkspace); timeoutErr != nil {
-				reconcileResult, err = r.failWorkspace(workspace, timeoutErr.Error(), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
+				reconcileResult, err = r.failWorkspace(workspace, timeoutErr.Error(), metrics.ReasonTimeout, reqLogger, &reconcileStatus)
 			}
 		}
 		if reconcileStatus.phase == dw.DevWorkspaceStatusRunning {
@@ -233,11 +252,27 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	timing.SetTime(timingInfo, timing.ComponentsCreated)
+	recordedImports, err := metadata.GetResolvedImports(clusterWorkspace, clusterAPI)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	importPinning, err := getImportPinning(clusterWorkspace)
+	if err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Error reading pinned plugins: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
+	pinnedImports := getPinnedImports(clusterWorkspace, importPinning, recordedImports)
+	devfileVariables, err := nsconfig.GetDevfileVariables(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Error reading devfile variables: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
+	resolvedImports := &flatten.ResolvedImports{Pinned: pinnedImports}
 	flattenHelpers := flatten.ResolverTools{
 		WorkspaceNamespace: workspace.Namespace,
 		Context:            ctx,
 		K8sClient:          r.Client,
-		HttpClient:         httpClient,
+		HttpClient:         registryClient,
+		ResolvedImports:    resolvedImports,
+		DefaultVariables:   devfileVariables,
 	}
 
 	flattenedWorkspace, warnings, err := flatten.ResolveDevWorkspace(&workspace.Spec.Template, flattenHelpers)
@@ -249,6 +284,10 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	} else {
 		reconcileStatus.setConditionFalse(conditions.DevWorkspaceWarning, "No warnings in processing DevWorkspace")
 	}
+	setTemplateUpdateCondition(&reconcileStatus, importPinning, resolvedImports.Outdated)
+	if err := wsprovision.CheckImportedPodOverrides(&clusterWorkspace.Spec.Template, flattenedWorkspace); err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Error processing devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
 	workspace.Spec.Template = *flattenedWorkspace
 	reconcileStatus.setConditionTrue(conditions.DevWorkspaceResolved, "Resolved plugins and parents from DevWorkspace")
 
@@ -279,44 +318,75 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Error processing devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
 	}
+	dedicatedPodAdditions, err := containerlib.GetDedicatedPodContainersFromDevfile(&workspace.Spec.Template)
+	if err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Error processing devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
+	if len(dedicatedPodAdditions) > 0 && storageProvisioner.NeedsStorage(&workspace.Spec.Template) &&
+		workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil) == constants.AsyncStorageClassType {
+		return r.failWorkspace(workspace, "Container components with dedicatedPod set are not supported with async storage", metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
+	// Containers in dedicated pods are processed in the same way as containers in the main workspace pod
+	componentPodAdditions := []*controllerv1alpha1.PodAdditions{devfilePodAdditions}
+	for idx := range dedicatedPodAdditions {
+		componentPodAdditions = append(componentPodAdditions, &dedicatedPodAdditions[idx])
+	}
+	for _, podAdditions := range componentPodAdditions {
+		for _, warning := range containerlib.ApplyResourceBounds(podAdditions, config.Workspace.ContainerResourceBounds) {
+			reconcileStatus.addWarning(warning)
+		}
 
-	// Add common environment variables and env vars defined via workspaceEnv attribute
-	if err := env.AddCommonEnvironmentVariables(devfilePodAdditions, clusterWorkspace, &workspace.Spec.Template); err != nil {
-		return r.failWorkspace(workspace, fmt.Sprintf("Failed to process workspace environment variables: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+		// Add common environment variables and env vars defined via workspaceEnv attribute
+		if err := env.AddCommonEnvironmentVariables(podAdditions, clusterWorkspace, &workspace.Spec.Template); err != nil {
+			return r.failWorkspace(workspace, fmt.Sprintf("Failed to process workspace environment variables: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+		}
+	}
+
+	if _, err := projects.GetStarterProject(&workspace.Spec.Template); err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Invalid starter project: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
 	}
 
 	// Add init container to clone projects
 	if projectClone, err := projects.GetProjectCloneInitContainer(&workspace.Spec.Template); err != nil {
-		return r.failWorkspace(workspace, fmt.Sprintf("Failed to set up project-clone init container: %s", err), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
+		return r.failWorkspace(workspace, fmt.Sprintf("Failed to set up project-clone init container: %s", err), metrics.ReasonCloneFailure, reqLogger, &reconcileStatus)
 	} else if projectClone != nil {
 		devfilePodAdditions.InitContainers = append(devfilePodAdditions.InitContainers, *projectClone)
 	}
 
 	// Add automount resources into devfile containers
-	if err := automount.ProvisionAutoMountResourcesInto(devfilePodAdditions, clusterAPI, workspace.Namespace); err != nil {
-		var autoMountErr *automount.AutoMountError
-		if errors.As(err, &autoMountErr) {
-			if autoMountErr.IsFatal {
-				return r.failWorkspace(workspace, fmt.Sprintf("Failed to process automount resources: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	for _, podAdditions := range componentPodAdditions {
+		if err := automount.ProvisionAutoMountResourcesInto(podAdditions, clusterAPI, workspace.Namespace); err != nil {
+			var autoMountErr *automount.AutoMountError
+			if errors.As(err, &autoMountErr) {
+				if autoMountErr.IsFatal {
+					return r.failWorkspace(workspace, fmt.Sprintf("Failed to process automount resources: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+				}
+				reqLogger.Info(autoMountErr.Error())
+				return reconcile.Result{Requeue: true}, nil
+			} else {
+				return reconcile.Result{}, err
 			}
-			reqLogger.Info(autoMountErr.Error())
-			return reconcile.Result{Requeue: true}, nil
-		} else {
-			return reconcile.Result{}, err
 		}
 	}
 
-	err = storageProvisioner.ProvisionStorage(devfilePodAdditions, workspace, clusterAPI)
-	if err != nil {
-		switch storageErr := err.(type) {
-		case *storage.NotReadyError:
-			reqLogger.Info(storageErr.Message)
-			reconcileStatus.setConditionFalse(conditions.StorageReady, fmt.Sprintf("Provisioning storage: %s", storageErr.Message))
-			return reconcile.Result{Requeue: true, RequeueAfter: storageErr.RequeueAfter}, nil
-		case *storage.ProvisioningError:
-			return r.failWorkspace(workspace, fmt.Sprintf("Error provisioning storage: %s", storageErr), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
-		default:
-			return reconcile.Result{}, storageErr
+	// Use a prebuild for imported DevWorkspaceTemplates, if available, when setting up projects on first start
+	if err := prebuild.ProvisionPrebuildInto(devfilePodAdditions, clusterWorkspace, clusterAPI); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, podAdditions := range componentPodAdditions {
+		err = storageProvisioner.ProvisionStorage(podAdditions, workspace, clusterAPI)
+		if err != nil {
+			switch storageErr := err.(type) {
+			case *storage.NotReadyError:
+				reqLogger.Info(storageErr.Message)
+				reconcileStatus.setConditionFalse(conditions.StorageReady, fmt.Sprintf("Provisioning storage: %s", storageErr.Message))
+				return reconcile.Result{Requeue: true, RequeueAfter: storageErr.RequeueAfter}, nil
+			case *storage.ProvisioningError:
+				return r.failWorkspace(workspace, fmt.Sprintf("Error provisioning storage: %s", storageErr), metrics.ReasonStorageFailure, reqLogger, &reconcileStatus)
+			default:
+				return reconcile.Result{}, storageErr
+			}
 		}
 	}
 	reconcileStatus.setConditionTrue(conditions.StorageReady, "Storage ready")
@@ -333,7 +403,7 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	routingStatus := wsprovision.SyncRoutingToCluster(workspace, clusterAPI)
 	if !routingStatus.Continue {
 		if routingStatus.FailStartup {
-			return r.failWorkspace(workspace, routingStatus.Message, metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
+			return r.failWorkspace(workspace, routingStatus.Message, metrics.ReasonRoutingFailure, reqLogger, &reconcileStatus)
 		}
 		reqLogger.Info("Waiting on routing to be ready")
 		message := "Preparing networking"
@@ -359,9 +429,16 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	annotate.AddURLAttributesToEndpoints(&workspace.Spec.Template, routingStatus.ExposedEndpoints)
+	healthChecks, err := getEndpointHealthChecks(workspace, routingStatus.ExposedEndpoints)
+	if err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Error processing endpoint health checks: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
 
 	// Step three: provision a configmap on the cluster to mount the flattened devfile in deployment containers
-	err = metadata.ProvisionWorkspaceMetadata(devfilePodAdditions, clusterWorkspace, workspace, clusterAPI)
+	err = metadata.ProvisionWorkspaceMetadata(devfilePodAdditions, clusterWorkspace, workspace, resolvedImports.Resolved, routingStatus.ExposedEndpoints, clusterAPI)
+	if err == nil {
+		err = metadata.SyncResolvedImports(clusterWorkspace, resolvedImports.Resolved, importPinning.filter(resolvedImports.Resolved), clusterAPI)
+	}
 	if err != nil {
 		switch provisionErr := err.(type) {
 		case *metadata.NotReadyError:
@@ -417,33 +494,101 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{Requeue: pullSecretStatus.Requeue}, pullSecretStatus.Err
 	}
 	allPodAdditions = append(allPodAdditions, pullSecretStatus.PodAdditions)
+	for idx := range dedicatedPodAdditions {
+		dedicatedPodAdditions[idx].PullSecrets = append(dedicatedPodAdditions[idx].PullSecrets, pullSecretStatus.PodAdditions.PullSecrets...)
+	}
 	reconcileStatus.setConditionTrue(conditions.PullSecretsReady, "DevWorkspace secrets ready")
 
+	// Build image components used by the workspace and use the resulting images in workspace containers
+	imageComponents, err := imagebuild.GetImageComponents(&workspace.Spec.Template)
+	if err != nil {
+		return r.failWorkspace(workspace, fmt.Sprintf("Invalid image component: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus)
+	}
+	if len(imageComponents) > 0 {
+		builtImages, err := imagebuild.BuildImages(workspace, imageComponents, allPodAdditions, serviceAcctName, clusterAPI)
+		if err != nil {
+			switch buildErr := err.(type) {
+			case *imagebuild.NotReadyError:
+				reqLogger.Info(buildErr.Message)
+				reconcileStatus.setConditionFalse(conditions.ImageBuildReady, buildErr.Message)
+				return reconcile.Result{Requeue: true, RequeueAfter: buildErr.RequeueAfter}, nil
+			case *imagebuild.ProvisioningError:
+				return r.failWorkspace(workspace, fmt.Sprintf("Error building images: %s", buildErr), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
+			default:
+				return reconcile.Result{}, buildErr
+			}
+		}
+		imagebuild.InjectBuiltImages(allPodAdditions, builtImages)
+		imagebuild.InjectBuiltImages(dedicatedPodAdditions, builtImages)
+		reconcileStatus.setConditionTrue(conditions.ImageBuildReady, imagebuild.FormatBuiltImages(builtImages))
+	}
+
 	// Step six: Create deployment and wait for it to be ready
 	timing.SetTime(timingInfo, timing.DeploymentCreated)
-	deploymentStatus := wsprovision.SyncDeploymentToCluster(workspace, allPodAdditions, serviceAcctName, clusterAPI)
+	deploymentStatus := wsprovision.SyncDeploymentToCluster(workspace, allPodAdditions, dedicatedPodAdditions, serviceAcctName, clusterAPI)
+	containerStatuses, err := wsprovision.GetContainerStatuses(workspace, clusterAPI)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(containerStatuses) > 0 {
+		if err := wsprovision.SyncContainerStatusToCluster(workspace, containerStatuses, clusterAPI); err != nil {
+			return reconcile.Result{}, err
+		}
+		containersReady, containersMessage := wsprovision.FormatContainersReady(workspace, containerStatuses)
+		if containersReady {
+			reconcileStatus.setConditionTrue(conditions.ContainersReady, containersMessage)
+		} else {
+			reconcileStatus.setConditionFalse(conditions.ContainersReady, containersMessage)
+		}
+	}
 	if !deploymentStatus.Continue {
+		if deploymentStatus.Preempted {
+			reqLogger.Info("Stopping DevWorkspace as its pod was preempted", "reason", deploymentStatus.Message)
+			patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}},"spec":{"started":false}}`,
+				constants.DevWorkspaceStopReasonAnnotation, constants.DevWorkspaceStopReasonPreempted))
+			err := r.Client.Patch(ctx, clusterWorkspace, client.RawPatch(types.MergePatchType, patch))
+			return reconcile.Result{Requeue: true}, err
+		}
 		if deploymentStatus.FailStartup {
 			failureReason := metrics.DetermineProvisioningFailureReason(deploymentStatus)
 			return r.failWorkspace(workspace, deploymentStatus.Info(), failureReason, reqLogger, &reconcileStatus)
 		}
 		reqLogger.Info("Waiting on deployment to be ready")
-		reconcileStatus.setConditionFalse(conditions.DeploymentReady, "Waiting for workspace deployment")
+		message := "Waiting for workspace deployment"
+		if deploymentStatus.Message != "" {
+			message = deploymentStatus.Message
+		}
+		reconcileStatus.setConditionFalse(conditions.DeploymentReady, message)
 		if !deploymentStatus.Requeue && deploymentStatus.Err == nil {
 			return reconcile.Result{RequeueAfter: startingWorkspaceRequeueInterval}, nil
 		}
 		return reconcile.Result{Requeue: deploymentStatus.Requeue}, deploymentStatus.Err
 	}
 	reconcileStatus.setConditionTrue(conditions.DeploymentReady, "DevWorkspace deployment ready")
+	if deploymentStatus.ProjectCloneWarning != "" {
+		reconcileStatus.addWarning(fmt.Sprintf("Problems encountered while setting up projects: %s", deploymentStatus.ProjectCloneWarning))
+	}
 	timing.SetTime(timingInfo, timing.DeploymentReady)
 
-	serverReady, err := checkServerStatus(clusterWorkspace)
-	if err != nil {
-		return reconcile.Result{}, err
+	if !hasMainEndpointHealthCheck(workspace) {
+		serverReady, err := checkServerStatus(clusterWorkspace)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !serverReady {
+			reconcileStatus.setConditionFalse(dw.DevWorkspaceReady, "Waiting for editor to start")
+			return reconcile.Result{RequeueAfter: 1 * time.Second}, nil
+		}
 	}
-	if !serverReady {
-		reconcileStatus.setConditionFalse(dw.DevWorkspaceReady, "Waiting for editor to start")
-		return reconcile.Result{RequeueAfter: 1 * time.Second}, nil
+	if len(healthChecks) > 0 {
+		notReadyEndpoints := checkEndpointHealth(healthChecks)
+		endpointReadiness := formatEndpointReadiness(healthChecks, notReadyEndpoints)
+		if len(notReadyEndpoints) > 0 {
+			reconcileStatus.setConditionFalse(conditions.EndpointsReady, endpointReadiness)
+			reconcileStatus.setConditionFalse(dw.DevWorkspaceReady, "Waiting for endpoints to be ready")
+			return reconcile.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		reconcileStatus.setConditionTrue(conditions.EndpointsReady, endpointReadiness)
 	}
 	timing.SetTime(timingInfo, timing.DevWorkspaceReady)
 	timing.SummarizeStartup(clusterWorkspace)
@@ -460,6 +605,18 @@ func (r *DevWorkspaceReconciler) stopWorkspace(ctx context.Context, workspace *d
 		if failedCondition != nil {
 			status.setCondition(dw.DevWorkspaceFailedStart, *failedCondition)
 		}
+		// Preserve the state of containers at the time of failure, as pods are removed when the workspace is stopped
+		containersCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.ContainersReady)
+		if containersCondition != nil && containersCondition.Status != corev1.ConditionUnknown {
+			status.setCondition(conditions.ContainersReady, *containersCondition)
+		}
+		// Store logs of failed containers before the workspace's pods are removed
+		logsCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.FailedStartLogs)
+		if logsCondition != nil {
+			status.setCondition(conditions.FailedStartLogs, *logsCondition)
+		} else if workspace.Status.Phase == devworkspacePhaseFailing {
+			r.syncFailedStartLogs(ctx, workspace, logger, &status)
+		}
 	}
 
 	stopped, err := r.doStop(ctx, workspace, logger)
@@ -474,13 +631,22 @@ func (r *DevWorkspaceReconciler) stopWorkspace(ctx context.Context, workspace *d
 			status.setConditionFalse(conditions.Started, "Workspace stopped due to error")
 		default:
 			status.phase = dw.DevWorkspaceStatusStopped
-			status.setConditionFalse(conditions.Started, "Workspace is stopped")
+			if workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] == constants.DevWorkspaceStopReasonPreempted {
+				status.setConditionFalse(conditions.Started, "Workspace was stopped because its pod was preempted by a higher priority pod")
+			} else {
+				status.setConditionFalse(conditions.Started, "Workspace is stopped")
+			}
 		}
 	}
 	return r.updateWorkspaceStatus(workspace, logger, &status, reconcile.Result{}, nil)
 }
 
 func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *dw.DevWorkspace, logger logr.Logger) (stopped bool, err error) {
+	err = imagebuild.DeleteBuildJobs(workspace, sync.ClusterAPI{Client: r.Client, Scheme: r.Scheme, Logger: logger, Ctx: ctx})
+	if err != nil {
+		return false, err
+	}
+
 	workspaceDeployment := &appsv1.Deployment{}
 	namespaceName := types.NamespacedName{
 		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
@@ -536,6 +702,31 @@ func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *dw.DevWo
 	}
 }
 
+// syncFailedStartLogs stores the logs of failed containers in a workspace that failed to start and sets the
+// FailedStartLogs condition to reference them. If the workspace failed due to timing out, logs of containers that
+// are running but not ready are stored as well. Errors are logged and otherwise ignored, as they should not prevent
+// the workspace from being stopped.
+func (r *DevWorkspaceReconciler) syncFailedStartLogs(ctx context.Context, workspace *dw.DevWorkspace, logger logr.Logger, status *currentStatus) {
+	failedCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceFailedStart)
+	includeNotReady := failedCondition != nil && failedCondition.Reason == string(metrics.ReasonTimeout)
+	containerNames, err := wsprovision.SyncFailedStartLogsToCluster(workspace, includeNotReady, sync.ClusterAPI{
+		Client:    r.Client,
+		Clientset: r.Clientset,
+		Scheme:    r.Scheme,
+		Logger:    logger,
+		Ctx:       ctx,
+	})
+	if err != nil {
+		logger.Info("Failed to store logs for failed workspace containers", "error", err.Error())
+		return
+	}
+	if len(containerNames) == 0 {
+		return
+	}
+	status.setConditionTrue(conditions.FailedStartLogs, fmt.Sprintf("Logs for containers %s are stored in configmap %s",
+		strings.Join(containerNames, ", "), common.FailedStartLogsConfigMapName(workspace.Status.DevWorkspaceId)))
+}
+
 // failWorkspace marks a workspace as failed by setting relevant fields in the status struct.
 // These changes are not synced to cluster immediately, and are intended to be synced to the cluster via a deferred function
 // in the main reconcile loop. If needed, changes can be flushed to the cluster immediately via `updateWorkspaceStatus()`
@@ -596,6 +787,10 @@ func (r *DevWorkspaceReconciler) removeStartedAtFromCluster(
 	if workspace.Annotations == nil {
 		workspace.Annotations = map[string]string{}
 	}
+	if _, startedSuccessfully := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]; startedSuccessfully {
+		// Projects have been set up, so prebuilds are no longer needed for this workspace
+		prebuild.MarkPrebuildRestored(workspace)
+	}
 	delete(workspace.Annotations, constants.DevWorkspaceStartedAtAnnotation)
 	if err := r.Update(ctx, workspace); err != nil {
 		if k8sErrors.IsConflict(err) {
@@ -669,14 +864,13 @@ func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	var configWatcher builder.WatchesOption = builder.WithPredicates(config.Predicates())
 
-	// TODO: Set up indexing https://book.kubebuilder.io/cronjob-tutorial/controller-implementation.html#setup
+	if err := setupImportedTemplatesIndex(mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		For(&dw.DevWorkspace{}).
-		// List DevWorkspaceTemplates as owned to enable updating workspaces when templates
-		// are changed; this should be moved to whichever controller is responsible for flattening
-		// DevWorkspaces
-		Owns(&dw.DevWorkspaceTemplate{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&batchv1.Job{}).
 		Owns(&controllerv1alpha1.DevWorkspaceRouting{}).
@@ -685,6 +879,7 @@ func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.ServiceAccount{}).
 		Watches(&source.Kind{Type: &corev1.Pod{}}, dwRelatedPodsHandler()).
 		Watches(&source.Kind{Type: &controllerv1alpha1.DevWorkspaceOperatorConfig{}}, handler.EnqueueRequestsFromMapFunc(emptyMapper), configWatcher).
+		Watches(&source.Kind{Type: &dw.DevWorkspaceTemplate{}}, handler.EnqueueRequestsFromMapFunc(r.dwtToDevWorkspaces)).
 		WithEventFilter(predicates).
 		WithEventFilter(podPredicates).
 		Complete(r)
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+)
+
+const (
+	// eventReasonStorageCleanup is the reason for events recorded while cleaning up a DevWorkspace's storage
+	eventReasonStorageCleanup = "StorageCleanup"
+	// eventReasonStorageCleanupSucceeded is the reason for the event recorded once a DevWorkspace's storage is cleaned up
+	eventReasonStorageCleanupSucceeded = "StorageCleanupSucceeded"
+	// eventReasonStorageCleanupFailed is the reason for the event recorded if cleaning up a DevWorkspace's storage fails
+	eventReasonStorageCleanupFailed = "StorageCleanupFailed"
+)
+
+// recordPhaseEvent records an event on a DevWorkspace if its phase has changed. Events for failed DevWorkspaces are
+// recorded as warnings, using the failure reason from the DevWorkspace's FailedStart condition as the event reason.
+func (r *DevWorkspaceReconciler) recordPhaseEvent(workspace *dw.DevWorkspace, oldPhase, newPhase dw.DevWorkspacePhase) {
+	if oldPhase == newPhase {
+		return
+	}
+	eventType, reason, message := corev1.EventTypeNormal, string(newPhase), workspace.Status.Message
+	switch newPhase {
+	case devworkspacePhaseFailing, dw.DevWorkspaceStatusFailed:
+		eventType, reason = corev1.EventTypeWarning, string(metrics.ReasonUnknown)
+		if failedCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceFailedStart); failedCondition != nil {
+			if failedCondition.Reason != "" {
+				reason = failedCondition.Reason
+			}
+			message = failedCondition.Message
+		}
+		message = fmt.Sprintf("DevWorkspace is %s: %s", newPhase, message)
+	case dw.DevWorkspaceStatusError:
+		eventType = corev1.EventTypeWarning
+	case dw.DevWorkspaceStatusStopped:
+		if startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started); startedCondition != nil {
+			message = startedCondition.Message
+		}
+	}
+	r.Recorder.Event(workspace, eventType, reason, message)
+}
+
+// recordConditionEvents records an event on a DevWorkspace for each condition whose status has changed. Conditions
+// that are no longer observed (i.e. that have changed to Unknown) are ignored. The reason for each event is the
+// condition's type, unless the condition specifies a reason (e.g. a metrics.FailureReason for the FailedStart
+// condition).
+func (r *DevWorkspaceReconciler) recordConditionEvents(workspace *dw.DevWorkspace, transitioned []dw.DevWorkspaceCondition) {
+	for _, condition := range transitioned {
+		if condition.Status == corev1.ConditionUnknown {
+			continue
+		}
+		eventType := corev1.EventTypeNormal
+		if isWarningCondition(condition) {
+			eventType = corev1.EventTypeWarning
+		}
+		reason := string(condition.Type)
+		if condition.Reason != "" {
+			reason = condition.Reason
+		}
+		message := fmt.Sprintf("Condition %s is %s", condition.Type, condition.Status)
+		if condition.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, condition.Message)
+		}
+		r.Recorder.Event(workspace, eventType, reason, message)
+	}
+}
+
+func isWarningCondition(condition dw.DevWorkspaceCondition) bool {
+	switch condition.Type {
+	case dw.DevWorkspaceFailedStart, dw.DevWorkspaceError, conditions.DevWorkspaceWarning:
+		return condition.Status == corev1.ConditionTrue
+	}
+	return false
+}
@@ -104,9 +104,11 @@ func (r *DevWorkspaceReconciler) finalizeStorage(ctx context.Context, log logr.L
 		switch storageErr := err.(type) {
 		case *storage.NotReadyError:
 			log.Info(storageErr.Message)
+			r.Recorder.Event(workspace, corev1.EventTypeNormal, eventReasonStorageCleanup, storageErr.Message)
 			return reconcile.Result{RequeueAfter: storageErr.RequeueAfter}, nil
 		case *storage.ProvisioningError:
 			log.Error(storageErr, "Failed to clean up DevWorkspace storage")
+			r.Recorder.Event(workspace, corev1.EventTypeWarning, eventReasonStorageCleanupFailed, storageErr.Error())
 			failedStatus := currentStatus{phase: dw.DevWorkspaceStatusError}
 			failedStatus.setConditionTrue(dw.DevWorkspaceError, err.Error())
 			return r.updateWorkspaceStatus(workspace, r.Log, &failedStatus, reconcile.Result{}, nil)
@@ -115,6 +117,7 @@ func (r *DevWorkspaceReconciler) finalizeStorage(ctx context.Context, log logr.L
 		}
 	}
 	log.Info("PVC clean up successful; clearing finalizer")
+	r.Recorder.Event(workspace, corev1.EventTypeNormal, eventReasonStorageCleanupSucceeded, "DevWorkspace storage cleaned up")
 	coputil.RemoveFinalizer(workspace, constants.StorageCleanupFinalizer)
 	return reconcile.Result{}, r.Update(ctx, workspace)
 }
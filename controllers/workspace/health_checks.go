@@ -0,0 +1,219 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	containerlib "github.com/devfile/devworkspace-operator/pkg/library/container"
+)
+
+type healthCheckType string
+
+const (
+	httpHealthCheck healthCheckType = "http"
+	tcpHealthCheck  healthCheckType = "tcp"
+	noHealthCheck   healthCheckType = "none"
+
+	tcpHealthCheckTimeout  = 5 * time.Second
+	httpHealthCheckTimeout = 5 * time.Second
+)
+
+// endpointHealthCheck is the value of the health-check attribute on an endpoint
+type endpointHealthCheck struct {
+	Type           healthCheckType `json:"type"`
+	Path           string          `json:"path,omitempty"`
+	ExpectedStatus int             `json:"expectedStatus,omitempty"`
+}
+
+// endpointHealthCheckTarget is a health check along with the addresses used to check an endpoint
+type endpointHealthCheckTarget struct {
+	endpointHealthCheck
+	endpointName string
+	// url is the URL of the endpoint, either as exposed by routing or as reachable within the cluster
+	url string
+	// address is the in-cluster host:port for the endpoint
+	address string
+}
+
+// getEndpointHealthChecks returns the health checks declared on endpoints in a flattened DevWorkspace. Endpoints are
+// checked using the URL exposed by routing, if present in exposedEndpoints, or otherwise using the in-cluster address
+// of the service for the endpoint. URLs set in endpoint attributes are not used, as they are controlled by the user.
+// Returns an error if a health check attribute is invalid.
+func getEndpointHealthChecks(workspace *dw.DevWorkspace, exposedEndpoints map[string]v1alpha1.ExposedEndpointList) ([]endpointHealthCheckTarget, error) {
+	var checks []endpointHealthCheckTarget
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Container == nil {
+			continue
+		}
+		serviceName := common.ServiceName(workspace.Status.DevWorkspaceId)
+		if containerlib.IsDedicatedPod(&component) {
+			serviceName = common.DedicatedPodName(workspace.Status.DevWorkspaceId, component.Name)
+		}
+		for _, endpoint := range component.Container.Endpoints {
+			if !endpoint.Attributes.Exists(constants.EndpointHealthCheckAttribute) {
+				continue
+			}
+			check := endpointHealthCheck{}
+			if err := endpoint.Attributes.GetInto(constants.EndpointHealthCheckAttribute, &check); err != nil {
+				return nil, fmt.Errorf("failed to parse %s attribute on endpoint %s: %w", constants.EndpointHealthCheckAttribute, endpoint.Name, err)
+			}
+			if err := validateEndpointHealthCheck(check, endpoint); err != nil {
+				return nil, fmt.Errorf("invalid %s attribute on endpoint %s: %w", constants.EndpointHealthCheckAttribute, endpoint.Name, err)
+			}
+			if check.Type == noHealthCheck {
+				continue
+			}
+			address := fmt.Sprintf("%s.%s.svc:%d", serviceName, workspace.Namespace, endpoint.TargetPort)
+			endpointURL := getExposedEndpointURL(exposedEndpoints[component.Name], endpoint.Name)
+			if endpointURL == "" {
+				endpointURL = fmt.Sprintf("http://%s/", address)
+			}
+			checks = append(checks, endpointHealthCheckTarget{
+				endpointHealthCheck: check,
+				endpointName:        endpoint.Name,
+				url:                 endpointURL,
+				address:             address,
+			})
+		}
+	}
+	return checks, nil
+}
+
+// getExposedEndpointURL returns the URL of the endpoint with the given name in exposedEndpoints, or an empty string
+// if the endpoint is not exposed.
+func getExposedEndpointURL(exposedEndpoints v1alpha1.ExposedEndpointList, endpointName string) string {
+	for _, exposedEndpoint := range exposedEndpoints {
+		if exposedEndpoint.Name == endpointName {
+			return exposedEndpoint.Url
+		}
+	}
+	return ""
+}
+
+// hasMainEndpointHealthCheck returns whether the main endpoint of a DevWorkspace declares a health check. If it does
+// not, the main URL is checked using checkServerStatus.
+func hasMainEndpointHealthCheck(workspace *dw.DevWorkspace) bool {
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Container == nil {
+			continue
+		}
+		for _, endpoint := range component.Container.Endpoints {
+			if endpoint.Attributes.GetString(string(v1alpha1.TypeEndpointAttribute), nil) == string(v1alpha1.MainEndpointType) &&
+				endpoint.Attributes.Exists(constants.EndpointHealthCheckAttribute) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateEndpointHealthCheck(check endpointHealthCheck, endpoint dw.Endpoint) error {
+	switch check.Type {
+	case noHealthCheck:
+		return nil
+	case httpHealthCheck:
+		if check.ExpectedStatus != 0 && (check.ExpectedStatus < 100 || check.ExpectedStatus > 599) {
+			return fmt.Errorf("invalid expectedStatus %d", check.ExpectedStatus)
+		}
+	case tcpHealthCheck:
+		if check.Path != "" || check.ExpectedStatus != 0 {
+			return fmt.Errorf("path and expectedStatus are only supported for http health checks")
+		}
+	default:
+		return fmt.Errorf("unsupported type %q: must be one of %q, %q, or %q", check.Type, httpHealthCheck, tcpHealthCheck, noHealthCheck)
+	}
+	if endpoint.Exposure == dw.NoneEndpointExposure {
+		return fmt.Errorf("health checks are not supported for endpoints with exposure %q", dw.NoneEndpointExposure)
+	}
+	return nil
+}
+
+// checkEndpointHealth runs health checks for endpoints, returning a map of endpoint name to a message describing why
+// that endpoint is not ready. Endpoints that are ready are not included in the returned map.
+func checkEndpointHealth(checks []endpointHealthCheckTarget) map[string]string {
+	notReady := map[string]string{}
+	for _, check := range checks {
+		var err error
+		switch check.Type {
+		case httpHealthCheck:
+			err = checkHTTPEndpoint(check)
+		case tcpHealthCheck:
+			err = checkTCPEndpoint(check)
+		}
+		if err != nil {
+			notReady[check.endpointName] = err.Error()
+		}
+	}
+	return notReady
+}
+
+func checkHTTPEndpoint(check endpointHealthCheckTarget) error {
+	checkURL, err := url.Parse(check.url)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	checkURL.Path = strings.TrimSuffix(checkURL.Path, "/") + "/" + strings.TrimPrefix(check.Path, "/")
+
+	resp, err := endpointHealthCheckHttpClient.Get(checkURL.String())
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if check.ExpectedStatus != 0 {
+		if resp.StatusCode != check.ExpectedStatus {
+			return fmt.Errorf("got status %d, expected %d", resp.StatusCode, check.ExpectedStatus)
+		}
+		return nil
+	}
+	if (resp.StatusCode / 100) != 2 {
+		return fmt.Errorf("got status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func checkTCPEndpoint(check endpointHealthCheckTarget) error {
+	conn, err := net.DialTimeout("tcp", check.address, tcpHealthCheckTimeout)
+	if err != nil {
+		return fmt.Errorf("connection failed: %w", err)
+	}
+	return conn.Close()
+}
+
+// formatEndpointReadiness returns a message describing the readiness of each checked endpoint, sorted by endpoint
+// name.
+func formatEndpointReadiness(checks []endpointHealthCheckTarget, notReady map[string]string) string {
+	var readiness []string
+	for _, check := range checks {
+		if reason, ok := notReady[check.endpointName]; ok {
+			readiness = append(readiness, fmt.Sprintf("%s: not ready (%s)", check.endpointName, reason))
+		} else {
+			readiness = append(readiness, fmt.Sprintf("%s: ready", check.endpointName))
+		}
+	}
+	sort.Strings(readiness)
+	return strings.Join(readiness, "; ")
+}
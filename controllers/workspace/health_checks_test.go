@@ -0,0 +1,217 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetEndpointHealthChecks(t *testing.T) {
+	dedicatedPod := true
+	workspace := &dw.DevWorkspace{}
+	workspace.Namespace = "test-namespace"
+	workspace.Status.DevWorkspaceId = "test-id"
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Endpoints: []dw.Endpoint{
+						getTestEndpoint(t, "exposed", 8080, dw.PublicEndpointExposure, map[string]interface{}{"type": "http", "path": "/ready"}),
+						getTestEndpoint(t, "internal", 8081, dw.InternalEndpointExposure, map[string]interface{}{"type": "tcp"}),
+						getTestEndpoint(t, "unchecked", 8082, dw.PublicEndpointExposure, map[string]interface{}{"type": "none"}),
+						{Name: "no-health-check", TargetPort: 8083},
+					},
+				},
+			},
+		},
+		{
+			Name: "db",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{DedicatedPod: &dedicatedPod},
+					Endpoints: []dw.Endpoint{
+						getTestEndpoint(t, "db", 5432, dw.InternalEndpointExposure, map[string]interface{}{"type": "tcp"}),
+					},
+				},
+			},
+		},
+	}
+	// URLs set in attributes by the user must not be used for health checks
+	for idx := range workspace.Spec.Template.Components[0].Container.Endpoints {
+		endpoint := &workspace.Spec.Template.Components[0].Container.Endpoints[idx]
+		if endpoint.Attributes == nil {
+			endpoint.Attributes = attributes.Attributes{}
+		}
+		endpoint.Attributes.PutString(constants.EndpointURLAttribute, "http://169.254.169.254/latest/meta-data/")
+	}
+	exposedEndpoints := map[string]v1alpha1.ExposedEndpointList{
+		"tools": {{Name: "exposed", Url: "https://test-id-1.example.com/"}},
+	}
+
+	checks, err := getEndpointHealthChecks(workspace, exposedEndpoints)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, []endpointHealthCheckTarget{
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: httpHealthCheck, Path: "/ready"},
+			endpointName:        "exposed",
+			url:                 "https://test-id-1.example.com/",
+			address:             "test-id-service.test-namespace.svc:8080",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: tcpHealthCheck},
+			endpointName:        "internal",
+			url:                 "http://test-id-service.test-namespace.svc:8081/",
+			address:             "test-id-service.test-namespace.svc:8081",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: tcpHealthCheck},
+			endpointName:        "db",
+			url:                 "http://test-id-db.test-namespace.svc:5432/",
+			address:             "test-id-db.test-namespace.svc:5432",
+		},
+	}, checks)
+}
+
+func TestGetEndpointHealthChecksInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		exposure dw.EndpointExposure
+		check    map[string]interface{}
+	}{
+		{
+			name:     "Unsupported type",
+			exposure: dw.PublicEndpointExposure,
+			check:    map[string]interface{}{"type": "grpc"},
+		},
+		{
+			name:     "Invalid expected status",
+			exposure: dw.PublicEndpointExposure,
+			check:    map[string]interface{}{"type": "http", "expectedStatus": 1000},
+		},
+		{
+			name:     "Path on tcp check",
+			exposure: dw.PublicEndpointExposure,
+			check:    map[string]interface{}{"type": "tcp", "path": "/ready"},
+		},
+		{
+			name:     "Endpoint with exposure none",
+			exposure: dw.NoneEndpointExposure,
+			check:    map[string]interface{}{"type": "http"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &dw.DevWorkspace{}
+			workspace.Spec.Template.Components = []dw.Component{
+				{
+					Name: "tools",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{
+							Endpoints: []dw.Endpoint{getTestEndpoint(t, "test-endpoint", 8080, tt.exposure, tt.check)},
+						},
+					},
+				},
+			}
+			_, err := getEndpointHealthChecks(workspace, nil)
+			assert.Error(t, err, "Should return error")
+		})
+	}
+}
+
+func TestCheckEndpointHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/base/ready":
+			w.WriteHeader(http.StatusOK)
+		case "/base/unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/base/redirect":
+			http.Redirect(w, r, "/base/ready", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	endpointHealthCheckHttpClient = newEndpointHealthCheckHttpClient(server.Client().Transport)
+	defer func() {
+		endpointHealthCheckHttpClient = nil
+	}()
+	serverURL, err := url.Parse(server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	checks := []endpointHealthCheckTarget{
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: httpHealthCheck, Path: "/ready"},
+			endpointName:        "http-ready",
+			url:                 server.URL + "/base/",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: httpHealthCheck, Path: "unauthorized", ExpectedStatus: http.StatusUnauthorized},
+			endpointName:        "http-expected-status",
+			url:                 server.URL + "/base",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: httpHealthCheck, Path: "/missing"},
+			endpointName:        "http-not-ready",
+			url:                 server.URL + "/base/",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: httpHealthCheck, Path: "/redirect"},
+			endpointName:        "http-redirect",
+			url:                 server.URL + "/base/",
+		},
+		{
+			endpointHealthCheck: endpointHealthCheck{Type: tcpHealthCheck},
+			endpointName:        "tcp-ready",
+			address:             serverURL.Host,
+		},
+	}
+	notReady := checkEndpointHealth(checks)
+	assert.Equal(t, map[string]string{"http-not-ready": "got status 404", "http-redirect": "got status 302"}, notReady,
+		"Should not follow redirects")
+	assert.Equal(t, "http-expected-status: ready; http-not-ready: not ready (got status 404); http-ready: ready; "+
+		"http-redirect: not ready (got status 302); tcp-ready: ready",
+		formatEndpointReadiness(checks, notReady))
+}
+
+func getTestEndpoint(t *testing.T, name string, port int, exposure dw.EndpointExposure, check map[string]interface{}) dw.Endpoint {
+	var err error
+	endpointAttributes := attributes.Attributes{}.Put(constants.EndpointHealthCheckAttribute, check, &err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dw.Endpoint{
+		Name:       name,
+		TargetPort: port,
+		Exposure:   exposure,
+		Attributes: endpointAttributes,
+	}
+}
@@ -16,41 +16,54 @@ package controllers
 import (
 	"crypto/tls"
 	"net/http"
-	"net/url"
 
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
 	"github.com/devfile/devworkspace-operator/pkg/config"
-	"golang.org/x/net/http/httpproxy"
+	"github.com/devfile/devworkspace-operator/pkg/config/proxy"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
 
 var (
 	httpClient            *http.Client
 	healthCheckHttpClient *http.Client
+	// endpointHealthCheckHttpClient is used for the health checks defined on workspace endpoints
+	endpointHealthCheckHttpClient *http.Client
+	// registryClient is used to fetch devfiles and plugins from registries and URIs, caching results
+	registryClient *network.CachingHTTPGetter
 )
 
 func setupHttpClients() {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	healthCheckTransport := http.DefaultTransport.(*http.Transport).Clone()
+	var proxyConfig *controllerv1alpha1.Proxy
+	if config.Routing != nil {
+		proxyConfig = config.Routing.ProxyConfig
+	}
+	transport := proxy.NewTransport(proxyConfig)
+	healthCheckTransport := proxy.NewTransport(proxyConfig)
 	healthCheckTransport.TLSClientConfig = &tls.Config{
 		InsecureSkipVerify: true,
 	}
 
-	if config.Routing != nil && config.Routing.ProxyConfig != nil {
-		proxyConf := httpproxy.Config{
-			HTTPProxy:  config.Routing.ProxyConfig.HttpProxy,
-			HTTPSProxy: config.Routing.ProxyConfig.HttpsProxy,
-			NoProxy:    config.Routing.ProxyConfig.NoProxy,
-		}
-		proxyFunc := func(req *http.Request) (*url.URL, error) {
-			return proxyConf.ProxyFunc()(req.URL)
-		}
-		transport.Proxy = proxyFunc
-		healthCheckTransport.Proxy = proxyFunc
-	}
-
 	httpClient = &http.Client{
 		Transport: transport,
 	}
 	healthCheckHttpClient = &http.Client{
 		Transport: healthCheckTransport,
+		Timeout:   httpHealthCheckTimeout,
+	}
+	endpointHealthCheckHttpClient = newEndpointHealthCheckHttpClient(healthCheckTransport)
+	registryClient = network.NewCachingHTTPGetter(httpClient, config.GetRegistryCacheConfig, metrics.RegistryCacheObserver{})
+}
+
+// newEndpointHealthCheckHttpClient returns a client for endpoint health checks. Requests time out after
+// httpHealthCheckTimeout, and redirects are not followed, so that a workspace cannot redirect the operator to
+// other URLs; the redirect response is returned as the result of the check instead.
+func newEndpointHealthCheckHttpClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		Timeout:   httpHealthCheckTimeout,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
 	}
 }
@@ -16,37 +16,42 @@
 package metrics
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
 	"github.com/devfile/devworkspace-operator/pkg/provision/workspace"
 )
 
-var badRequestFailures = []string{
-	"CrashLoopBackOff",
-	"ErrImagePull",
-	"ImagePullBackOff",
-}
-
-var infrastructureFailures = []string{
-	"CreateContainerError",
-	"RunContainerError",
-	"FailedScheduling",
-	"FailedMount",
+// failureReasonsByPriority maps failure reasons to substrings of a deployment provisioning status message that
+// indicate that reason. Reasons are checked in order, as a message may contain substrings matching multiple reasons
+// (e.g. a container in CrashLoopBackOff that was last terminated due to OOMKilled).
+var failureReasonsByPriority = []struct {
+	reason   FailureReason
+	messages []string
+}{
+	{ReasonOOMKilled, []string{"OOMKilled"}},
+	{ReasonImagePullFailure, []string{"ErrImagePull", "ImagePullBackOff"}},
+	{ReasonCrashLoop, []string{"CrashLoopBackOff"}},
+	{ReasonFailedScheduling, []string{"FailedScheduling"}},
+	{ReasonStorageFailure, []string{"FailedMount", "FailedAttachVolume"}},
+	{ReasonInfrastructureFailure, []string{"CreateContainerError", "RunContainerError"}},
 }
 
 // DetermineProvisioningFailureReason scans a deployment provisioning status info message
-// and returns the corresponding failure reason.
+// and returns the corresponding failure reason. Failures of the project-clone init container
+// are reported as a clone failure, regardless of the container's state.
 // If a failure reason cannot be found, an Unknown reason is returned.
 func DetermineProvisioningFailureReason(status workspace.DeploymentProvisioningStatus) FailureReason {
 	failStartupMsg := status.Info()
-	for _, failure := range badRequestFailures {
-		if strings.Contains(failStartupMsg, failure) {
-			return ReasonBadRequest
-		}
+	if strings.Contains(failStartupMsg, fmt.Sprintf("Init Container %s ", projects.GetProjectCloneContainerName())) {
+		return ReasonCloneFailure
 	}
-	for _, failure := range infrastructureFailures {
-		if strings.Contains(failStartupMsg, failure) {
-			return ReasonInfrastructureFailure
+	for _, failure := range failureReasonsByPriority {
+		for _, msg := range failure.messages {
+			if strings.Contains(failStartupMsg, msg) {
+				return failure.reason
+			}
 		}
 	}
 	return ReasonUnknown
@@ -0,0 +1,48 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+func TestDetermineProvisioningFailureReason(t *testing.T) {
+	tests := []struct {
+		message  string
+		expected FailureReason
+	}{
+		{"Container tools has state CrashLoopBackOff", ReasonCrashLoop},
+		{"Container tools has state CrashLoopBackOff (last terminated: OOMKilled)", ReasonOOMKilled},
+		{"Container tools has state ImagePullBackOff", ReasonImagePullFailure},
+		{"Init Container project-clone has state CrashLoopBackOff (last terminated: Error)", ReasonCloneFailure},
+		{"Detected unrecoverable event FailedScheduling: 0/3 nodes are available", ReasonFailedScheduling},
+		{"Detected unrecoverable event FailedMount 3 times: Unable to attach or mount volumes", ReasonStorageFailure},
+		{"Container tools has state CreateContainerError", ReasonInfrastructureFailure},
+		{"Detected unrecoverable event FailedPostStartHook: exec failed", ReasonUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			status := workspace.DeploymentProvisioningStatus{
+				ProvisioningStatus: workspace.ProvisioningStatus{FailStartup: true, Message: tt.message},
+			}
+			assert.Equal(t, tt.expected, DetermineProvisioningFailureReason(status))
+		})
+	}
+}
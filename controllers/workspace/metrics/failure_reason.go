@@ -26,13 +26,37 @@ const (
 	ReasonBadRequest             FailureReason = "BadRequest"
 	ReasonInfrastructureFailure  FailureReason = "InfrastructureFailure"
 	ReasonWorkspaceEngineFailure FailureReason = "WorkspaceEngineFailure"
-	ReasonUnknown                FailureReason = "Unknown"
+	// ReasonImagePullFailure is used when a workspace container image cannot be pulled
+	ReasonImagePullFailure FailureReason = "ImagePullFailure"
+	// ReasonFailedScheduling is used when a workspace pod cannot be scheduled
+	ReasonFailedScheduling FailureReason = "FailedScheduling"
+	// ReasonOOMKilled is used when a workspace container is repeatedly killed for exceeding its memory limit
+	ReasonOOMKilled FailureReason = "OOMKilled"
+	// ReasonCrashLoop is used when a workspace container repeatedly exits
+	ReasonCrashLoop FailureReason = "CrashLoop"
+	// ReasonStorageFailure is used when storage for a workspace cannot be provisioned or mounted
+	ReasonStorageFailure FailureReason = "StorageFailure"
+	// ReasonRoutingFailure is used when the DevWorkspaceRouting for a workspace fails
+	ReasonRoutingFailure FailureReason = "RoutingFailure"
+	// ReasonCloneFailure is used when projects cannot be cloned into the workspace
+	ReasonCloneFailure FailureReason = "CloneFailure"
+	// ReasonTimeout is used when a workspace does not start within the configured progress timeout
+	ReasonTimeout FailureReason = "Timeout"
+	ReasonUnknown FailureReason = "Unknown"
 )
 
 var devworkspaceFailureReasons = []FailureReason{
 	ReasonBadRequest,
 	ReasonInfrastructureFailure,
 	ReasonWorkspaceEngineFailure,
+	ReasonImagePullFailure,
+	ReasonFailedScheduling,
+	ReasonOOMKilled,
+	ReasonCrashLoop,
+	ReasonStorageFailure,
+	ReasonRoutingFailure,
+	ReasonCloneFailure,
+	ReasonTimeout,
 	ReasonUnknown,
 }
 
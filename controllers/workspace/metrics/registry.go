@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+const metricsResultLabel = "result"
+
+var (
+	registryCacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "registry_cache_hit_total",
+			Help:      "Number of devfile registry and URI requests served from the cache",
+		},
+	)
+	registryCacheMisses = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "registry_cache_miss_total",
+			Help:      "Number of devfile registry and URI requests that required contacting a server",
+		},
+	)
+	registryFetchDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "devworkspace",
+			Name:      "registry_fetch_duration_seconds",
+			Help:      "Time taken to fetch content from devfile registries and URIs, in seconds",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{
+			metricsResultLabel,
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(registryCacheHits, registryCacheMisses, registryFetchDuration)
+}
+
+// RegistryCacheObserver records metrics for requests made through a network.CachingHTTPGetter
+type RegistryCacheObserver struct{}
+
+var _ network.CacheObserver = RegistryCacheObserver{}
+
+func (RegistryCacheObserver) CacheHit() {
+	registryCacheHits.Inc()
+}
+
+func (RegistryCacheObserver) CacheMiss() {
+	registryCacheMisses.Inc()
+}
+
+func (RegistryCacheObserver) Fetched(duration time.Duration, result string) {
+	registryFetchDuration.WithLabelValues(result).Observe(duration.Seconds())
+}
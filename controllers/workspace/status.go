@@ -64,7 +64,7 @@ var clock kubeclock.Clock = &kubeclock.RealClock{}
 // Parameters for result and error are returned unmodified, unless error is nil and another error is encountered while
 // updating the status.
 func (r *DevWorkspaceReconciler) updateWorkspaceStatus(workspace *dw.DevWorkspace, logger logr.Logger, status *currentStatus, reconcileResult reconcile.Result, reconcileError error) (reconcile.Result, error) {
-	syncConditions(&workspace.Status, status)
+	transitionedConditions := syncConditions(&workspace.Status, status)
 	oldPhase := workspace.Status.Phase
 	workspace.Status.Phase = status.phase
 
@@ -84,12 +84,16 @@ func (r *DevWorkspaceReconciler) updateWorkspaceStatus(workspace *dw.DevWorkspac
 		}
 	} else {
 		updateMetricsForPhase(workspace, oldPhase, status.phase, logger)
+		r.recordConditionEvents(workspace, transitionedConditions)
+		r.recordPhaseEvent(workspace, oldPhase, status.phase)
 	}
 
 	return reconcileResult, reconcileError
 }
 
-func syncConditions(workspaceStatus *dw.DevWorkspaceStatus, currentStatus *currentStatus) {
+// syncConditions updates the conditions in workspaceStatus to match currentStatus, returning the conditions that
+// were added or whose status changed. Conditions where only the message or reason changed are updated but not returned.
+func syncConditions(workspaceStatus *dw.DevWorkspaceStatus, currentStatus *currentStatus) (transitioned []dw.DevWorkspaceCondition) {
 	currTransitionTime := metav1.Time{Time: clock.Now()}
 
 	// Set of conditions already set on the workspace
@@ -105,6 +109,7 @@ func syncConditions(workspaceStatus *dw.DevWorkspaceStatus, currentStatus *curre
 				workspaceStatus.Conditions[idx].Status = corev1.ConditionUnknown
 				workspaceStatus.Conditions[idx].Message = ""
 				workspaceStatus.Conditions[idx].Reason = ""
+				transitioned = append(transitioned, workspaceStatus.Conditions[idx])
 			}
 			continue
 		}
@@ -115,6 +120,9 @@ func syncConditions(workspaceStatus *dw.DevWorkspaceStatus, currentStatus *curre
 			workspaceStatus.Conditions[idx].Status = currCondition.Status
 			workspaceStatus.Conditions[idx].Message = currCondition.Message
 			workspaceStatus.Conditions[idx].Reason = currCondition.Reason
+			if workspaceCondition.Status != currCondition.Status {
+				transitioned = append(transitioned, workspaceStatus.Conditions[idx])
+			}
 		}
 	}
 
@@ -124,19 +132,25 @@ func syncConditions(workspaceStatus *dw.DevWorkspaceStatus, currentStatus *curre
 			// Condition is already present and was updated (if necessary) above
 			continue
 		}
-		workspaceStatus.Conditions = append(workspaceStatus.Conditions, dw.DevWorkspaceCondition{
+		newCondition := dw.DevWorkspaceCondition{
 			LastTransitionTime: currTransitionTime,
 			Type:               condType,
 			Status:             cond.Status,
 			Message:            cond.Message,
 			Reason:             cond.Reason,
-		})
+		}
+		workspaceStatus.Conditions = append(workspaceStatus.Conditions, newCondition)
+		transitioned = append(transitioned, newCondition)
 	}
 
 	// Sort conditions to avoid unnecessary updates
 	sort.SliceStable(workspaceStatus.Conditions, func(i, j int) bool {
 		return getConditionIndexInOrder(workspaceStatus.Conditions[i].Type) < getConditionIndexInOrder(workspaceStatus.Conditions[j].Type)
 	})
+	sort.SliceStable(transitioned, func(i, j int) bool {
+		return getConditionIndexInOrder(transitioned[i].Type) < getConditionIndexInOrder(transitioned[j].Type)
+	})
+	return transitioned
 }
 
 func syncWorkspaceMainURL(workspace *dw.DevWorkspace, exposedEndpoints map[string]v1alpha1.ExposedEndpointList, clusterAPI sync.ClusterAPI) (ok bool, err error) {
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+)
+
+func TestSyncConditionsReturnsOnlyStatusChanges(t *testing.T) {
+	workspaceStatus := &dw.DevWorkspaceStatus{}
+	status := &currentStatus{}
+	status.setConditionTrue(conditions.Started, "DevWorkspace is starting")
+	status.setConditionFalse(conditions.DeploymentReady, "Waiting for workspace deployment")
+
+	transitioned := syncConditions(workspaceStatus, status)
+	assert.Len(t, transitioned, 2, "Should return added conditions")
+
+	// Progress messages update the condition but are not status changes
+	status.setConditionFalse(conditions.DeploymentReady, "Containers not ready: tools")
+	transitioned = syncConditions(workspaceStatus, status)
+	assert.Empty(t, transitioned, "Should not return conditions where only the message changed")
+	deploymentReady := conditions.GetConditionByType(workspaceStatus.Conditions, conditions.DeploymentReady)
+	if assert.NotNil(t, deploymentReady) {
+		assert.Equal(t, "Containers not ready: tools", deploymentReady.Message, "Should update condition message")
+	}
+
+	status.setConditionTrue(conditions.DeploymentReady, "Deployment ready")
+	transitioned = syncConditions(workspaceStatus, status)
+	if assert.Len(t, transitioned, 1, "Should return condition whose status changed") {
+		assert.Equal(t, conditions.DeploymentReady, transitioned[0].Type)
+		assert.Equal(t, corev1.ConditionTrue, transitioned[0].Status)
+	}
+
+	delete(status.conditions, conditions.DeploymentReady)
+	transitioned = syncConditions(workspaceStatus, status)
+	if assert.Len(t, transitioned, 1, "Should return condition that is no longer observed") {
+		assert.Equal(t, corev1.ConditionUnknown, transitioned[0].Status)
+	}
+}
+
+func TestRecordConditionEvents(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &DevWorkspaceReconciler{Recorder: recorder}
+	workspace := &dw.DevWorkspace{}
+
+	r.recordConditionEvents(workspace, []dw.DevWorkspaceCondition{
+		{Type: conditions.DeploymentReady, Status: corev1.ConditionTrue, Message: "Deployment ready"},
+		{Type: dw.DevWorkspaceFailedStart, Status: corev1.ConditionTrue, Reason: "BadRequest", Message: "Invalid devfile"},
+		{Type: conditions.StorageReady, Status: corev1.ConditionUnknown},
+	})
+	assert.Equal(t, []string{
+		"Normal DeploymentReady Condition DeploymentReady is True: Deployment ready",
+		"Warning BadRequest Condition FailedStart is True: Invalid devfile",
+	}, readEvents(recorder), "Should record events for conditions that are not Unknown")
+}
+
+func TestRecordPhaseEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &DevWorkspaceReconciler{Recorder: recorder}
+	workspace := &dw.DevWorkspace{}
+	workspace.Status.Message = "Waiting for workspace deployment"
+
+	r.recordPhaseEvent(workspace, dw.DevWorkspaceStatusStarting, dw.DevWorkspaceStatusStarting)
+	assert.Empty(t, readEvents(recorder), "Should not record event when phase is unchanged")
+
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{Type: dw.DevWorkspaceFailedStart, Status: corev1.ConditionTrue, Reason: "BadRequest", Message: "Invalid devfile"},
+	}
+	r.recordPhaseEvent(workspace, dw.DevWorkspaceStatusStarting, dw.DevWorkspaceStatusFailed)
+	assert.Equal(t, []string{"Warning BadRequest DevWorkspace is Failed: Invalid devfile"}, readEvents(recorder))
+}
+
+func readEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
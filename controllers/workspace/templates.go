@@ -0,0 +1,223 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+)
+
+// importedTemplatesIndex is a field index on DevWorkspaces and DevWorkspaceTemplates, storing the
+// <namespace>/<name> keys of the DevWorkspaceTemplates they import by Kubernetes reference.
+const importedTemplatesIndex = "spec.template.importedTemplates"
+
+// setupImportedTemplatesIndex sets up the importedTemplatesIndex field index for DevWorkspaces and
+// DevWorkspaceTemplates.
+func setupImportedTemplatesIndex(mgr ctrl.Manager) error {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &dw.DevWorkspace{}, importedTemplatesIndex, indexWorkspaceImportedTemplates)
+	if err != nil {
+		return err
+	}
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &dw.DevWorkspaceTemplate{}, importedTemplatesIndex, indexTemplateImportedTemplates)
+}
+
+func indexWorkspaceImportedTemplates(obj client.Object) []string {
+	workspace := obj.(*dw.DevWorkspace)
+	return getImportedTemplateKeys(workspace.Namespace, &workspace.Spec.Template)
+}
+
+func indexTemplateImportedTemplates(obj client.Object) []string {
+	template := obj.(*dw.DevWorkspaceTemplate)
+	return getImportedTemplateKeys(template.Namespace, &template.Spec)
+}
+
+// getImportedTemplateKeys returns the keys of DevWorkspaceTemplates imported by Kubernetes reference as a parent
+// or plugin in template. References that do not specify a namespace are assumed to refer to defaultNamespace.
+func getImportedTemplateKeys(defaultNamespace string, template *dw.DevWorkspaceTemplateSpec) []string {
+	var keys []string
+	addRef := func(ref *dw.KubernetesCustomResourceImportReference) {
+		if ref == nil {
+			return
+		}
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		keys = append(keys, templateKey(namespace, ref.Name))
+	}
+	if template.Parent != nil {
+		addRef(template.Parent.Kubernetes)
+	}
+	for _, component := range template.Components {
+		if component.Plugin != nil {
+			addRef(component.Plugin.Kubernetes)
+		}
+	}
+	return keys
+}
+
+func templateKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// dwtToDevWorkspaces maps a DevWorkspaceTemplate to reconcile requests for all started DevWorkspaces that import
+// it, either directly or through other DevWorkspaceTemplates.
+func (r *DevWorkspaceReconciler) dwtToDevWorkspaces(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	seen := map[string]bool{}
+	queue := []string{templateKey(obj.GetNamespace(), obj.GetName())}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		workspaces := &dw.DevWorkspaceList{}
+		if err := r.Client.List(context.Background(), workspaces, client.MatchingFields{importedTemplatesIndex: key}); err != nil {
+			r.Log.Error(err, "Failed to list DevWorkspaces importing DevWorkspaceTemplate", "template", key)
+			continue
+		}
+		for _, workspace := range workspaces.Items {
+			if workspace.Spec.Started {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: workspace.Name, Namespace: workspace.Namespace},
+				})
+			}
+		}
+
+		templates := &dw.DevWorkspaceTemplateList{}
+		if err := r.Client.List(context.Background(), templates, client.MatchingFields{importedTemplatesIndex: key}); err != nil {
+			r.Log.Error(err, "Failed to list DevWorkspaceTemplates importing DevWorkspaceTemplate", "template", key)
+			continue
+		}
+		for _, template := range templates.Items {
+			queue = append(queue, templateKey(template.Namespace, template.Name))
+		}
+	}
+	return requests
+}
+
+// getTemplateUpdatePolicy returns the template update policy for a workspace, as defined by the
+// controller.devfile.io/template-update-policy attribute.
+func getTemplateUpdatePolicy(workspace *dw.DevWorkspace) (string, error) {
+	attributes := workspace.Spec.Template.Attributes
+	if !attributes.Exists(constants.TemplateUpdatePolicyAttribute) {
+		return constants.TemplateUpdatePolicyNotify, nil
+	}
+	var err error
+	policy := attributes.GetString(constants.TemplateUpdatePolicyAttribute, &err)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attribute %s: %w", constants.TemplateUpdatePolicyAttribute, err)
+	}
+	switch policy {
+	case constants.TemplateUpdatePolicyRestart, constants.TemplateUpdatePolicyNotify, constants.TemplateUpdatePolicyIgnore:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unsupported value '%s' for attribute %s", policy, constants.TemplateUpdatePolicyAttribute)
+	}
+}
+
+// importPinning describes which imports are pinned for a workspace:
+//   - imports by URI or registry ID are pinned if the controller.devfile.io/pin-resolved-plugins attribute is true
+//   - imports by Kubernetes reference are pinned while the workspace is running, unless its template update policy
+//     is "restart"
+type importPinning struct {
+	remote bool
+	// updatePolicy is the workspace's template update policy, as returned by getTemplateUpdatePolicy
+	updatePolicy string
+}
+
+func getImportPinning(workspace *dw.DevWorkspace) (importPinning, error) {
+	pinning := importPinning{}
+	if workspace.Spec.Template.Attributes.Exists(constants.PinResolvedPluginsAttribute) {
+		var err error
+		pinning.remote = workspace.Spec.Template.Attributes.GetBoolean(constants.PinResolvedPluginsAttribute, &err)
+		if err != nil {
+			return pinning, fmt.Errorf("failed to read attribute %s: %w", constants.PinResolvedPluginsAttribute, err)
+		}
+	}
+	policy, err := getTemplateUpdatePolicy(workspace)
+	if err != nil {
+		return pinning, err
+	}
+	pinning.updatePolicy = policy
+	return pinning, nil
+}
+
+// filter returns the imports whose content should be kept for reuse on subsequent reconciles
+func (p importPinning) filter(imports []flatten.ResolvedImport) []flatten.ResolvedImport {
+	var pinned []flatten.ResolvedImport
+	for _, resolvedImport := range imports {
+		if flatten.IsKubernetesImportSource(resolvedImport.Source) {
+			if p.updatePolicy != constants.TemplateUpdatePolicyRestart {
+				pinned = append(pinned, resolvedImport)
+			}
+		} else if p.remote {
+			pinned = append(pinned, resolvedImport)
+		}
+	}
+	return pinned
+}
+
+// getPinnedImports returns the subset of recorded imports that should be reused when flattening the workspace.
+// Imports by Kubernetes reference are only reused while the workspace is running, and no imports are reused if the
+// workspace has the controller.devfile.io/refresh-imports annotation.
+func getPinnedImports(workspace *dw.DevWorkspace, pinning importPinning, recordedImports []flatten.ResolvedImport) []flatten.ResolvedImport {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceRefreshImportsAnnotation]; ok {
+		return nil
+	}
+	var pinnedImports []flatten.ResolvedImport
+	for _, recorded := range pinning.filter(recordedImports) {
+		if recorded.Content == nil {
+			continue
+		}
+		if flatten.IsKubernetesImportSource(recorded.Source) && workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+			continue
+		}
+		pinnedImports = append(pinnedImports, recorded)
+	}
+	return pinnedImports
+}
+
+// setTemplateUpdateCondition sets the TemplateUpdateAvailable condition if imported DevWorkspaceTemplates have been
+// updated and the workspace's template update policy is "notify".
+func setTemplateUpdateCondition(status *currentStatus, pinning importPinning, outdated []flatten.ResolvedImport) {
+	if len(outdated) > 0 && pinning.updatePolicy == constants.TemplateUpdatePolicyNotify {
+		status.setConditionTrue(conditions.TemplateUpdateAvailable, formatOutdatedImports(outdated))
+	}
+}
+
+// formatOutdatedImports returns a message describing imported DevWorkspaceTemplates that have been updated
+func formatOutdatedImports(outdated []flatten.ResolvedImport) string {
+	var sources []string
+	for _, outdatedImport := range outdated {
+		sources = append(sources, strings.TrimPrefix(outdatedImport.Source, "kubernetes:"))
+	}
+	return fmt.Sprintf("Imported DevWorkspaceTemplates have been updated: %s. Restart the workspace to apply updates", strings.Join(sources, ", "))
+}
@@ -0,0 +1,353 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+)
+
+func TestGetImportedTemplateKeys(t *testing.T) {
+	template := &dw.DevWorkspaceTemplateSpec{
+		Parent: &dw.Parent{
+			ImportReference: dw.ImportReference{
+				ImportReferenceUnion: dw.ImportReferenceUnion{
+					Kubernetes: &dw.KubernetesCustomResourceImportReference{Name: "parent-template"},
+				},
+			},
+		},
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Components: []dw.Component{
+				getTestPluginComponent("k8s-plugin", &dw.KubernetesCustomResourceImportReference{Name: "plugin-template", Namespace: "other-ns"}),
+				{
+					Name: "uri-plugin",
+					ComponentUnion: dw.ComponentUnion{
+						Plugin: &dw.PluginComponent{
+							ImportReference: dw.ImportReference{
+								ImportReferenceUnion: dw.ImportReferenceUnion{Uri: "https://example.com/plugin.yaml"},
+							},
+						},
+					},
+				},
+				{
+					Name: "container",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"test-ns/parent-template", "other-ns/plugin-template"}, getImportedTemplateKeys("test-ns", template),
+		"Should return keys for DevWorkspaceTemplates imported by Kubernetes reference")
+	assert.Empty(t, getImportedTemplateKeys("test-ns", &dw.DevWorkspaceTemplateSpec{}), "Should return no keys if nothing is imported")
+
+	workspace := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "test-ns"},
+		Spec:       dw.DevWorkspaceSpec{Template: *template},
+	}
+	assert.Equal(t, []string{"test-ns/parent-template", "other-ns/plugin-template"}, indexWorkspaceImportedTemplates(workspace),
+		"Should index DevWorkspaces on imported DevWorkspaceTemplates")
+	dwt := &dw.DevWorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "template-ns"},
+		Spec:       *template,
+	}
+	assert.Equal(t, []string{"template-ns/parent-template", "other-ns/plugin-template"}, indexTemplateImportedTemplates(dwt),
+		"Should index DevWorkspaceTemplates on imported DevWorkspaceTemplates, defaulting to the template's namespace")
+}
+
+func TestDwtToDevWorkspaces(t *testing.T) {
+	baseRef := &dw.KubernetesCustomResourceImportReference{Name: "base"}
+	objs := []client.Object{
+		getTestTemplate("base", "test-ns", nil),
+		getTestTemplate("mid", "test-ns", baseRef),
+		getTestTemplate("top", "test-ns", &dw.KubernetesCustomResourceImportReference{Name: "mid"}),
+		getTestTemplate("cross-ns", "other-ns", &dw.KubernetesCustomResourceImportReference{Name: "base", Namespace: "test-ns"}),
+		// Templates that import each other should not cause an infinite loop
+		getTestTemplate("cycle-a", "test-ns", &dw.KubernetesCustomResourceImportReference{Name: "cycle-b"}),
+		getTestTemplate("cycle-b", "test-ns", &dw.KubernetesCustomResourceImportReference{Name: "cycle-a"}),
+		getTestTemplate("unrelated", "test-ns", nil),
+		getTestImportingWorkspace("direct", "test-ns", true, baseRef),
+		getTestImportingWorkspace("transitive", "test-ns", true, &dw.KubernetesCustomResourceImportReference{Name: "top"}),
+		getTestImportingWorkspace("cross-ns", "other-ns", true, &dw.KubernetesCustomResourceImportReference{Name: "cross-ns"}),
+		getTestImportingWorkspace("stopped", "test-ns", false, baseRef),
+		getTestImportingWorkspace("other-namespace", "other-ns", true, baseRef),
+		getTestImportingWorkspace("cycle", "test-ns", true, &dw.KubernetesCustomResourceImportReference{Name: "cycle-a"}),
+		getTestImportingWorkspace("unrelated", "test-ns", true, &dw.KubernetesCustomResourceImportReference{Name: "unrelated"}),
+	}
+	r := &DevWorkspaceReconciler{
+		Client: &indexingClient{Client: fake.NewClientBuilder().WithScheme(getTemplatesTestScheme(t)).WithObjects(objs...).Build()},
+		Log:    zap.New(),
+	}
+
+	tests := []struct {
+		template string
+		expected []string
+	}{
+		{
+			template: "base",
+			expected: []string{"test-ns/direct", "test-ns/transitive", "other-ns/cross-ns"},
+		},
+		{
+			template: "mid",
+			expected: []string{"test-ns/transitive"},
+		},
+		{
+			template: "cycle-b",
+			expected: []string{"test-ns/cycle"},
+		},
+		{
+			template: "unrelated",
+			expected: []string{"test-ns/unrelated"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			requests := r.dwtToDevWorkspaces(&dw.DevWorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.template, Namespace: "test-ns"},
+			})
+			assert.ElementsMatch(t, tt.expected, requestKeys(requests), "Should enqueue started DevWorkspaces importing template")
+		})
+	}
+}
+
+func TestGetTemplateUpdatePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		attribute   interface{}
+		expected    string
+		expectedErr string
+	}{
+		{name: "Defaults to notify", expected: constants.TemplateUpdatePolicyNotify},
+		{name: "Restart", attribute: "restart", expected: constants.TemplateUpdatePolicyRestart},
+		{name: "Notify", attribute: "notify", expected: constants.TemplateUpdatePolicyNotify},
+		{name: "Ignore", attribute: "ignore", expected: constants.TemplateUpdatePolicyIgnore},
+		{
+			name:        "Unsupported value",
+			attribute:   "always",
+			expectedErr: "unsupported value 'always' for attribute " + constants.TemplateUpdatePolicyAttribute,
+		},
+		{
+			name:        "Invalid type",
+			attribute:   map[string]string{"policy": "restart"},
+			expectedErr: "failed to read attribute " + constants.TemplateUpdatePolicyAttribute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := getTemplateUpdatePolicy(getTestPolicyWorkspace(tt.attribute))
+			if tt.expectedErr != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Contains(t, err.Error(), tt.expectedErr)
+				}
+				return
+			}
+			if assert.NoError(t, err, "Should not return error") {
+				assert.Equal(t, tt.expected, policy)
+			}
+		})
+	}
+}
+
+func TestTemplateUpdatePolicies(t *testing.T) {
+	content := &dw.DevWorkspaceTemplateSpec{}
+	kubernetesImport := flatten.ResolvedImport{Name: "parent", Source: "kubernetes:test-ns/base", Digest: "sha256:old", Content: content}
+	remoteImport := flatten.ResolvedImport{Name: "plugin", Source: "https://example.com/plugin.yaml", Digest: "sha256:old", Content: content}
+	recordedImports := []flatten.ResolvedImport{kubernetesImport, remoteImport}
+
+	tests := []struct {
+		policy                string
+		expectedPinned        []flatten.ResolvedImport
+		expectUpdateCondition bool
+	}{
+		{
+			// Template updates are applied immediately, restarting the workspace
+			policy: constants.TemplateUpdatePolicyRestart,
+		},
+		{
+			// Template updates are not applied while running, but are signalled in a condition
+			policy:                constants.TemplateUpdatePolicyNotify,
+			expectedPinned:        []flatten.ResolvedImport{kubernetesImport},
+			expectUpdateCondition: true,
+		},
+		{
+			// Template updates are silently deferred until the workspace is restarted
+			policy:         constants.TemplateUpdatePolicyIgnore,
+			expectedPinned: []flatten.ResolvedImport{kubernetesImport},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			workspace := getTestPolicyWorkspace(tt.policy)
+			workspace.Status.Phase = dw.DevWorkspaceStatusRunning
+			pinning, err := getImportPinning(workspace)
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.Equal(t, tt.expectedPinned, getPinnedImports(workspace, pinning, recordedImports),
+				"Should pin imported DevWorkspaceTemplates while running according to policy")
+
+			status := &currentStatus{}
+			setTemplateUpdateCondition(status, pinning, []flatten.ResolvedImport{kubernetesImport})
+			updateCondition, ok := status.conditions[conditions.TemplateUpdateAvailable]
+			if tt.expectUpdateCondition {
+				if assert.True(t, ok, "Should set TemplateUpdateAvailable condition") {
+					assert.Equal(t, corev1.ConditionTrue, updateCondition.Status)
+					assert.Contains(t, updateCondition.Message, "test-ns/base")
+				}
+			} else {
+				assert.False(t, ok, "Should not set TemplateUpdateAvailable condition")
+			}
+
+			workspace.Status.Phase = dw.DevWorkspaceStatusStopped
+			assert.Empty(t, getPinnedImports(workspace, pinning, recordedImports),
+				"Should apply template updates when the workspace is not running")
+		})
+	}
+}
+
+// indexingClient emulates the importedTemplatesIndex field index in List calls, as field indexes are not supported
+// by the fake client.
+type indexingClient struct {
+	client.Client
+}
+
+func (c *indexingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	if listOpts.FieldSelector == nil {
+		return c.Client.List(ctx, list, opts...)
+	}
+	key, ok := listOpts.FieldSelector.RequiresExactMatch(importedTemplatesIndex)
+	if !ok {
+		return fmt.Errorf("unsupported field selector %s", listOpts.FieldSelector)
+	}
+	listOpts.FieldSelector = nil
+	if err := c.Client.List(ctx, list, listOpts); err != nil {
+		return err
+	}
+	switch l := list.(type) {
+	case *dw.DevWorkspaceList:
+		var items []dw.DevWorkspace
+		for idx := range l.Items {
+			if containsKey(indexWorkspaceImportedTemplates(&l.Items[idx]), key) {
+				items = append(items, l.Items[idx])
+			}
+		}
+		l.Items = items
+	case *dw.DevWorkspaceTemplateList:
+		var items []dw.DevWorkspaceTemplate
+		for idx := range l.Items {
+			if containsKey(indexTemplateImportedTemplates(&l.Items[idx]), key) {
+				items = append(items, l.Items[idx])
+			}
+		}
+		l.Items = items
+	default:
+		return fmt.Errorf("unsupported list type %T", list)
+	}
+	return nil
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func getTemplatesTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := dw.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return scheme
+}
+
+func getTestTemplate(name, namespace string, parent *dw.KubernetesCustomResourceImportReference) *dw.DevWorkspaceTemplate {
+	template := &dw.DevWorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	if parent != nil {
+		template.Spec.Parent = &dw.Parent{
+			ImportReference: dw.ImportReference{
+				ImportReferenceUnion: dw.ImportReferenceUnion{Kubernetes: parent},
+			},
+		}
+	}
+	return template
+}
+
+func getTestImportingWorkspace(name, namespace string, started bool, plugin *dw.KubernetesCustomResourceImportReference) *dw.DevWorkspace {
+	return &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: dw.DevWorkspaceSpec{
+			Started: started,
+			Template: dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Components: []dw.Component{getTestPluginComponent("plugin", plugin)},
+				},
+			},
+		},
+	}
+}
+
+func getTestPluginComponent(name string, ref *dw.KubernetesCustomResourceImportReference) dw.Component {
+	return dw.Component{
+		Name: name,
+		ComponentUnion: dw.ComponentUnion{
+			Plugin: &dw.PluginComponent{
+				ImportReference: dw.ImportReference{
+					ImportReferenceUnion: dw.ImportReferenceUnion{Kubernetes: ref},
+				},
+			},
+		},
+	}
+}
+
+func getTestPolicyWorkspace(policy interface{}) *dw.DevWorkspace {
+	workspace := &dw.DevWorkspace{}
+	if policy != nil {
+		workspace.Spec.Template.Attributes = attributes.Attributes{}.Put(constants.TemplateUpdatePolicyAttribute, policy, nil)
+	}
+	return workspace
+}
+
+func requestKeys(requests []reconcile.Request) []string {
+	var keys []string
+	for _, request := range requests {
+		keys = append(keys, request.NamespacedName.String())
+	}
+	return keys
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspaceprebuild"
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting"
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting/solvers"
 	"github.com/devfile/devworkspace-operator/pkg/cache"
@@ -44,6 +45,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -90,7 +92,10 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var prebuildWebhookAddr string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
+	flag.StringVar(&prebuildWebhookAddr, "prebuild-webhook-addr", ":8090",
+		"The address the endpoint for git push events that trigger prebuilds binds to. Set to \"0\" to disable.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -135,6 +140,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to initialize kubernetes clientset")
+		os.Exit(1)
+	}
+
 	// Index Events on involvedObject.name to allow us to get events involving a DevWorkspace's pod(s). This is used to
 	// check for issues that prevent the pod from starting, so that DevWorkspaces aren't just hanging indefinitely.
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Event{}, "involvedObject.name", func(obj client.Object) []string {
@@ -145,6 +156,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Index Events on reason to allow us to find Preempted events for workspace pods that were already removed.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Event{}, "reason", func(obj client.Object) []string {
+		ev := obj.(*corev1.Event)
+		return []string{ev.Reason}
+	}); err != nil {
+		setupLog.Error(err, "unable to update indexer to include event reasons")
+		os.Exit(1)
+	}
+
 	if err = (&devworkspacerouting.DevWorkspaceRoutingReconciler{
 		Client:       mgr.GetClient(),
 		Log:          ctrl.Log.WithName("controllers").WithName("DevWorkspaceRouting"),
@@ -155,11 +175,34 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceRouting")
 		os.Exit(1)
 	}
+	if err = (&devworkspaceprebuild.DevWorkspacePrebuildReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("DevWorkspacePrebuild"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DevWorkspacePrebuild")
+		os.Exit(1)
+	}
+	if prebuildWebhookAddr != "0" {
+		if err := mgr.Add(&devworkspaceprebuild.PushWebhookServer{
+			Addr: prebuildWebhookAddr,
+			Handler: &devworkspaceprebuild.PushWebhookHandler{
+				Client:    mgr.GetClient(),
+				APIReader: mgr.GetAPIReader(),
+				Log:       ctrl.Log.WithName("prebuild-push-webhook"),
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to set up prebuild push webhook server")
+			os.Exit(1)
+		}
+	}
 	if err = (&workspacecontroller.DevWorkspaceReconciler{
 		Client:           mgr.GetClient(),
 		NonCachingClient: nonCachingClient,
+		Clientset:        clientset,
 		Log:              ctrl.Log.WithName("controllers").WithName("DevWorkspace"),
 		Scheme:           mgr.GetScheme(),
+		Recorder:         mgr.GetEventRecorderFor("devworkspace-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DevWorkspace")
 		os.Exit(1)
@@ -39,6 +39,11 @@ func GetCacheFunc() (cache.NewCacheFunc, error) {
 		return nil, err
 	}
 
+	prebuildObjectSelector, err := labels.Parse(constants.DevWorkspacePrebuildLabel)
+	if err != nil {
+		return nil, err
+	}
+
 	// We have to treat secrets and configmaps separately since we need to support auto-mounting
 	secretObjectSelector, err := labels.Parse(fmt.Sprintf("%s=true", constants.DevWorkspaceWatchSecretLabel))
 	if err != nil {
@@ -59,6 +64,9 @@ func GetCacheFunc() (cache.NewCacheFunc, error) {
 		&batchv1.Job{}: {
 			Label: devworkspaceObjectSelector,
 		},
+		&batchv1.CronJob{}: {
+			Label: prebuildObjectSelector,
+		},
 		&corev1.ServiceAccount{}: {
 			Label: devworkspaceObjectSelector,
 		},
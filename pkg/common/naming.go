@@ -16,6 +16,7 @@
 package common
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"regexp"
 	"strings"
@@ -82,6 +83,18 @@ func DeploymentName(workspaceId string) string {
 	return workspaceId
 }
 
+// DedicatedPodName returns the name used for the deployment and service created for a container component with
+// dedicatedPod set. Names longer than 63 characters are truncated and suffixed with a hash of the component name to
+// avoid collisions between components.
+func DedicatedPodName(workspaceId, componentName string) string {
+	name := fmt.Sprintf("%s-%s", workspaceId, componentName)
+	if len(name) > 63 {
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(componentName)))[:8]
+		name = fmt.Sprintf("%s-%s", strings.TrimRight(name[:63-len(hash)-1], "-"), hash)
+	}
+	return name
+}
+
 func ServingCertVolumeName(serviceName string) string {
 	return fmt.Sprintf("devworkspace-serving-cert-%s", serviceName)
 }
@@ -90,6 +103,18 @@ func PVCCleanupJobName(workspaceId string) string {
 	return fmt.Sprintf("cleanup-%s", workspaceId)
 }
 
+func ImageBuildJobName(workspaceId, componentName string) string {
+	name := fmt.Sprintf("%s-build-%s", workspaceId, componentName)
+	if len(name) > 63 {
+		name = strings.TrimSuffix(name[:63], "-")
+	}
+	return name
+}
+
+func ImageBuildAuthSecretName(workspaceId string) string {
+	return fmt.Sprintf("%s-build-auth", workspaceId)
+}
+
 func PerWorkspacePVCName(workspaceId string) string {
 	return fmt.Sprintf("storage-%s", workspaceId)
 }
@@ -98,6 +123,18 @@ func MetadataConfigMapName(workspaceId string) string {
 	return fmt.Sprintf("%s-metadata", workspaceId)
 }
 
+func FailedStartLogsConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-failed-start-logs", workspaceId)
+}
+
+func PinnedImportsConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-pinned-imports", workspaceId)
+}
+
+func ContainerStatusConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-container-status", workspaceId)
+}
+
 // We can't add prefixes to automount volume names, as adding any characters
 // can potentially push the name over the 63 character limit (if the original
 // object has a long name)
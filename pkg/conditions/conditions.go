@@ -24,6 +24,20 @@ const (
 	StorageReady         dw.DevWorkspaceConditionType = "StorageReady"
 	DeploymentReady      dw.DevWorkspaceConditionType = "DeploymentReady"
 	DevWorkspaceWarning  dw.DevWorkspaceConditionType = "DevWorkspaceWarning"
+	// TemplateUpdateAvailable is set on running DevWorkspaces that use the "notify" template update policy when a
+	// DevWorkspaceTemplate they import has changed
+	TemplateUpdateAvailable dw.DevWorkspaceConditionType = "TemplateUpdateAvailable"
+	// ImageBuildReady is set on DevWorkspaces that build image components, and reports the status of image builds
+	ImageBuildReady dw.DevWorkspaceConditionType = "ImageBuildReady"
+	// EndpointsReady is set on DevWorkspaces that declare health checks on endpoints, and reports the readiness of
+	// each checked endpoint
+	EndpointsReady dw.DevWorkspaceConditionType = "EndpointsReady"
+	// ContainersReady reports whether all containers in a DevWorkspace's pods are ready, and references the configmap
+	// storing the state of each container (including init containers)
+	ContainersReady dw.DevWorkspaceConditionType = "ContainersReady"
+	// FailedStartLogs is set on DevWorkspaces that failed to start, and references the configmap storing the logs of
+	// containers that failed
+	FailedStartLogs dw.DevWorkspaceConditionType = "FailedStartLogs"
 )
 
 func GetConditionByType(conditions []dw.DevWorkspaceCondition, t dw.DevWorkspaceConditionType) *dw.DevWorkspaceCondition {
@@ -17,6 +17,7 @@ package config
 
 import (
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -24,8 +25,9 @@ import (
 // defaultConfig represents the default configuration for the DevWorkspace Operator.
 var defaultConfig = &v1alpha1.OperatorConfiguration{
 	Routing: &v1alpha1.RoutingConfig{
-		DefaultRoutingClass: "basic",
-		ClusterHostSuffix:   "", // is auto discovered when running on OpenShift. Must be defined by CR on Kubernetes.
+		DefaultRoutingClass:        "basic",
+		ClusterHostSuffix:          "", // is auto discovered when running on OpenShift. Must be defined by CR on Kubernetes.
+		AllowedEndpointAnnotations: []string{"nginx.ingress.kubernetes.io/proxy-*"},
 	},
 	Workspace: &v1alpha1.WorkspaceConfig{
 		ImagePullPolicy: "Always",
@@ -43,6 +45,23 @@ var defaultConfig = &v1alpha1.OperatorConfiguration{
 			RunAsNonRoot: &boolTrue,
 			FSGroup:      &int64UID,
 		},
+		ImageBuild: &v1alpha1.ImageBuildConfig{
+			Builder:               "kaniko",
+			AllowRootBuilds:       &boolFalse,
+			AllowPrivilegedBuilds: &boolFalse,
+		},
+		DefaultContainerResources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(constants.SidecarDefaultMemoryLimit),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(constants.SidecarDefaultMemoryRequest),
+			},
+		},
+		FailedStartLogLines: &failedStartLogLines,
+	},
+	Registry: &v1alpha1.RegistryConfig{
+		CacheTTL: "5m",
 	},
 }
 
@@ -54,4 +73,5 @@ var (
 	int64GID                = int64(0)
 	commonStorageSize       = resource.MustParse("10Gi")
 	perWorkspaceStorageSize = resource.MustParse("5Gi")
+	failedStartLogLines     = 100
 )
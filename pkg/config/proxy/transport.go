@@ -0,0 +1,39 @@
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// NewTransport returns a copy of the default HTTP transport that sends requests through the proxy described by
+// proxyConfig. If proxyConfig is nil, the transport's proxy settings are left unchanged.
+func NewTransport(proxyConfig *controller.Proxy) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyConfig != nil {
+		proxyConf := httpproxy.Config{
+			HTTPProxy:  proxyConfig.HttpProxy,
+			HTTPSProxy: proxyConfig.HttpsProxy,
+			NoProxy:    proxyConfig.NoProxy,
+		}
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyConf.ProxyFunc()(req.URL)
+		}
+	}
+	return transport
+}
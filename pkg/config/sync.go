@@ -18,11 +18,16 @@ package config
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/devfile/devworkspace-operator/pkg/config/proxy"
 	routeV1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,6 +36,7 @@ import (
 
 	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
 
 const (
@@ -41,6 +47,7 @@ const (
 var (
 	Routing         *controller.RoutingConfig
 	Workspace       *controller.WorkspaceConfig
+	Registry        *controller.RegistryConfig
 	internalConfig  *controller.OperatorConfiguration
 	configMutex     sync.Mutex
 	configNamespace string
@@ -108,6 +115,61 @@ func ExperimentalFeaturesEnabled() bool {
 	return *internalConfig.EnableExperimentalFeatures
 }
 
+// GetRegistryCacheTTL returns the configured TTL for cached devfile registry content. If the configured value
+// cannot be parsed, the default value is used.
+func GetRegistryCacheTTL() time.Duration {
+	registryConfig := Registry
+	if registryConfig != nil && registryConfig.CacheTTL != "" {
+		if ttl, err := time.ParseDuration(registryConfig.CacheTTL); err == nil {
+			return ttl
+		}
+	}
+	ttl, _ := time.ParseDuration(defaultConfig.Registry.CacheTTL)
+	return ttl
+}
+
+// GetRegistryCacheConfig returns the configuration used for caching devfiles and plugins fetched from registries
+// and URIs.
+func GetRegistryCacheConfig() network.CacheConfig {
+	return network.CacheConfig{
+		TTL:     GetRegistryCacheTTL(),
+		Mirrors: GetRegistryMirrors(),
+	}
+}
+
+// GetRegistryMirrors returns the configured registry mirrors as a map from registry URL to the list of its mirrors.
+func GetRegistryMirrors() map[string][]string {
+	mirrors := map[string][]string{}
+	registryConfig := Registry
+	if registryConfig == nil {
+		return mirrors
+	}
+	for _, mirror := range registryConfig.Mirrors {
+		mirrors[mirror.Registry] = append(mirrors[mirror.Registry], mirror.Mirrors...)
+	}
+	return mirrors
+}
+
+// ReadOperatorConfig reads the DevWorkspaceOperatorConfig from the operator's namespace and returns it merged with the
+// default configuration. Unlike SetupControllerConfig, the configuration used by the current process is not updated and
+// cluster-specific defaults (e.g. routing suffix and proxy settings) are not discovered. This is intended for components
+// that do not run the controller, such as the webhook server.
+func ReadOperatorConfig(client crclient.Client) (*controller.OperatorConfiguration, error) {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+	clusterConfig, err := getClusterConfig(namespace, client)
+	if err != nil {
+		return nil, err
+	}
+	config := defaultConfig.DeepCopy()
+	if clusterConfig != nil {
+		mergeConfig(clusterConfig.Config, config)
+	}
+	return config, nil
+}
+
 func getClusterConfig(namespace string, client crclient.Client) (*controller.DevWorkspaceOperatorConfig, error) {
 	clusterConfig := &controller.DevWorkspaceOperatorConfig{}
 	if err := client.Get(context.Background(), types.NamespacedName{Name: OperatorConfigName, Namespace: namespace}, clusterConfig); err != nil {
@@ -140,6 +202,7 @@ func restoreDefaultConfig() {
 func updatePublicConfig() {
 	Routing = internalConfig.Routing.DeepCopy()
 	Workspace = internalConfig.Workspace.DeepCopy()
+	Registry = internalConfig.Registry.DeepCopy()
 	logCurrentConfig()
 }
 
@@ -205,6 +268,9 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 		if from.Routing.ClusterHostSuffix != "" {
 			to.Routing.ClusterHostSuffix = from.Routing.ClusterHostSuffix
 		}
+		if from.Routing.AllowedEndpointAnnotations != nil {
+			to.Routing.AllowedEndpointAnnotations = from.Routing.AllowedEndpointAnnotations
+		}
 		if from.Routing.ProxyConfig != nil {
 			if to.Routing.ProxyConfig == nil {
 				to.Routing.ProxyConfig = &controller.Proxy{}
@@ -240,6 +306,51 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 		if from.Workspace.PodSecurityContext != nil {
 			to.Workspace.PodSecurityContext = from.Workspace.PodSecurityContext
 		}
+		if from.Workspace.ImageBuild != nil {
+			if to.Workspace.ImageBuild == nil {
+				to.Workspace.ImageBuild = &controller.ImageBuildConfig{}
+			}
+			if from.Workspace.ImageBuild.Builder != "" {
+				to.Workspace.ImageBuild.Builder = from.Workspace.ImageBuild.Builder
+			}
+			if from.Workspace.ImageBuild.BuilderImage != "" {
+				to.Workspace.ImageBuild.BuilderImage = from.Workspace.ImageBuild.BuilderImage
+			}
+			if from.Workspace.ImageBuild.AllowRootBuilds != nil {
+				to.Workspace.ImageBuild.AllowRootBuilds = from.Workspace.ImageBuild.AllowRootBuilds
+			}
+			if from.Workspace.ImageBuild.AllowPrivilegedBuilds != nil {
+				to.Workspace.ImageBuild.AllowPrivilegedBuilds = from.Workspace.ImageBuild.AllowPrivilegedBuilds
+			}
+		}
+		if from.Workspace.DevfileVariables != nil {
+			to.Workspace.DevfileVariables = from.Workspace.DevfileVariables
+		}
+		if from.Workspace.DefaultContainerResources != nil {
+			to.Workspace.DefaultContainerResources = mergeResources(from.Workspace.DefaultContainerResources, to.Workspace.DefaultContainerResources)
+		}
+		if from.Workspace.ContainerResourceBounds != nil {
+			to.Workspace.ContainerResourceBounds = from.Workspace.ContainerResourceBounds.DeepCopy()
+		}
+		if from.Workspace.CreatorQuota != nil {
+			to.Workspace.CreatorQuota = from.Workspace.CreatorQuota.DeepCopy()
+		}
+		if from.Workspace.DefaultPriorityClassName != "" {
+			to.Workspace.DefaultPriorityClassName = from.Workspace.DefaultPriorityClassName
+		}
+		if from.Workspace.AllowedPriorityClassNames != nil {
+			to.Workspace.AllowedPriorityClassNames = from.Workspace.AllowedPriorityClassNames
+		}
+		if from.Workspace.Affinity != nil {
+			to.Workspace.Affinity = from.Workspace.Affinity
+		}
+		if from.Workspace.TopologySpreadConstraints != nil {
+			to.Workspace.TopologySpreadConstraints = from.Workspace.TopologySpreadConstraints
+		}
+		if from.Workspace.FailedStartLogLines != nil {
+			failedStartLogLines := *from.Workspace.FailedStartLogLines
+			to.Workspace.FailedStartLogLines = &failedStartLogLines
+		}
 		if from.Workspace.DefaultStorageSize != nil {
 			if to.Workspace.DefaultStorageSize == nil {
 				to.Workspace.DefaultStorageSize = &controller.StorageSizes{}
@@ -254,6 +365,38 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 			}
 		}
 	}
+	if from.Registry != nil {
+		if to.Registry == nil {
+			to.Registry = &controller.RegistryConfig{}
+		}
+		if from.Registry.CacheTTL != "" {
+			to.Registry.CacheTTL = from.Registry.CacheTTL
+		}
+		if from.Registry.Mirrors != nil {
+			to.Registry.Mirrors = from.Registry.Mirrors
+		}
+	}
+}
+
+// mergeResources returns a copy of to with each limit and request defined in from applied to it
+func mergeResources(from, to *corev1.ResourceRequirements) *corev1.ResourceRequirements {
+	merged := &corev1.ResourceRequirements{}
+	if to != nil {
+		merged = to.DeepCopy()
+	}
+	for name, limit := range from.Limits {
+		if merged.Limits == nil {
+			merged.Limits = corev1.ResourceList{}
+		}
+		merged.Limits[name] = limit.DeepCopy()
+	}
+	for name, request := range from.Requests {
+		if merged.Requests == nil {
+			merged.Requests = corev1.ResourceList{}
+		}
+		merged.Requests[name] = request.DeepCopy()
+	}
+	return merged
 }
 
 // logCurrentConfig formats the current operator configuration as a plain string
@@ -261,6 +404,21 @@ func logCurrentConfig() {
 	if internalConfig == nil {
 		return
 	}
+	config := formatCurrentConfig()
+	if len(config) == 0 {
+		log.Info("Updated config to [(default config)]")
+	} else {
+		log.Info(fmt.Sprintf("Updated config to [%s]", strings.Join(config, ",")))
+	}
+
+	if internalConfig.Routing.ProxyConfig != nil {
+		log.Info("Resolved proxy configuration", "proxy", internalConfig.Routing.ProxyConfig)
+	}
+}
+
+// formatCurrentConfig returns the fields of the current operator configuration that differ from the default
+// configuration, formatted as <field>=<value>. Fields are returned in a stable order.
+func formatCurrentConfig() []string {
 	var config []string
 	if Routing != nil {
 		if Routing.ClusterHostSuffix != "" && Routing.ClusterHostSuffix != defaultConfig.Routing.ClusterHostSuffix {
@@ -269,6 +427,10 @@ func logCurrentConfig() {
 		if Routing.DefaultRoutingClass != defaultConfig.Routing.DefaultRoutingClass {
 			config = append(config, fmt.Sprintf("routing.defaultRoutingClass=%s", Routing.DefaultRoutingClass))
 		}
+		if !reflect.DeepEqual(Routing.AllowedEndpointAnnotations, defaultConfig.Routing.AllowedEndpointAnnotations) {
+			config = append(config, fmt.Sprintf("routing.allowedEndpointAnnotations=%s",
+				strings.Join(Routing.AllowedEndpointAnnotations, ";")))
+		}
 	}
 	if Workspace != nil {
 		if Workspace.ImagePullPolicy != defaultConfig.Workspace.ImagePullPolicy {
@@ -295,18 +457,78 @@ func logCurrentConfig() {
 				config = append(config, fmt.Sprintf("workspace.defaultStorageSize.perWorkspace=%s", Workspace.DefaultStorageSize.PerWorkspace.String()))
 			}
 		}
+		if Workspace.ImageBuild != nil {
+			if Workspace.ImageBuild.Builder != defaultConfig.Workspace.ImageBuild.Builder {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.builder=%s", Workspace.ImageBuild.Builder))
+			}
+			if Workspace.ImageBuild.BuilderImage != "" {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.builderImage=%s", Workspace.ImageBuild.BuilderImage))
+			}
+			if Workspace.ImageBuild.AllowRootBuilds != nil && *Workspace.ImageBuild.AllowRootBuilds {
+				config = append(config, "workspace.imageBuild.allowRootBuilds=true")
+			}
+			if Workspace.ImageBuild.AllowPrivilegedBuilds != nil && *Workspace.ImageBuild.AllowPrivilegedBuilds {
+				config = append(config, "workspace.imageBuild.allowPrivilegedBuilds=true")
+			}
+		}
+		var variableNames []string
+		for name := range Workspace.DevfileVariables {
+			variableNames = append(variableNames, name)
+		}
+		sort.Strings(variableNames)
+		for _, name := range variableNames {
+			config = append(config, fmt.Sprintf("workspace.devfileVariables[%s]=%s", name, Workspace.DevfileVariables[name]))
+		}
+		if Workspace.DefaultContainerResources != nil && !equality.Semantic.DeepEqual(Workspace.DefaultContainerResources, defaultConfig.Workspace.DefaultContainerResources) {
+			config = append(config, formatResourceList("workspace.defaultContainerResources.limits", Workspace.DefaultContainerResources.Limits)...)
+			config = append(config, formatResourceList("workspace.defaultContainerResources.requests", Workspace.DefaultContainerResources.Requests)...)
+		}
+		if Workspace.ContainerResourceBounds != nil {
+			config = append(config, formatResourceList("workspace.containerResourceBounds.min", Workspace.ContainerResourceBounds.Min)...)
+			config = append(config, formatResourceList("workspace.containerResourceBounds.max", Workspace.ContainerResourceBounds.Max)...)
+		}
+		if Workspace.CreatorQuota != nil {
+			if Workspace.CreatorQuota.MaxRunningWorkspaces != nil {
+				config = append(config, fmt.Sprintf("workspace.creatorQuota.maxRunningWorkspaces=%d", *Workspace.CreatorQuota.MaxRunningWorkspaces))
+			}
+			config = append(config, formatResourceList("workspace.creatorQuota.maxResources", Workspace.CreatorQuota.MaxResources)...)
+		}
+		if Workspace.DefaultPriorityClassName != "" {
+			config = append(config, fmt.Sprintf("workspace.defaultPriorityClassName=%s", Workspace.DefaultPriorityClassName))
+		}
+		if Workspace.AllowedPriorityClassNames != nil {
+			config = append(config, fmt.Sprintf("workspace.allowedPriorityClassNames=%s",
+				strings.Join(Workspace.AllowedPriorityClassNames, ";")))
+		}
+		if Workspace.FailedStartLogLines != nil && *Workspace.FailedStartLogLines != *defaultConfig.Workspace.FailedStartLogLines {
+			config = append(config, fmt.Sprintf("workspace.failedStartLogLines=%d", *Workspace.FailedStartLogLines))
+		}
+	}
+	if Registry != nil {
+		if Registry.CacheTTL != defaultConfig.Registry.CacheTTL {
+			config = append(config, fmt.Sprintf("registry.cacheTTL=%s", Registry.CacheTTL))
+		}
+		for _, mirror := range Registry.Mirrors {
+			config = append(config, fmt.Sprintf("registry.mirrors[%s]=%s", mirror.Registry, strings.Join(mirror.Mirrors, ";")))
+		}
 	}
 	if internalConfig.EnableExperimentalFeatures != nil && *internalConfig.EnableExperimentalFeatures {
 		config = append(config, "enableExperimentalFeatures=true")
 	}
+	return config
+}
 
-	if len(config) == 0 {
-		log.Info("Updated config to [(default config)]")
-	} else {
-		log.Info(fmt.Sprintf("Updated config to [%s]", strings.Join(config, ",")))
+// formatResourceList formats each resource in resources as <field>.<resource name>=<quantity>, sorted by resource name
+func formatResourceList(field string, resources corev1.ResourceList) []string {
+	var names []string
+	for name := range resources {
+		names = append(names, string(name))
 	}
-
-	if internalConfig.Routing.ProxyConfig != nil {
-		log.Info("Resolved proxy configuration", "proxy", internalConfig.Routing.ProxyConfig)
+	sort.Strings(names)
+	var formatted []string
+	for _, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		formatted = append(formatted, fmt.Sprintf("%s.%s=%s", field, name, quantity.String()))
 	}
+	return formatted
 }
@@ -17,12 +17,15 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	fuzz "github.com/google/gofuzz"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -233,6 +236,75 @@ func TestSyncConfigDoesNotEraseClusterRoutingSuffix(t *testing.T) {
 	assert.Equal(t, "test-host", Routing.ClusterHostSuffix, "clusterHostSuffix should persist after an update")
 }
 
+func TestFormatCurrentConfigSortsDevfileVariables(t *testing.T) {
+	setupForTest(t)
+	internalConfig = defaultConfig.DeepCopy()
+	syncConfigFrom(buildConfig(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			DevfileVariables: map[string]string{
+				"registry_host": "registry.example.com",
+				"cluster_name":  "test-cluster",
+				"team":          "test-team",
+			},
+		},
+	}))
+	expected := []string{
+		"workspace.devfileVariables[cluster_name]=test-cluster",
+		"workspace.devfileVariables[registry_host]=registry.example.com",
+		"workspace.devfileVariables[team]=test-team",
+	}
+	for i := 0; i < 10; i++ {
+		var variables []string
+		for _, field := range formatCurrentConfig() {
+			if strings.HasPrefix(field, "workspace.devfileVariables") {
+				variables = append(variables, field)
+			}
+		}
+		assert.Equal(t, expected, variables, "Devfile variables should be formatted in sorted order")
+	}
+}
+
+func TestFormatCurrentConfigSkipsDefaultContainerResources(t *testing.T) {
+	setupForTest(t)
+	internalConfig = defaultConfig.DeepCopy()
+	for _, field := range formatCurrentConfig() {
+		assert.False(t, strings.HasPrefix(field, "workspace.defaultContainerResources"),
+			"Default container resources should not be formatted when unchanged: %s", field)
+	}
+
+	syncConfigFrom(buildConfig(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			DefaultContainerResources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory:           resource.MustParse("1Gi"),
+					corev1.ResourceCPU:              resource.MustParse("1"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+				},
+			},
+		},
+	}))
+	expected := []string{
+		"workspace.defaultContainerResources.limits.cpu=1",
+		"workspace.defaultContainerResources.limits.ephemeral-storage=2Gi",
+		"workspace.defaultContainerResources.limits.memory=1Gi",
+		"workspace.defaultContainerResources.requests.cpu=100m",
+		"workspace.defaultContainerResources.requests.memory=256Mi",
+	}
+	for i := 0; i < 10; i++ {
+		var resources []string
+		for _, field := range formatCurrentConfig() {
+			if strings.HasPrefix(field, "workspace.defaultContainerResources") {
+				resources = append(resources, field)
+			}
+		}
+		assert.Equal(t, expected, resources, "Container resources should be formatted in sorted order")
+	}
+}
+
 func TestMergeConfigLooksAtAllFields(t *testing.T) {
 	f := fuzz.New().NilChance(0)
 	expectedConfig := &v1alpha1.OperatorConfiguration{}
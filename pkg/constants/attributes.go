@@ -31,6 +31,57 @@ const (
 	// components in the DevWorkspace (pod.spec.runtimeClassName). If empty, no runtimeClassName is added.
 	RuntimeClassNameAttribute = "controller.devfile.io/runtime-class"
 
+	// PriorityClassAttribute is an attribute added to a DevWorkspace to specify a priorityClassName for the workspace
+	// pod (pod.spec.priorityClassName). If empty, the default priority class from the DevWorkspaceOperatorConfig is used.
+	// If the DevWorkspaceOperatorConfig defines a list of allowed priority classes, the value of this attribute must be
+	// one of them.
+	PriorityClassAttribute = "controller.devfile.io/priority-class"
+
+	// PodOverridesAttribute is an attribute added to a DevWorkspace to override fields of the workspace pod. The value
+	// of the attribute should follow the structure of a Kubernetes PodTemplateSpec, and is applied to the pod template
+	// of the workspace deployment as a strategic merge patch, e.g.
+	//
+	//   attributes:
+	//     controller.devfile.io/pod-overrides:
+	//       metadata:
+	//         annotations:
+	//           example.com/annotation: value
+	//       spec:
+	//         terminationGracePeriodSeconds: 30
+	//         affinity:
+	//           nodeAffinity: ...
+	//
+	// The pod's affinity and topologySpreadConstraints are merged with values defined for the namespace and in the
+	// DevWorkspaceOperatorConfig, with values defined here taking precedence. Fields managed by the DevWorkspace Operator
+	// (existing labels, annotations, and volumes, containers, serviceAccountName, restartPolicy, and priorityClassName)
+	// cannot be overridden. Setting hostNetwork, hostPID, hostIPC, hostPath volumes, nodeName, tolerations, or the pod
+	// securityContext requires the user to have permission to "use" the corresponding name of the "podoverrides"
+	// resource in the "controller.devfile.io" API group; these fields cannot be set by attributes imported from a
+	// parent.
+	PodOverridesAttribute = "controller.devfile.io/pod-overrides"
+
+	// ContainerOverridesAttribute is an attribute added to a container component to override fields of the Kubernetes
+	// container generated for it. The value of the attribute is applied as a strategic merge patch to the container,
+	// allowing fields that cannot be expressed in a devfile to be set, e.g.
+	//
+	//   components:
+	//     - name: tools
+	//       attributes:
+	//         controller.devfile.io/container-overrides:
+	//           securityContext:
+	//             runAsNonRoot: true
+	//           resources:
+	//             limits:
+	//               nvidia.com/gpu: 1
+	//       container:
+	//         image: ...
+	//
+	// Fields that are managed by the DevWorkspace Operator (the container's name, volumeMounts, and the environment
+	// variables it sets for project sources) cannot be overridden, and the securityContext cannot grant additional
+	// privileges (privileged, allowPrivilegeEscalation, running as UID or GID 0, added capabilities, or an unmasked
+	// procMount).
+	ContainerOverridesAttribute = "controller.devfile.io/container-overrides"
+
 	// WorkspaceEnvAttribute is an attribute that specifies a set of environment variables provided by a component
 	// that should be added to all workspace containers. The structure of the attribute value should be a list of
 	// Devfile 2.0 EnvVar, e.g.
@@ -65,6 +116,51 @@ const (
 	//               will not be cloned into the workspace on start.
 	ProjectCloneAttribute = "controller.devfile.io/project-clone"
 
+	// StarterProjectAttribute selects a starter project from the DevWorkspace's starterProjects to be set up in the
+	// workspace when it is first started. The starter project is cloned or extracted into $PROJECTS_ROOT/<name>,
+	// where <name> is the name of the starter project; if the starter project defines a subDir, only that
+	// subdirectory is used. This attribute must be applied to the top-level attributes field in the DevWorkspace:
+	//
+	//     spec:
+	//       template:
+	//         attributes:
+	//           controller.devfile.io/use-starter-project: "my-starter-project"
+	//
+	// The starter project is only set up once; changing or removing this attribute does not affect workspaces
+	// where a starter project has already been set up.
+	StarterProjectAttribute = "controller.devfile.io/use-starter-project"
+
+	// PinResolvedPluginsAttribute configures whether content resolved for parents and plugins imported by URI or
+	// registry ID should be pinned. The source and digest of each import are recorded in the DevWorkspace's
+	// controller.devfile.io/resolved-imports annotation; if this attribute is "true", the resolved content is stored
+	// and reused instead of being fetched again, ensuring that updates to a registry do not change the workspace. To
+	// refresh pinned content, the controller.devfile.io/refresh-imports annotation can be added to the DevWorkspace.
+	// This attribute must be applied to the top-level attributes field in the DevWorkspace:
+	//
+	//     spec:
+	//       template:
+	//         attributes:
+	//           controller.devfile.io/pin-resolved-plugins: true
+	PinResolvedPluginsAttribute = "controller.devfile.io/pin-resolved-plugins"
+
+	// TemplateUpdatePolicyAttribute configures how a running DevWorkspace handles changes to the DevWorkspaceTemplates
+	// it imports (as a parent or plugin) by Kubernetes reference. This attribute must be applied to the top-level
+	// attributes field in the DevWorkspace.
+	// Supported options:
+	// - "restart" - Apply changes to the workspace immediately, restarting it if necessary.
+	// - "notify"  - Keep using the previous content until the workspace is restarted, and set the
+	//               TemplateUpdateAvailable condition on the DevWorkspace to signal that an update is available.
+	//               This is the default.
+	// - "ignore"  - Keep using the previous content until the workspace is restarted.
+	TemplateUpdatePolicyAttribute = "controller.devfile.io/template-update-policy"
+
+	// TemplateUpdatePolicyRestart is the value for TemplateUpdatePolicyAttribute that applies template changes immediately
+	TemplateUpdatePolicyRestart = "restart"
+	// TemplateUpdatePolicyNotify is the value for TemplateUpdatePolicyAttribute that signals template changes in a condition
+	TemplateUpdatePolicyNotify = "notify"
+	// TemplateUpdatePolicyIgnore is the value for TemplateUpdatePolicyAttribute that ignores template changes until restart
+	TemplateUpdatePolicyIgnore = "ignore"
+
 	// PluginSourceAttribute is an attribute added to components, commands, and projects in a flattened
 	// DevWorkspace representation to signify where the respective component came from (i.e. which plugin
 	// or parent imported it)
@@ -73,4 +169,18 @@ const (
 	// EndpointURLAttribute is an attribute added to endpoints to denote the endpoint on the cluster that
 	// was created to route to this endpoint
 	EndpointURLAttribute = "controller.devfile.io/endpoint-url"
+
+	// EndpointHealthCheckAttribute is an attribute added to endpoints to declare how the DevWorkspace Operator should
+	// check that the endpoint is ready before marking the DevWorkspace as running. The value of this attribute is
+	// an object with fields
+	//
+	// - type: one of "http", "tcp", or "none"
+	//
+	// - path: for "http" checks, the path that should be requested, relative to the endpoint's URL
+	//
+	// - expectedStatus: for "http" checks, the expected response status code. If unset, any 2XX status is accepted.
+	//
+	// If the main endpoint of a DevWorkspace does not define this attribute, the DevWorkspace's main URL is checked by
+	// requesting the path "healthz".
+	EndpointHealthCheckAttribute = "controller.devfile.io/health-check"
 )
@@ -37,4 +37,8 @@ const (
 	// DevWorkspaceComponentName contains env var name which indicates from which devfile container component
 	// the container is created from. Note the flattened devfile is used to evaluate it.
 	DevWorkspaceComponentName = "DEVWORKSPACE_COMPONENT_NAME"
+
+	// ProjectClonePrebuildPath contains env var name which value is the path where the latest prebuild for
+	// the DevWorkspace is mounted in the project-clone container. If unset, no prebuild is available.
+	ProjectClonePrebuildPath = "PROJECT_CLONE_PREBUILD_PATH"
 )
@@ -31,6 +31,14 @@ const (
 	// DevWorkspaceNameLabel is the label key to store workspace name
 	DevWorkspaceNameLabel = "controller.devfile.io/devworkspace_name"
 
+	// DevWorkspaceDedicatedPodLabel is the label key applied to the deployment, pods, and service created for a
+	// container component with dedicatedPod set. The value of the label is the name of the component.
+	DevWorkspaceDedicatedPodLabel = "controller.devfile.io/dedicated-pod"
+
+	// DevWorkspaceMainPodLabel is applied (with value "true") to the main workspace pod when a workspace has components
+	// running in dedicated pods, in order to distinguish it from dedicated pods when routing traffic to the workspace.
+	DevWorkspaceMainPodLabel = "controller.devfile.io/main-pod"
+
 	// DevWorkspaceWatchConfigMapLabel marks a configmap so that it is watched by the controller. This label is required on all
 	// configmaps that should be seen by the controller
 	DevWorkspaceWatchConfigMapLabel = "controller.devfile.io/watch-configmap"
@@ -58,6 +66,19 @@ const (
 	// If the git host is not defined then the certificate will be used for all http repositories.
 	DevWorkspaceGitTLSLabel = "controller.devfile.io/git-tls-credential"
 
+	// DevWorkspaceRegistryCredentialLabel is the label key to specify that a secret contains credentials used to fetch
+	// devfiles and plugins from devfile registries and URIs. Secrets with this label must also have the
+	// DevWorkspaceRegistryHostAnnotation annotation, and contain one of the following:
+	// token: a bearer token
+	// username and password: credentials for basic authentication
+	// tls.crt and tls.key: a client certificate and key for TLS client authentication
+	// Additionally, the CA certificate used to verify the server can be provided in ca.crt
+	DevWorkspaceRegistryCredentialLabel = "controller.devfile.io/registry-credential"
+
+	// DevWorkspaceRegistryHostAnnotation is the annotation key to store the comma-separated list of hosts
+	// (e.g. registry.example.com or registry.example.com:8443) that registry credentials should be used for.
+	DevWorkspaceRegistryHostAnnotation = "controller.devfile.io/registry-host"
+
 	// DevWorkspaceMountPathAnnotation is the annotation key to store the mount path for the secret or configmap.
 	// If no mount path is provided, configmaps will be mounted at /etc/config/<configmap-name>, secrets will
 	// be mounted at /etc/secret/<secret-name>, and persistent volume claims will be mounted to /tmp/<claim-name>
@@ -103,6 +124,10 @@ const (
 	// this annotation will be cleared
 	DevWorkspaceStopReasonAnnotation = "controller.devfile.io/stopped-by"
 
+	// DevWorkspaceStopReasonPreempted is the value of the DevWorkspaceStopReasonAnnotation used when a devworkspace is
+	// stopped by the controller because its pod was preempted by a pod with a higher priority
+	DevWorkspaceStopReasonPreempted = "Preempted"
+
 	// DevWorkspaceDebugStartAnnotation enables debugging workspace startup if set to "true". If a workspace with this annotation
 	// fails to start (i.e. enters the "Failed" phase), its deployment will not be scaled down in order to allow viewing logs, etc.
 	DevWorkspaceDebugStartAnnotation = "controller.devfile.io/debug-start"
@@ -133,6 +158,10 @@ const (
 	// the current namespace.
 	NamespacedConfigLabelKey = "controller.devfile.io/namespaced-config"
 
+	// DevfileVariablesLabel is a label applied to configmaps to mark them as defining devfile variables for all DevWorkspaces
+	// in the current namespace. Each key in the configmap's data defines a variable.
+	DevfileVariablesLabel = "controller.devfile.io/devfile-variables"
+
 	// NamespacePodTolerationsAnnotation is an annotation applied to a namespace to configure pod tolerations for all workspaces
 	// in that namespace. Value should be json-encoded []corev1.Toleration struct.
 	NamespacePodTolerationsAnnotation = "controller.devfile.io/pod-tolerations"
@@ -140,4 +169,48 @@ const (
 	// NamespaceNodeSelectorAnnotation is an annotation applied to a namespace to configure the node selector for all workspaces
 	// in that namespace. Value should be json-encoded map[string]string
 	NamespaceNodeSelectorAnnotation = "controller.devfile.io/node-selector"
+
+	// NamespacePodAffinityAnnotation is an annotation applied to a namespace to configure pod affinity and anti-affinity
+	// for all workspaces in that namespace. Value should be a json-encoded corev1.Affinity struct.
+	NamespacePodAffinityAnnotation = "controller.devfile.io/pod-affinity"
+
+	// NamespaceTopologySpreadConstraintsAnnotation is an annotation applied to a namespace to configure topology spread
+	// constraints for all workspaces in that namespace. Value should be a json-encoded []corev1.TopologySpreadConstraint
+	NamespaceTopologySpreadConstraintsAnnotation = "controller.devfile.io/topology-spread-constraints"
+
+	// DevWorkspacePrebuildLabel is the label key to store the name of the DevWorkspacePrebuild that owns a prebuild
+	// job or pod
+	DevWorkspacePrebuildLabel = "controller.devfile.io/devworkspace-prebuild"
+
+	// DevWorkspacePrebuildTriggerAnnotation is an annotation applied to DevWorkspacePrebuilds to trigger a new prebuild.
+	// Whenever the value of this annotation changes (e.g. to the current timestamp), a new prebuild is started.
+	DevWorkspacePrebuildTriggerAnnotation = "controller.devfile.io/prebuild-trigger"
+
+	// DevWorkspacePrebuildAnnotation is applied to DevWorkspaces by the controller to record the prebuild used to set up
+	// projects on the DevWorkspace's first start, in the format <DevWorkspacePrebuild name>/<prebuild run>. Once the
+	// DevWorkspace has started successfully, the value is set to DevWorkspacePrebuildRestored and prebuilds are no
+	// longer used for the DevWorkspace.
+	DevWorkspacePrebuildAnnotation = "controller.devfile.io/prebuild"
+
+	// DevWorkspacePrebuildRestored is the value of the DevWorkspacePrebuildAnnotation used once a DevWorkspace's
+	// projects have been set up from a prebuild
+	DevWorkspacePrebuildRestored = "restored"
+
+	// DevWorkspaceImageComponentLabel is the label key to store the name of the image component built by an image
+	// build job
+	DevWorkspaceImageComponentLabel = "controller.devfile.io/image-component"
+
+	// DevWorkspaceBuiltImageAnnotation is applied to completed image build jobs to store the reference (including digest)
+	// of the image that was built and pushed
+	DevWorkspaceBuiltImageAnnotation = "controller.devfile.io/built-image"
+
+	// DevWorkspaceResolvedImportsAnnotation is applied to DevWorkspaces by the controller to record the parent and
+	// plugins imported by the DevWorkspace, as a JSON list of the name, source and sha256 digest of the content each
+	// import resolved to.
+	DevWorkspaceResolvedImportsAnnotation = "controller.devfile.io/resolved-imports"
+
+	// DevWorkspaceRefreshImportsAnnotation can be applied to a DevWorkspace to discard any pinned content for its
+	// imported parent and plugins. Imports are resolved again and the controller removes the annotation once the
+	// newly resolved content is recorded.
+	DevWorkspaceRefreshImportsAnnotation = "controller.devfile.io/refresh-imports"
 )
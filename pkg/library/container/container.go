@@ -28,6 +28,8 @@ import (
 	"fmt"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
 	"github.com/devfile/devworkspace-operator/pkg/library/lifecycle"
@@ -35,49 +37,99 @@ import (
 
 // GetKubeContainersFromDevfile converts container components in a DevWorkspace into Kubernetes containers.
 // If a DevWorkspace container is an init container (i.e. is bound to a preStart event), it will be returned as an
-// init container.
+// init container. Container components with dedicatedPod set are not included; see GetDedicatedPodContainersFromDevfile.
 //
 // This function also provisions volume mounts on containers as follows:
 // - Container component's volume mounts are provisioned with the mount path and name specified in the devworkspace
 // However, no Volumes are added to the returned PodAdditions at this stage; the volumeMounts above are expected to be
 // rewritten as Volumes are added to PodAdditions, in order to support e.g. using one PVC to hold all volumes
 //
+// If a component defines the container-overrides attribute, it is applied to the resulting container as a strategic
+// merge patch.
+//
 // Note: Requires DevWorkspace to be flattened (i.e. the DevWorkspace contains no Parent or Components of type Plugin)
 func GetKubeContainersFromDevfile(workspace *dw.DevWorkspaceTemplateSpec) (*v1alpha1.PodAdditions, error) {
+	podAdditions, _, err := getKubeContainers(workspace)
+	return podAdditions, err
+}
+
+// GetDedicatedPodContainersFromDevfile converts container components in a DevWorkspace that have dedicatedPod set
+// into Kubernetes containers. Each container is returned in its own PodAdditions, as it is expected to run in its own
+// pod. Volume mounts are provisioned as in GetKubeContainersFromDevfile.
+//
+// Note: Requires DevWorkspace to be flattened (i.e. the DevWorkspace contains no Parent or Components of type Plugin)
+func GetDedicatedPodContainersFromDevfile(workspace *dw.DevWorkspaceTemplateSpec) ([]v1alpha1.PodAdditions, error) {
+	_, dedicatedPodAdditions, err := getKubeContainers(workspace)
+	return dedicatedPodAdditions, err
+}
+
+// IsDedicatedPod returns whether a container component should run in its own pod rather than the main workspace pod.
+func IsDedicatedPod(component *dw.Component) bool {
+	return component.Container != nil && component.Container.DedicatedPod != nil && *component.Container.DedicatedPod
+}
+
+func getKubeContainers(workspace *dw.DevWorkspaceTemplateSpec) (*v1alpha1.PodAdditions, []v1alpha1.PodAdditions, error) {
 	if !flatten.DevWorkspaceIsFlattened(workspace) {
-		return nil, fmt.Errorf("devfile is not flattened")
+		return nil, nil, fmt.Errorf("devfile is not flattened")
 	}
 	podAdditions := &v1alpha1.PodAdditions{}
 
 	initContainers, mainComponents, err := lifecycle.GetInitContainers(workspace.DevWorkspaceTemplateSpecContent)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	// Containers are collected together so that postStart events can reference containers in dedicated pods
+	var containers []corev1.Container
+	dedicatedPods := map[string]bool{}
 	for _, component := range mainComponents {
 		if component.Container == nil {
 			continue
 		}
 		k8sContainer, err := convertContainerToK8s(component)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		handleMountSources(k8sContainer, component.Container, workspace.Projects)
-		podAdditions.Containers = append(podAdditions.Containers, *k8sContainer)
+		k8sContainer, err = ApplyContainerOverrides(&component, k8sContainer)
+		if err != nil {
+			return nil, nil, err
+		}
+		containers = append(containers, *k8sContainer)
+		dedicatedPods[component.Name] = IsDedicatedPod(&component)
 	}
 
-	if err := lifecycle.AddPostStartLifecycleHooks(workspace, podAdditions.Containers); err != nil {
-		return nil, err
+	if err := lifecycle.AddPostStartLifecycleHooks(workspace, containers); err != nil {
+		return nil, nil, err
+	}
+
+	var dedicatedPodAdditions []v1alpha1.PodAdditions
+	for _, container := range containers {
+		if dedicatedPods[container.Name] {
+			dedicatedPodAdditions = append(dedicatedPodAdditions, v1alpha1.PodAdditions{
+				Containers: []corev1.Container{container},
+			})
+		} else {
+			podAdditions.Containers = append(podAdditions.Containers, container)
+		}
 	}
 
 	for _, container := range initContainers {
+		if container.Container == nil {
+			// Non-container components (e.g. image components) bound to preStart events are handled separately
+			continue
+		}
 		k8sContainer, err := convertContainerToK8s(container)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		handleMountSources(k8sContainer, container.Container, workspace.Projects)
+		k8sContainer, err = ApplyContainerOverrides(&container, k8sContainer)
+		if err != nil {
+			return nil, nil, err
+		}
 		podAdditions.InitContainers = append(podAdditions.InitContainers, *k8sContainer)
 	}
 
-	return podAdditions, nil
+	return podAdditions, dedicatedPodAdditions, nil
 }
@@ -24,6 +24,8 @@ import (
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
 
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
@@ -101,3 +103,93 @@ func TestGetKubeContainersFromDevfile(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyResourceBounds(t *testing.T) {
+	bounds := &v1alpha1.ResourceBounds{
+		Min: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		Max: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+			corev1.ResourceCPU:    resource.MustParse("2"),
+		},
+	}
+	podAdditions := &v1alpha1.PodAdditions{
+		Containers: []corev1.Container{
+			{
+				Name: "tools",
+				Resources: corev1.ResourceRequirements{
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+				},
+			},
+		},
+		InitContainers: []corev1.Container{
+			{
+				Name: "init",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+					},
+				},
+			},
+		},
+	}
+
+	warnings := ApplyResourceBounds(podAdditions, bounds)
+	assert.Len(t, warnings, 3)
+
+	tools := podAdditions.Containers[0].Resources
+	assert.True(t, resource.MustParse("4Gi").Equal(tools.Limits[corev1.ResourceMemory]), "Memory limit should be clamped to maximum")
+	assert.True(t, resource.MustParse("256Mi").Equal(tools.Requests[corev1.ResourceMemory]), "Memory request should be raised to minimum")
+	assert.True(t, resource.MustParse("2").Equal(tools.Limits[corev1.ResourceCPU]), "Unset cpu limit should use maximum")
+
+	init := podAdditions.InitContainers[0].Resources
+	assert.True(t, resource.MustParse("256Mi").Equal(init.Limits[corev1.ResourceMemory]), "Memory limit should be raised to minimum")
+	assert.True(t, resource.MustParse("500m").Equal(init.Limits[corev1.ResourceCPU]), "Cpu limit within bounds should be unchanged")
+}
+
+func TestGetDedicatedPodContainersFromDevfile(t *testing.T) {
+	setupControllerCfg()
+	dedicatedPod := true
+	workspace := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Components: []dw.Component{
+				{
+					Name: "tools",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{Container: dw.Container{Image: "tools-image"}},
+					},
+				},
+				{
+					Name: "database",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{
+							Container: dw.Container{Image: "database-image", DedicatedPod: &dedicatedPod},
+							Endpoints: []dw.Endpoint{{Name: "postgres", TargetPort: 5432}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dedicatedPodAdditions, err := GetDedicatedPodContainersFromDevfile(workspace)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, dedicatedPodAdditions, 1, "Should return one PodAdditions per dedicated pod") &&
+		assert.Len(t, dedicatedPodAdditions[0].Containers, 1) {
+		container := dedicatedPodAdditions[0].Containers[0]
+		assert.Equal(t, "database", container.Name)
+		assert.Empty(t, container.VolumeMounts, "Dedicated pods should not mount sources by default")
+		assert.Len(t, container.Ports, 1)
+	}
+
+	podAdditions, err := GetKubeContainersFromDevfile(workspace)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, podAdditions.Containers, 1, "Main pod should not include dedicated pod containers") {
+		assert.Equal(t, "tools", podAdditions.Containers[0].Name)
+	}
+}
@@ -78,36 +78,42 @@ func devfileResourcesToContainerResources(devfileContainer *dw.ContainerComponen
 
 	memLimit := devfileContainer.MemoryLimit
 	if memLimit == "" {
-		memLimit = constants.SidecarDefaultMemoryLimit
+		memLimit = getDefaultResource(v1.ResourceMemory, true)
 	}
-	memLimitQuantity, err := resource.ParseQuantity(memLimit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse memory limit %q: %w", memLimit, err)
+	if memLimit != "" {
+		memLimitQuantity, err := resource.ParseQuantity(memLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory limit %q: %w", memLimit, err)
+		}
+		limits[v1.ResourceMemory] = memLimitQuantity
 	}
-	limits[v1.ResourceMemory] = memLimitQuantity
 
 	memReq := devfileContainer.MemoryRequest
 	if memReq == "" {
-		memReq = constants.SidecarDefaultMemoryRequest
+		memReq = getDefaultResource(v1.ResourceMemory, false)
 	}
-	memReqQuantity, err := resource.ParseQuantity(memReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse memory request %q: %w", memReq, err)
+	if memReq != "" {
+		memReqQuantity, err := resource.ParseQuantity(memReq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory request %q: %w", memReq, err)
+		}
+		requests[v1.ResourceMemory] = memReqQuantity
 	}
-	requests[v1.ResourceMemory] = memReqQuantity
 
-	if memLimitQuantity.Cmp(memReqQuantity) < 0 {
-		if devfileContainer.MemoryRequest != "" {
-			return nil, fmt.Errorf("container resources are invalid: memory limit (%s) is less than request (%s)", memLimit, devfileContainer.MemoryRequest)
-		} else {
-			// No value was supplied; the issue is that the default value is greater than supplied limit. To resolve this, reuse limit as request
-			requests[v1.ResourceMemory] = memLimitQuantity
+	if parsedMemLimit, ok := limits[v1.ResourceMemory]; ok {
+		if parsedMemReq, ok := requests[v1.ResourceMemory]; ok && parsedMemLimit.Cmp(parsedMemReq) < 0 {
+			if devfileContainer.MemoryRequest != "" {
+				return nil, fmt.Errorf("container resources are invalid: memory limit (%s) is less than request (%s)", memLimit, devfileContainer.MemoryRequest)
+			} else {
+				// No value was supplied; the issue is that the default value is greater than supplied limit. To resolve this, reuse limit as request
+				requests[v1.ResourceMemory] = parsedMemLimit
+			}
 		}
 	}
 
 	cpuLimit := devfileContainer.CpuLimit
 	if cpuLimit == "" {
-		cpuLimit = constants.SidecarDefaultCpuLimit
+		cpuLimit = getDefaultResource(v1.ResourceCPU, true)
 	}
 	if cpuLimit != "" {
 		cpuLimitQuantity, err := resource.ParseQuantity(cpuLimit)
@@ -119,7 +125,7 @@ func devfileResourcesToContainerResources(devfileContainer *dw.ContainerComponen
 
 	cpuReq := devfileContainer.CpuRequest
 	if cpuReq == "" {
-		cpuReq = constants.SidecarDefaultCpuRequest
+		cpuReq = getDefaultResource(v1.ResourceCPU, false)
 	}
 	if cpuReq != "" {
 		cpuReqQuantity, err := resource.ParseQuantity(cpuReq)
@@ -130,9 +136,12 @@ func devfileResourcesToContainerResources(devfileContainer *dw.ContainerComponen
 	}
 
 	if parsedCPULimit, ok := limits[v1.ResourceCPU]; ok {
-		if parsedCPUReq, ok := requests[v1.ResourceCPU]; ok {
-			if parsedCPULimit.Cmp(parsedCPUReq) < 0 {
+		if parsedCPUReq, ok := requests[v1.ResourceCPU]; ok && parsedCPULimit.Cmp(parsedCPUReq) < 0 {
+			if devfileContainer.CpuRequest != "" {
 				return nil, fmt.Errorf("container resources are invalid: CPU limit (%s) is less than request (%s)", cpuLimit, cpuReq)
+			} else {
+				// As for memory, reuse the limit as request if the default request is greater than the supplied limit
+				requests[v1.ResourceCPU] = parsedCPULimit
 			}
 		}
 	}
@@ -143,6 +152,23 @@ func devfileResourcesToContainerResources(devfileContainer *dw.ContainerComponen
 	}, nil
 }
 
+// getDefaultResource returns the default limit or request for a resource, as defined in the operator configuration,
+// or an empty string if no default is defined.
+func getDefaultResource(name v1.ResourceName, limit bool) string {
+	defaults := config.Workspace.DefaultContainerResources
+	if defaults == nil {
+		return ""
+	}
+	resources := defaults.Requests
+	if limit {
+		resources = defaults.Limits
+	}
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return ""
+}
+
 func devfileVolumeMountsToContainerVolumeMounts(devfileVolumeMounts []dw.VolumeMount) []v1.VolumeMount {
 	var volumeMounts []v1.VolumeMount
 	for _, vm := range devfileVolumeMounts {
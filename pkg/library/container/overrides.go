@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	devfileConstants "github.com/devfile/devworkspace-operator/pkg/library/constants"
+)
+
+// protectedEnvVars are environment variables set by the DevWorkspace Operator that cannot be changed via the
+// container-overrides attribute.
+var protectedEnvVars = []string{
+	constants.DevWorkspaceComponentName,
+	devfileConstants.ProjectsRootEnvVar,
+	devfileConstants.ProjectsSourceEnvVar,
+}
+
+// NeedsContainerOverrides returns whether a component defines the container-overrides attribute
+func NeedsContainerOverrides(component *dw.Component) bool {
+	return component.Attributes.Exists(constants.ContainerOverridesAttribute)
+}
+
+// ApplyContainerOverrides applies the container-overrides attribute on a component, if present, to the container
+// generated for that component. The attribute is applied as a strategic merge patch. Returns an error if the attribute
+// cannot be applied or if it modifies fields that are managed by the DevWorkspace Operator.
+func ApplyContainerOverrides(component *dw.Component, container *corev1.Container) (*corev1.Container, error) {
+	if !NeedsContainerOverrides(component) {
+		return container, nil
+	}
+	if component.Container == nil {
+		return nil, fmt.Errorf("attribute %s is only supported on container components", constants.ContainerOverridesAttribute)
+	}
+	patch := component.Attributes[constants.ContainerOverridesAttribute].Raw
+
+	originalBytes, err := json.Marshal(container)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal container %s: %w", container.Name, err)
+	}
+	patchedBytes, err := strategicpatch.StrategicMergePatch(originalBytes, patch, &corev1.Container{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s attribute for component %s: %w", constants.ContainerOverridesAttribute, component.Name, err)
+	}
+	patched := &corev1.Container{}
+	if err := json.Unmarshal(patchedBytes, patched); err != nil {
+		return nil, fmt.Errorf("failed to apply %s attribute for component %s: %w", constants.ContainerOverridesAttribute, component.Name, err)
+	}
+
+	if err := checkProtectedContainerFields(container, patched); err != nil {
+		return nil, fmt.Errorf("invalid %s attribute for component %s: %w", constants.ContainerOverridesAttribute, component.Name, err)
+	}
+	return patched, nil
+}
+
+// ValidateContainerOverrides checks that the container-overrides attribute on a component, if present, can be applied
+// to a container and does not modify fields that are managed by the DevWorkspace Operator.
+func ValidateContainerOverrides(component *dw.Component) error {
+	_, err := ApplyContainerOverrides(component, &corev1.Container{Name: component.Name})
+	return err
+}
+
+func checkProtectedContainerFields(original, patched *corev1.Container) error {
+	if original.Name != patched.Name {
+		return fmt.Errorf("container name cannot be overridden")
+	}
+	if !reflect.DeepEqual(original.VolumeMounts, patched.VolumeMounts) {
+		return fmt.Errorf("container volumeMounts cannot be overridden")
+	}
+	for _, envName := range protectedEnvVars {
+		if !reflect.DeepEqual(getEnvVar(original, envName), getEnvVar(patched, envName)) {
+			return fmt.Errorf("environment variable %s cannot be overridden", envName)
+		}
+	}
+	if field := getPrivilegedSecurityContextField(patched.SecurityContext); field != "" && field != getPrivilegedSecurityContextField(original.SecurityContext) {
+		return fmt.Errorf("container securityContext cannot set %s", field)
+	}
+	return nil
+}
+
+// getPrivilegedSecurityContextField returns the first field in a container security context that grants the
+// container privileges beyond those of a regular workspace container, or an empty string if there is none.
+func getPrivilegedSecurityContextField(securityContext *corev1.SecurityContext) string {
+	if securityContext == nil {
+		return ""
+	}
+	switch {
+	case securityContext.Privileged != nil && *securityContext.Privileged:
+		return "privileged"
+	case securityContext.AllowPrivilegeEscalation != nil && *securityContext.AllowPrivilegeEscalation:
+		return "allowPrivilegeEscalation"
+	case securityContext.RunAsUser != nil && *securityContext.RunAsUser == 0:
+		return "runAsUser: 0"
+	case securityContext.RunAsGroup != nil && *securityContext.RunAsGroup == 0:
+		return "runAsGroup: 0"
+	case securityContext.Capabilities != nil && len(securityContext.Capabilities.Add) > 0:
+		return "capabilities.add"
+	case securityContext.ProcMount != nil && *securityContext.ProcMount == corev1.UnmaskedProcMount:
+		return "procMount: Unmasked"
+	case securityContext.RunAsNonRoot != nil && !*securityContext.RunAsNonRoot:
+		return "runAsNonRoot: false"
+	case securityContext.SeccompProfile != nil && securityContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined:
+		return "seccompProfile.type: Unconfined"
+	case securityContext.SELinuxOptions != nil:
+		return "seLinuxOptions"
+	}
+	return ""
+}
+
+func getEnvVar(container *corev1.Container, name string) *corev1.EnvVar {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return &env
+		}
+	}
+	return nil
+}
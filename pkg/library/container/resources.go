@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ApplyResourceBounds clamps the resource requests and limits of containers and init containers in podAdditions to
+// the minimum and maximum values defined in bounds. Containers that do not define a limit for a resource with a
+// maximum use the maximum as their limit. Returns a list of warnings describing requests and limits that were changed.
+func ApplyResourceBounds(podAdditions *v1alpha1.PodAdditions, bounds *v1alpha1.ResourceBounds) []string {
+	if bounds == nil {
+		return nil
+	}
+	var warnings []string
+	for idx := range podAdditions.Containers {
+		warnings = append(warnings, applyContainerResourceBounds(&podAdditions.Containers[idx], bounds)...)
+	}
+	for idx := range podAdditions.InitContainers {
+		warnings = append(warnings, applyContainerResourceBounds(&podAdditions.InitContainers[idx], bounds)...)
+	}
+	return warnings
+}
+
+func applyContainerResourceBounds(container *corev1.Container, bounds *v1alpha1.ResourceBounds) []string {
+	var warnings []string
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	clamp := func(resources corev1.ResourceList, name corev1.ResourceName, kind string) {
+		value, ok := resources[name]
+		if !ok {
+			return
+		}
+		if min, ok := bounds.Min[name]; ok && value.Cmp(min) < 0 {
+			warnings = append(warnings, fmt.Sprintf("container %s: %s %s %s is less than minimum %s, using minimum",
+				container.Name, name, kind, value.String(), min.String()))
+			resources[name] = min.DeepCopy()
+		}
+		if max, ok := bounds.Max[name]; ok && value.Cmp(max) > 0 {
+			warnings = append(warnings, fmt.Sprintf("container %s: %s %s %s is greater than maximum %s, using maximum",
+				container.Name, name, kind, value.String(), max.String()))
+			resources[name] = max.DeepCopy()
+		}
+	}
+
+	for _, name := range getBoundedResourceNames(bounds) {
+		clamp(container.Resources.Limits, name, "limit")
+		clamp(container.Resources.Requests, name, "request")
+		if max, ok := bounds.Max[name]; ok {
+			if _, hasLimit := container.Resources.Limits[name]; !hasLimit {
+				container.Resources.Limits[name] = max.DeepCopy()
+			}
+		}
+		// Clamping may result in a request greater than the limit, e.g. if only the request is below the minimum
+		limit, hasLimit := container.Resources.Limits[name]
+		request, hasRequest := container.Resources.Requests[name]
+		if hasLimit && hasRequest && request.Cmp(limit) > 0 {
+			container.Resources.Requests[name] = limit.DeepCopy()
+		}
+	}
+	return warnings
+}
+
+// getBoundedResourceNames returns the names of all resources with a minimum or maximum, in a stable order
+func getBoundedResourceNames(bounds *v1alpha1.ResourceBounds) []corev1.ResourceName {
+	names := map[corev1.ResourceName]bool{}
+	for name := range bounds.Min {
+		names[name] = true
+	}
+	for name := range bounds.Max {
+		names[name] = true
+	}
+	var sorted []corev1.ResourceName
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -25,10 +25,13 @@ import (
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/api/v2/pkg/utils/overriding"
 	"github.com/devfile/api/v2/pkg/validation/variables"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/library/annotate"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
@@ -43,11 +46,18 @@ const (
 	DWTSupportedNamespacesAnnotation = "controller.devfile.io/allow-import-from"
 )
 
+var log = logf.Log.WithName("flatten")
+
 type ResolverTools struct {
 	WorkspaceNamespace string
 	Context            context.Context
 	K8sClient          client.Client
 	HttpClient         network.HTTPGetter
+	// ResolvedImports, if not nil, is used to pin and record content resolved for imported parents and plugins
+	ResolvedImports *ResolvedImports
+	// DefaultVariables defines variables available for substitution in addition to those defined in the
+	// DevWorkspace. Variables defined in the DevWorkspace (or its parents and plugins) take precedence.
+	DefaultVariables map[string]string
 }
 
 // ResolveDevWorkspace takes a devworkspace and returns a "resolved" version of it -- i.e. one where all plugins and parents
@@ -59,6 +69,15 @@ func ResolveDevWorkspace(workspace *dw.DevWorkspaceTemplateSpec, tooling Resolve
 		return nil, nil, err
 	}
 
+	for name, value := range tooling.DefaultVariables {
+		if _, ok := resolvedDW.Variables[name]; !ok {
+			if resolvedDW.Variables == nil {
+				resolvedDW.Variables = map[string]string{}
+			}
+			resolvedDW.Variables[name] = value
+		}
+	}
+
 	warnings := variables.ValidateAndReplaceGlobalVariable(resolvedDW)
 	if len(warnings.Commands) > 0 || len(warnings.Components) > 0 || len(warnings.Projects) > 0 || len(warnings.StarterProjects) > 0 {
 		return resolvedDW, &warnings, nil
@@ -74,13 +93,21 @@ func recursiveResolve(workspace *dw.DevWorkspaceTemplateSpec, tooling ResolverTo
 
 	resolvedParent := &dw.DevWorkspaceTemplateSpecContent{}
 	if workspace.Parent != nil {
-		resolvedParentSpec, err := resolveParentComponent(workspace.Parent, tooling)
+		parentCtx := resolveCtx.addParent(workspace.Parent)
+		if err := parentCtx.hasCycle(); err != nil {
+			return nil, err
+		}
+		parentSpec, err := resolveParentComponent(workspace.Parent, tooling)
+		if err != nil {
+			return nil, err
+		}
+		resolvedParentSpec, err := recursiveResolve(parentSpec, tooling, parentCtx)
 		if err != nil {
 			return nil, err
 		}
-		if !DevWorkspaceIsFlattened(resolvedParentSpec) {
-			// TODO: implemenent this
-			return nil, fmt.Errorf("parents containing plugins or parents are not supported")
+		// Overrides are applied to the flattened parent, so that they can refer to elements inherited by the parent
+		if err := overrideParent(workspace.Parent, resolvedParentSpec); err != nil {
+			return nil, err
 		}
 		annotate.AddSourceAttributesForTemplate("parent", resolvedParentSpec)
 		resolvedParent = &resolvedParentSpec.DevWorkspaceTemplateSpecContent
@@ -94,19 +121,22 @@ func recursiveResolve(workspace *dw.DevWorkspaceTemplateSpec, tooling ResolverTo
 			// No action necessary
 			resolvedContent.Components = append(resolvedContent.Components, component)
 		} else {
-			pluginComponent, err := resolvePluginComponent(component.Name, component.Plugin, tooling)
-			if err != nil {
-				return nil, err
-			}
 			newCtx := resolveCtx.addPlugin(component.Name, component.Plugin)
 			if err := newCtx.hasCycle(); err != nil {
 				return nil, err
 			}
+			pluginComponent, err := resolvePluginComponent(component.Name, component.Plugin, tooling)
+			if err != nil {
+				return nil, err
+			}
 
 			resolvedPlugin, err := recursiveResolve(pluginComponent, tooling, newCtx)
 			if err != nil {
 				return nil, err
 			}
+			if err := overridePlugin(component.Plugin, resolvedPlugin); err != nil {
+				return nil, err
+			}
 
 			annotate.AddSourceAttributesForTemplate(component.Name, resolvedPlugin)
 			pluginSpecContents = append(pluginSpecContents, &resolvedPlugin.DevWorkspaceTemplateSpecContent)
@@ -142,15 +172,20 @@ func resolveParentComponent(parent *dw.Parent, tools ResolverTools) (resolvedPar
 	if err != nil {
 		return nil, err
 	}
-	if parent.Components != nil || parent.Commands != nil || parent.Projects != nil || parent.StarterProjects != nil {
-		overrideSpec, err := overriding.OverrideDevWorkspaceTemplateSpec(&resolvedParent.DevWorkspaceTemplateSpecContent, parent.ParentOverrides)
+	return resolvedParent, nil
+}
 
-		if err != nil {
-			return nil, err
-		}
-		resolvedParent.DevWorkspaceTemplateSpecContent = *overrideSpec
+// overrideParent applies the overrides defined in a parent reference to the resolved parent
+func overrideParent(parent *dw.Parent, resolvedParent *dw.DevWorkspaceTemplateSpec) error {
+	if parent.Components == nil && parent.Commands == nil && parent.Projects == nil && parent.StarterProjects == nil {
+		return nil
 	}
-	return resolvedParent, nil
+	overrideSpec, err := overriding.OverrideDevWorkspaceTemplateSpec(&resolvedParent.DevWorkspaceTemplateSpecContent, parent.ParentOverrides)
+	if err != nil {
+		return err
+	}
+	resolvedParent.DevWorkspaceTemplateSpecContent = *overrideSpec
+	return nil
 }
 
 // resolvePluginComponent resolves the DevWorkspaceTemplateSpec that a plugin component refers to. The name parameter is
@@ -172,19 +207,23 @@ func resolvePluginComponent(
 	if err != nil {
 		return nil, err
 	}
+	return resolvedPlugin, nil
+}
 
-	if plugin.Components != nil || plugin.Commands != nil {
-		overrideSpec, err := overriding.OverrideDevWorkspaceTemplateSpec(&resolvedPlugin.DevWorkspaceTemplateSpecContent, dw.PluginOverrides{
-			Components: plugin.Components,
-			Commands:   plugin.Commands,
-		})
-
-		if err != nil {
-			return nil, err
-		}
-		resolvedPlugin.DevWorkspaceTemplateSpecContent = *overrideSpec
+// overridePlugin applies the overrides defined in a plugin component to the resolved plugin
+func overridePlugin(plugin *dw.PluginComponent, resolvedPlugin *dw.DevWorkspaceTemplateSpec) error {
+	if plugin.Components == nil && plugin.Commands == nil {
+		return nil
 	}
-	return resolvedPlugin, nil
+	overrideSpec, err := overriding.OverrideDevWorkspaceTemplateSpec(&resolvedPlugin.DevWorkspaceTemplateSpecContent, dw.PluginOverrides{
+		Components: plugin.Components,
+		Commands:   plugin.Commands,
+	})
+	if err != nil {
+		return err
+	}
+	resolvedPlugin.DevWorkspaceTemplateSpecContent = *overrideSpec
+	return nil
 }
 
 // resolveElementByKubernetesImport resolves a plugin specified by a Kubernetes reference.
@@ -224,7 +263,7 @@ func resolveElementByKubernetesImport(
 		return nil, fmt.Errorf("plugin for component %s not found", name)
 	}
 
-	return &dwTemplate.Spec, nil
+	return recordKubernetesImport(name, namespacedName, &dwTemplate, tools)
 }
 
 // resolveElementById resolves a component specified by ID and registry URL. The name parameter is used to
@@ -252,7 +291,7 @@ func resolveElementById(
 	// convention: elements specified by id are served at <registryUrl>/devfiles/<id>
 	pluginURL.Path = path.Join(pluginURL.Path, "devfiles", id)
 
-	dwt, err := network.FetchDevWorkspaceTemplate(pluginURL.String(), tools.HttpClient)
+	dwt, err := fetchRemoteElement(name, pluginURL.String(), tools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve component %s from registry %s: %w", name, registryUrl, err)
 	}
@@ -270,13 +309,40 @@ func resolveElementByURI(
 		return nil, fmt.Errorf("cannot resolve resources by id: no HTTP client provided")
 	}
 
-	dwt, err := network.FetchDevWorkspaceTemplate(uri, tools.HttpClient)
+	dwt, err := fetchRemoteElement(name, uri, tools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve component %s by URI: %w", name, err)
 	}
 	return dwt, nil
 }
 
+// getRegistryCredentials reads credentials for fetching devfiles and plugins from secrets in the workspace's
+// namespace that are labelled with constants.DevWorkspaceRegistryCredentialLabel. Secrets that do not contain valid
+// credentials are logged and ignored, so that they do not affect workspaces that do not need them. If no Kubernetes
+// client or namespace is provided, no credentials are returned.
+func getRegistryCredentials(tools ResolverTools) ([]network.Credentials, error) {
+	if tools.K8sClient == nil || tools.WorkspaceNamespace == "" {
+		return nil, nil
+	}
+	secrets := &corev1.SecretList{}
+	err := tools.K8sClient.List(tools.Context, secrets, client.InNamespace(tools.WorkspaceNamespace), client.MatchingLabels{
+		constants.DevWorkspaceRegistryCredentialLabel: "true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry credentials: %w", err)
+	}
+	var credentials []network.Credentials
+	for idx := range secrets.Items {
+		creds, err := network.ReadCredentialsFromSecret(&secrets.Items[idx])
+		if err != nil {
+			log.Info(fmt.Sprintf("Ignoring invalid registry credentials: %s", err), "namespace", tools.WorkspaceNamespace)
+			continue
+		}
+		credentials = append(credentials, *creds)
+	}
+	return credentials, nil
+}
+
 // canImportDW returns true if a DevWorkspace in dwNamespace is allowed to reference the provided DevWorkspaceTemplate
 // DevWorkspaces can by default only read DevWorkspaceTemplates in their own namespace, unless the DevWorkspaceTemplate
 // has the controller.devfile.io/allow-import-from annotation.
@@ -19,10 +19,15 @@ import (
 	"context"
 	"testing"
 
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten/internal/testutil"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestResolveDevWorkspaceKubernetesReference(t *testing.T) {
@@ -211,6 +216,148 @@ func TestMergesDuplicateVolumeComponents(t *testing.T) {
 	}
 }
 
+func TestResolveDevWorkspaceRecordsAndPinsImports(t *testing.T) {
+	tt := testutil.LoadTestCaseOrPanic(t, "testdata/plugin-uri/resolve-plugin-by-uri.yaml")
+	testResolverTools := getTestingTools(tt.Input, "test-ignored")
+	testResolverTools.ResolvedImports = &ResolvedImports{}
+
+	_, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	resolved := testResolverTools.ResolvedImports.Resolved
+	if !assert.Len(t, resolved, 1, "Should record resolved plugin") {
+		return
+	}
+	assert.Equal(t, "test-plugin", resolved[0].Name)
+	assert.Equal(t, "https://my-plugin.io/test", resolved[0].Source)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", resolved[0].Digest)
+
+	// Pinned content should be used instead of fetching, even if the source content changed
+	pinned := resolved[0]
+	pinned.Content.Components[0].Container.Image = "pinned-image"
+	testResolverTools = getTestingTools(tt.Input, "test-ignored")
+	testResolverTools.ResolvedImports = &ResolvedImports{Pinned: []ResolvedImport{pinned}}
+	outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, "pinned-image", outputWorkspace.Components[0].Container.Image, "Should use pinned content")
+	assert.Equal(t, pinned.Digest, testResolverTools.ResolvedImports.Resolved[0].Digest, "Should record pinned import")
+}
+
+func TestResolveDevWorkspaceUsesDefaultVariables(t *testing.T) {
+	workspace := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Variables: map[string]string{
+				"maven_mirror": "https://devfile-mirror.example.com",
+			},
+			Components: []dw.Component{
+				{
+					Name: "test-container",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{
+							Container: dw.Container{
+								Image: "{{ registry_host }}/test-image",
+								Env: []dw.EnvVar{
+									{Name: "MAVEN_MIRROR", Value: "{{ maven_mirror }}"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	testResolverTools := ResolverTools{
+		DefaultVariables: map[string]string{
+			"registry_host": "registry.example.com",
+			"maven_mirror":  "https://cluster-mirror.example.com",
+		},
+	}
+
+	outputWorkspace, warnings, err := ResolveDevWorkspace(workspace, testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Nil(t, warnings, "Should not return variable warnings")
+	container := outputWorkspace.Components[0].Container
+	assert.Equal(t, "registry.example.com/test-image", container.Image, "Should use default variable")
+	assert.Equal(t, "https://devfile-mirror.example.com", container.Env[0].Value, "Variables in devfile should take precedence")
+}
+
+func TestResolveDevWorkspaceDetectsOutdatedPinnedTemplates(t *testing.T) {
+	tt := testutil.LoadTestCaseOrPanic(t, "testdata/parent/resolve-parent-by-k8s-reference.yaml")
+	testResolverTools := getTestingTools(tt.Input, "test-ns")
+	testResolverTools.ResolvedImports = &ResolvedImports{}
+	_, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	resolved := testResolverTools.ResolvedImports.Resolved
+	if !assert.Len(t, resolved, 1, "Should record resolved parent") {
+		return
+	}
+	assert.Equal(t, KubernetesImportSource("test-ns", "test-parent-k8s"), resolved[0].Source)
+	assert.Empty(t, testResolverTools.ResolvedImports.Outdated)
+
+	// Simulate the template being updated after it was pinned
+	pinned := resolved[0]
+	pinned.Digest = "sha256:outdated"
+	testResolverTools = getTestingTools(tt.Input, "test-ns")
+	testResolverTools.ResolvedImports = &ResolvedImports{Pinned: []ResolvedImport{pinned}}
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, []ResolvedImport{pinned}, testResolverTools.ResolvedImports.Outdated, "Should detect outdated template")
+}
+
+func TestGetRegistryCredentialsIgnoresInvalidSecrets(t *testing.T) {
+	credentialsLabels := map[string]string{constants.DevWorkspaceRegistryCredentialLabel: "true"}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "valid-credentials",
+				Namespace:   "test-ns",
+				Labels:      credentialsLabels,
+				Annotations: map[string]string{constants.DevWorkspaceRegistryHostAnnotation: "registry.example.com"},
+			},
+			Data: map[string][]byte{"token": []byte("test-token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "missing-hosts",
+				Namespace: "test-ns",
+				Labels:    credentialsLabels,
+			},
+			Data: map[string][]byte{"token": []byte("test-token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "missing-password",
+				Namespace:   "test-ns",
+				Labels:      credentialsLabels,
+				Annotations: map[string]string{constants.DevWorkspaceRegistryHostAnnotation: "other.example.com"},
+			},
+			Data: map[string][]byte{"username": []byte("test-user")},
+		},
+	).Build()
+
+	credentials, err := getRegistryCredentials(ResolverTools{
+		Context:            context.Background(),
+		K8sClient:          fakeClient,
+		WorkspaceNamespace: "test-ns",
+	})
+	if !assert.NoError(t, err, "Should not return error for invalid secrets") {
+		return
+	}
+	if assert.Len(t, credentials, 1, "Should only return valid credentials") {
+		assert.Equal(t, []string{"registry.example.com"}, credentials[0].Hosts)
+		assert.Equal(t, "test-token", credentials[0].BearerToken)
+	}
+}
+
 func getTestingTools(input testutil.TestInput, testNamespace string) ResolverTools {
 	testHttpGetter := &testutil.FakeHTTPGetter{
 		DevfileResources:      input.DevfileResources,
@@ -28,7 +28,9 @@ type resolutionContextTree struct {
 	componentName   string
 	importReference dw.ImportReference
 	plugins         []*resolutionContextTree
-	parentNode      *resolutionContextTree
+	// parent is the node for the devfile parent imported by this element, if any
+	parent     *resolutionContextTree
+	parentNode *resolutionContextTree
 }
 
 func (t *resolutionContextTree) addPlugin(name string, plugin *dw.PluginComponent) *resolutionContextTree {
@@ -41,6 +43,16 @@ func (t *resolutionContextTree) addPlugin(name string, plugin *dw.PluginComponen
 	return newNode
 }
 
+func (t *resolutionContextTree) addParent(parent *dw.Parent) *resolutionContextTree {
+	newNode := &resolutionContextTree{
+		componentName:   "parent",
+		importReference: parent.ImportReference,
+		parentNode:      t,
+	}
+	t.parent = newNode
+	return newNode
+}
+
 func (t *resolutionContextTree) hasCycle() error {
 	var seenRefs []dw.ImportReference
 	currNode := t
@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package flatten
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+// ResolvedImport records the content that an imported parent or plugin resolved to
+type ResolvedImport struct {
+	// Name is the name of the element that imported the content: the plugin component name or "parent"
+	Name string `json:"name"`
+	// Source is the URL the content was fetched from, or kubernetes:<namespace>/<name> for DevWorkspaceTemplates
+	// imported by Kubernetes reference
+	Source string `json:"source"`
+	// Digest is the sha256 digest of the resolved content
+	Digest string `json:"digest"`
+	// Content is the resolved content, before any overrides are applied. Content is not serialized, as only the
+	// digest is recorded for an import.
+	Content *dw.DevWorkspaceTemplateSpec `json:"-"`
+}
+
+// ResolvedImports tracks the content resolved for imported parents and plugins
+type ResolvedImports struct {
+	// Pinned is a list of previously resolved imports. If the source of an import matches a pinned import, the
+	// pinned content is used instead of fetching the source again.
+	Pinned []ResolvedImport
+	// Resolved is populated with all imports resolved during flattening.
+	Resolved []ResolvedImport
+	// Outdated is populated with pinned imports of DevWorkspaceTemplates whose content has changed since they
+	// were pinned.
+	Outdated []ResolvedImport
+}
+
+// KubernetesImportSource returns the source recorded for a DevWorkspaceTemplate imported by Kubernetes reference
+func KubernetesImportSource(namespace, name string) string {
+	return fmt.Sprintf("kubernetes:%s/%s", namespace, name)
+}
+
+// IsKubernetesImportSource returns whether source refers to a DevWorkspaceTemplate imported by Kubernetes reference
+func IsKubernetesImportSource(source string) bool {
+	return strings.HasPrefix(source, "kubernetes:")
+}
+
+// getPinned returns the pinned import for source and records it as resolved, or returns nil if source is not pinned.
+func (r *ResolvedImports) getPinned(name, source string) *ResolvedImport {
+	for _, pinned := range r.Pinned {
+		if pinned.Source == source && pinned.Content != nil {
+			r.Resolved = append(r.Resolved, ResolvedImport{
+				Name:    name,
+				Source:  pinned.Source,
+				Digest:  pinned.Digest,
+				Content: pinned.Content.DeepCopy(),
+			})
+			return &pinned
+		}
+	}
+	return nil
+}
+
+// recordKubernetesImport records the content of a DevWorkspaceTemplate imported by Kubernetes reference. If the
+// template is pinned in tools.ResolvedImports, the pinned content is returned instead.
+func recordKubernetesImport(name string, namespacedName types.NamespacedName, dwt *dw.DevWorkspaceTemplate, tools ResolverTools) (*dw.DevWorkspaceTemplateSpec, error) {
+	if tools.ResolvedImports == nil {
+		return &dwt.Spec, nil
+	}
+	source := KubernetesImportSource(namespacedName.Namespace, namespacedName.Name)
+	digest, err := getDigest(&dwt.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if pinned := tools.ResolvedImports.getPinned(name, source); pinned != nil {
+		if pinned.Digest != digest {
+			tools.ResolvedImports.Outdated = append(tools.ResolvedImports.Outdated, *pinned)
+		}
+		return pinned.Content.DeepCopy(), nil
+	}
+	tools.ResolvedImports.Resolved = append(tools.ResolvedImports.Resolved, ResolvedImport{
+		Name:    name,
+		Source:  source,
+		Digest:  digest,
+		Content: dwt.Spec.DeepCopy(),
+	})
+	return &dwt.Spec, nil
+}
+
+// fetchRemoteElement fetches the DevWorkspaceTemplateSpec at location, using pinned content from
+// tools.ResolvedImports if available. If tools.ResolvedImports is not nil, the resolved content is recorded.
+func fetchRemoteElement(name, location string, tools ResolverTools) (*dw.DevWorkspaceTemplateSpec, error) {
+	if tools.ResolvedImports != nil {
+		if pinned := tools.ResolvedImports.getPinned(name, location); pinned != nil {
+			return pinned.Content.DeepCopy(), nil
+		}
+	}
+
+	credentials, err := getRegistryCredentials(tools)
+	if err != nil {
+		return nil, err
+	}
+	dwt, err := network.FetchDevWorkspaceTemplate(location, tools.HttpClient, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if tools.ResolvedImports != nil {
+		digest, err := getDigest(dwt)
+		if err != nil {
+			return nil, err
+		}
+		tools.ResolvedImports.Resolved = append(tools.ResolvedImports.Resolved, ResolvedImport{
+			Name:    name,
+			Source:  location,
+			Digest:  digest,
+			Content: dwt.DeepCopy(),
+		})
+	}
+	return dwt, nil
+}
+
+func getDigest(dwt *dw.DevWorkspaceTemplateSpec) (string, error) {
+	contentBytes, err := yaml.Marshal(dwt)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute digest for resolved content: %w", err)
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(contentBytes)), nil
+}
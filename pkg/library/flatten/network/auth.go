@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	credentialsTokenKey    = "token"
+	credentialsUsernameKey = "username"
+	credentialsPasswordKey = "password"
+	credentialsCAKey       = "ca.crt"
+)
+
+// AuthenticatedHTTPGetter is an HTTPGetter that can authenticate requests using credentials
+type AuthenticatedHTTPGetter interface {
+	HTTPGetter
+	// GetWithCredentials fetches location, authenticating the request using the first credentials in the list
+	// that apply to the location's host. If no credentials apply, the request is not authenticated.
+	GetWithCredentials(location string, credentials []Credentials) (*http.Response, error)
+}
+
+// Credentials defines how requests to a set of hosts should be authenticated
+type Credentials struct {
+	// ID uniquely identifies these credentials (including their contents), e.g. <namespace>/<name>@<resourceVersion>
+	// for credentials read from a secret
+	ID string
+	// Hosts is the list of hosts (optionally including port) that these credentials apply to
+	Hosts []string
+	// BearerToken, if set, is sent in the Authorization header
+	BearerToken string
+	// Username and Password, if set, are used for basic authentication
+	Username string
+	Password string
+	// ClientCertificate, if set, is used for TLS client authentication
+	ClientCertificate *tls.Certificate
+	// RootCAs, if set, is used to verify the server's certificate
+	RootCAs *x509.CertPool
+}
+
+// ReadCredentialsFromSecret reads credentials from a secret labelled with constants.DevWorkspaceRegistryCredentialLabel.
+// The hosts that the credentials apply to are read from the constants.DevWorkspaceRegistryHostAnnotation annotation,
+// and the secret must define either a bearer token (key 'token'), a username and password for basic authentication
+// (keys 'username' and 'password'), or a client certificate and key (keys 'tls.crt' and 'tls.key'). A CA certificate
+// used to verify the server can optionally be provided in the key 'ca.crt'.
+func ReadCredentialsFromSecret(secret *corev1.Secret) (*Credentials, error) {
+	var hosts []string
+	for _, host := range strings.Split(secret.Annotations[constants.DevWorkspaceRegistryHostAnnotation], ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("secret %s does not define the hosts it applies to using the %s annotation", secret.Name, constants.DevWorkspaceRegistryHostAnnotation)
+	}
+
+	credentials := &Credentials{
+		ID:          fmt.Sprintf("%s/%s@%s", secret.Namespace, secret.Name, secret.ResourceVersion),
+		Hosts:       hosts,
+		BearerToken: string(secret.Data[credentialsTokenKey]),
+		Username:    string(secret.Data[credentialsUsernameKey]),
+		Password:    string(secret.Data[credentialsPasswordKey]),
+	}
+	if (credentials.Username == "") != (credentials.Password == "") {
+		return nil, fmt.Errorf("secret %s must define both '%s' and '%s' for basic authentication", secret.Name, credentialsUsernameKey, credentialsPasswordKey)
+	}
+	if credentials.BearerToken != "" && credentials.Username != "" {
+		return nil, fmt.Errorf("secret %s cannot define both a token and basic authentication", secret.Name)
+	}
+
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if (len(certPEM) == 0) != (len(keyPEM) == 0) {
+		return nil, fmt.Errorf("secret %s must define both '%s' and '%s' for TLS client authentication", secret.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+	if len(certPEM) > 0 {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client certificate from secret %s: %w", secret.Name, err)
+		}
+		credentials.ClientCertificate = &certificate
+	}
+	if caPEM := secret.Data[credentialsCAKey]; len(caPEM) > 0 {
+		credentials.RootCAs = x509.NewCertPool()
+		if !credentials.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to read CA certificate from secret %s", secret.Name)
+		}
+	}
+
+	if credentials.BearerToken == "" && credentials.Username == "" && credentials.ClientCertificate == nil {
+		return nil, fmt.Errorf("secret %s does not define a token, username and password, or client certificate", secret.Name)
+	}
+	return credentials, nil
+}
+
+// GetCredentialsForLocation returns the first credentials in the list that apply to location, or nil if
+// no credentials apply.
+func GetCredentialsForLocation(location string, credentials []Credentials) *Credentials {
+	locationURL, err := url.Parse(location)
+	if err != nil {
+		return nil
+	}
+	for idx, creds := range credentials {
+		for _, host := range creds.Hosts {
+			if host == locationURL.Host || host == locationURL.Hostname() {
+				return &credentials[idx]
+			}
+		}
+	}
+	return nil
+}
+
+// usesTLS returns whether the credentials require a custom TLS configuration
+func (c *Credentials) usesTLS() bool {
+	return c.ClientCertificate != nil || c.RootCAs != nil
+}
+
+// authorize adds authentication headers to req, if required
+func (c *Credentials) authorize(req *http.Request) {
+	switch {
+	case c.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+}
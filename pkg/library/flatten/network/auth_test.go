@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func testCredentialsSecret(host string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-secret",
+			Namespace: "test-ns",
+			Labels: map[string]string{
+				constants.DevWorkspaceRegistryCredentialLabel: "true",
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceRegistryHostAnnotation: host,
+			},
+		},
+		Data: map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestReadCredentialsFromSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    *corev1.Secret
+		errRegexp string
+	}{
+		{
+			name:   "Reads bearer token",
+			secret: testCredentialsSecret("registry.example.com", map[string]string{"token": "test-token"}),
+		},
+		{
+			name:   "Reads basic auth",
+			secret: testCredentialsSecret("registry.example.com, other.example.com:8443", map[string]string{"username": "user", "password": "pass"}),
+		},
+		{
+			name:      "Fails when host is not defined",
+			secret:    testCredentialsSecret("", map[string]string{"token": "test-token"}),
+			errRegexp: "does not define the hosts",
+		},
+		{
+			name:      "Fails when password is missing",
+			secret:    testCredentialsSecret("registry.example.com", map[string]string{"username": "user"}),
+			errRegexp: "must define both 'username' and 'password'",
+		},
+		{
+			name:      "Fails when client key is missing",
+			secret:    testCredentialsSecret("registry.example.com", map[string]string{"tls.crt": "test-cert"}),
+			errRegexp: "must define both 'tls.crt' and 'tls.key'",
+		},
+		{
+			name:      "Fails when no credentials are defined",
+			secret:    testCredentialsSecret("registry.example.com", nil),
+			errRegexp: "does not define a token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCredentialsFromSecret(tt.secret)
+			if tt.errRegexp != "" {
+				if assert.Error(t, err) {
+					assert.Regexp(t, tt.errRegexp, err.Error())
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCachingHTTPGetterAuthenticatesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("private-content"))
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	credentials := []Credentials{{ID: "test-ns/test-secret@1", Hosts: []string{serverURL.Host}, BearerToken: "test-token"}}
+
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: time.Hour} }, nil)
+	resp, err := getter.GetWithCredentials(server.URL+"/devfile.yaml", credentials)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "private-content", readBody(t, resp))
+
+	// Content fetched with credentials should not be served to requests without them
+	resp, err = getter.Get(server.URL + "/devfile.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
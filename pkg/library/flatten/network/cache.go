@@ -0,0 +1,354 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FetchResultSuccess     = "success"
+	FetchResultNotModified = "not_modified"
+	FetchResultError       = "error"
+
+	// maxCacheEntries is the maximum number of responses stored by a CachingHTTPGetter. Once reached, the least
+	// recently used response is evicted.
+	maxCacheEntries = 1000
+	// maxTLSClients is the maximum number of clients for credentials that require TLS configuration stored by a
+	// CachingHTTPGetter. Once reached, the oldest client is evicted.
+	maxTLSClients = 100
+)
+
+// CacheConfig configures a CachingHTTPGetter.
+type CacheConfig struct {
+	// TTL is the duration for which a cached response is used without contacting the server. Once the TTL
+	// expires, the cached response is revalidated using its ETag, if available.
+	TTL time.Duration
+	// Mirrors maps URL prefixes (e.g. devfile registry URLs) to a list of alternative prefixes. If a request
+	// to a location with a mirrored prefix fails, the request is retried against each mirror in order.
+	Mirrors map[string][]string
+}
+
+// CacheObserver is notified about requests handled by a CachingHTTPGetter, e.g. to record metrics.
+type CacheObserver interface {
+	// CacheHit is called when a request is served from the cache without contacting a server
+	CacheHit()
+	// CacheMiss is called when a request requires contacting a server
+	CacheMiss()
+	// Fetched is called for each request made to a server, with the time taken and the result of the request
+	// (one of FetchResultSuccess, FetchResultNotModified, or FetchResultError)
+	Fetched(duration time.Duration, result string)
+}
+
+type cacheEntry struct {
+	// source is the location the cached content was fetched from, which may be a mirror
+	source    string
+	etag      string
+	content   []byte
+	fetchedAt time.Time
+}
+
+// CachingHTTPGetter is an HTTPGetter that caches successful responses in memory. Cached responses are revalidated
+// with the server using ETags once they are older than the configured TTL. If the server cannot be reached, mirrors
+// are tried, and if all requests fail, the last successfully fetched content is returned.
+//
+// Content fetched using credentials is cached separately for each set of credentials, to avoid serving content
+// fetched using one set of credentials to requests that do not use them.
+type CachingHTTPGetter struct {
+	client    *http.Client
+	getConfig func() CacheConfig
+	observer  CacheObserver
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// lastUsed stores the time each entry in entries was last used, for evicting the least recently used entry
+	lastUsed map[string]time.Time
+	// tlsClients stores clients configured for credentials that require TLS configuration, keyed by credentials ID.
+	// As credentials IDs change whenever credentials are updated, tlsClientIDs records the order in which clients
+	// were added so that outdated clients are evicted.
+	tlsClients   map[string]*http.Client
+	tlsClientIDs []string
+}
+
+var _ AuthenticatedHTTPGetter = (*CachingHTTPGetter)(nil)
+
+// NewCachingHTTPGetter returns a CachingHTTPGetter that uses client for requests. The getConfig function is called
+// for every request, allowing configuration to be updated at runtime. The observer may be nil.
+func NewCachingHTTPGetter(client *http.Client, getConfig func() CacheConfig, observer CacheObserver) *CachingHTTPGetter {
+	return &CachingHTTPGetter{
+		client:     client,
+		getConfig:  getConfig,
+		observer:   observer,
+		entries:    map[string]*cacheEntry{},
+		lastUsed:   map[string]time.Time{},
+		tlsClients: map[string]*http.Client{},
+	}
+}
+
+// Get returns the content at location, from the cache if possible. Only responses with status 200 are cached.
+func (c *CachingHTTPGetter) Get(location string) (*http.Response, error) {
+	return c.GetWithCredentials(location, nil)
+}
+
+// GetWithCredentials is the same as Get, except each request made is authenticated using the first credentials
+// that apply to the requested host, if any.
+func (c *CachingHTTPGetter) GetWithCredentials(location string, credentials []Credentials) (*http.Response, error) {
+	config := c.getConfig()
+	candidates := getCandidateLocations(location, config.Mirrors)
+	cacheKey := getCacheKey(location, candidates, credentials)
+	entry := c.getEntry(cacheKey)
+	if entry != nil && time.Since(entry.fetchedAt) < config.TTL {
+		c.cacheHit()
+		return cachedResponse(entry), nil
+	}
+	c.cacheMiss()
+
+	var lastErr error
+	for _, candidate := range candidates {
+		resp, err := c.fetch(candidate, entry, GetCredentialsForLocation(candidate, credentials))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotModified:
+			resp.Body.Close()
+			updated := *entry
+			updated.fetchedAt = time.Now()
+			c.setEntry(cacheKey, &updated)
+			return cachedResponse(&updated), nil
+		case resp.StatusCode == http.StatusOK:
+			content, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				lastErr = fmt.Errorf("could not read data from %s: %w", candidate, err)
+				continue
+			}
+			newEntry := &cacheEntry{
+				source:    candidate,
+				etag:      resp.Header.Get("ETag"),
+				content:   content,
+				fetchedAt: time.Now(),
+			}
+			c.setEntry(cacheKey, newEntry)
+			return cachedResponse(newEntry), nil
+		case resp.StatusCode >= http.StatusInternalServerError:
+			resp.Body.Close()
+			lastErr = fmt.Errorf("request to %s returned status %d", candidate, resp.StatusCode)
+			continue
+		default:
+			// Client errors (e.g. 404) are not retried against mirrors, as mirrors are expected to serve
+			// the same content.
+			return resp, nil
+		}
+	}
+
+	if entry != nil {
+		// Servers are unavailable; use stale content rather than failing
+		return cachedResponse(entry), nil
+	}
+	return nil, lastErr
+}
+
+func (c *CachingHTTPGetter) fetch(location string, entry *cacheEntry, credentials *Credentials) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && entry.etag != "" && entry.source == location {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	client := c.client
+	if credentials != nil {
+		credentials.authorize(req)
+		if credentials.usesTLS() {
+			client = c.getTLSClient(credentials)
+		}
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	switch {
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		c.fetched(time.Since(start), FetchResultError)
+	case resp.StatusCode == http.StatusNotModified:
+		if entry == nil || entry.source != location {
+			// Should not happen, as If-None-Match is only set when there is a cached entry for this location
+			resp.Body.Close()
+			c.fetched(time.Since(start), FetchResultError)
+			return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, location)
+		}
+		c.fetched(time.Since(start), FetchResultNotModified)
+	default:
+		c.fetched(time.Since(start), FetchResultSuccess)
+	}
+	return resp, err
+}
+
+func (c *CachingHTTPGetter) getEntry(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if ok {
+		c.lastUsed[key] = time.Now()
+	}
+	return entry
+}
+
+func (c *CachingHTTPGetter) setEntry(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		c.evictLeastRecentlyUsed()
+	}
+	c.entries[key] = entry
+	c.lastUsed[key] = time.Now()
+}
+
+// evictLeastRecentlyUsed removes the least recently used entry from the cache. Must be called with c.mu held.
+func (c *CachingHTTPGetter) evictLeastRecentlyUsed() {
+	var oldestKey string
+	var oldest time.Time
+	for key, lastUsed := range c.lastUsed {
+		if oldestKey == "" || lastUsed.Before(oldest) {
+			oldestKey, oldest = key, lastUsed
+		}
+	}
+	delete(c.entries, oldestKey)
+	delete(c.lastUsed, oldestKey)
+}
+
+// getTLSClient returns a client that uses the TLS configuration (client certificate and CAs) defined in credentials
+func (c *CachingHTTPGetter) getTLSClient(credentials *Credentials) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.tlsClients[credentials.ID]; ok {
+		return client
+	}
+	var transport *http.Transport
+	if baseTransport, ok := c.client.Transport.(*http.Transport); ok {
+		transport = baseTransport.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	if credentials.ClientCertificate != nil {
+		transport.TLSClientConfig.Certificates = []tls.Certificate{*credentials.ClientCertificate}
+	}
+	if credentials.RootCAs != nil {
+		transport.TLSClientConfig.RootCAs = credentials.RootCAs
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   c.client.Timeout,
+	}
+	if len(c.tlsClientIDs) >= maxTLSClients {
+		oldestID := c.tlsClientIDs[0]
+		c.tlsClientIDs = c.tlsClientIDs[1:]
+		c.tlsClients[oldestID].CloseIdleConnections()
+		delete(c.tlsClients, oldestID)
+	}
+	c.tlsClients[credentials.ID] = client
+	c.tlsClientIDs = append(c.tlsClientIDs, credentials.ID)
+	return client
+}
+
+func (c *CachingHTTPGetter) cacheHit() {
+	if c.observer != nil {
+		c.observer.CacheHit()
+	}
+}
+
+func (c *CachingHTTPGetter) cacheMiss() {
+	if c.observer != nil {
+		c.observer.CacheMiss()
+	}
+}
+
+func (c *CachingHTTPGetter) fetched(duration time.Duration, result string) {
+	if c.observer != nil {
+		c.observer.Fetched(duration, result)
+	}
+}
+
+// getCandidateLocations returns the list of locations that can be used to fetch location: location itself, followed
+// by the equivalent location on each mirror configured for it. If multiple mirrored prefixes match location, mirrors
+// for the longest (most specific) prefix are used first.
+func getCandidateLocations(location string, mirrors map[string][]string) []string {
+	var matchingPrefixes []string
+	for prefix := range mirrors {
+		trimmedPrefix := strings.TrimSuffix(prefix, "/")
+		if trimmedPrefix == "" || !strings.HasPrefix(location, trimmedPrefix+"/") {
+			continue
+		}
+		matchingPrefixes = append(matchingPrefixes, prefix)
+	}
+	sort.Slice(matchingPrefixes, func(i, j int) bool {
+		iLen, jLen := len(strings.TrimSuffix(matchingPrefixes[i], "/")), len(strings.TrimSuffix(matchingPrefixes[j], "/"))
+		if iLen != jLen {
+			return iLen > jLen
+		}
+		return matchingPrefixes[i] < matchingPrefixes[j]
+	})
+
+	candidates := []string{location}
+	for _, prefix := range matchingPrefixes {
+		suffix := strings.TrimPrefix(location, strings.TrimSuffix(prefix, "/"))
+		for _, mirrorPrefix := range mirrors[prefix] {
+			candidates = append(candidates, strings.TrimSuffix(mirrorPrefix, "/")+suffix)
+		}
+	}
+	return candidates
+}
+
+// getCacheKey returns the key used to cache content for location. If any credentials apply to the location or its
+// mirrors, their IDs are included in the key.
+func getCacheKey(location string, candidates []string, credentials []Credentials) string {
+	credentialIDs := map[string]bool{}
+	for _, candidate := range candidates {
+		if creds := GetCredentialsForLocation(candidate, credentials); creds != nil {
+			credentialIDs[creds.ID] = true
+		}
+	}
+	if len(credentialIDs) == 0 {
+		return location
+	}
+	var ids []string
+	for id := range credentialIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return fmt.Sprintf("%s#%s", location, strings.Join(ids, ","))
+}
+
+func cachedResponse(entry *cacheEntry) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(entry.content)),
+		ContentLength: int64(len(entry.content)),
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testObserver struct {
+	hits, misses int
+	results      []string
+}
+
+func (o *testObserver) CacheHit()  { o.hits++ }
+func (o *testObserver) CacheMiss() { o.misses++ }
+func (o *testObserver) Fetched(_ time.Duration, result string) {
+	o.results = append(o.results, result)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return string(body)
+}
+
+func TestCachingHTTPGetterUsesCacheWithinTTL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("test-content"))
+	}))
+	defer server.Close()
+
+	observer := &testObserver{}
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: time.Hour} }, observer)
+	for i := 0; i < 2; i++ {
+		resp, err := getter.Get(server.URL + "/devfile.yaml")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "test-content", readBody(t, resp))
+	}
+	assert.Equal(t, 1, requests, "Should only contact server once within TTL")
+	assert.Equal(t, 1, observer.hits)
+	assert.Equal(t, 1, observer.misses)
+	assert.Equal(t, []string{FetchResultSuccess}, observer.results)
+}
+
+func TestCachingHTTPGetterRevalidatesWithETag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("test-content"))
+	}))
+	defer server.Close()
+
+	observer := &testObserver{}
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: 0} }, observer)
+	for i := 0; i < 2; i++ {
+		resp, err := getter.Get(server.URL + "/devfile.yaml")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "test-content", readBody(t, resp))
+	}
+	assert.Equal(t, []string{FetchResultSuccess, FetchResultNotModified}, observer.results)
+}
+
+func TestCachingHTTPGetterFallsBackToMirror(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer registry.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/devfiles/test/devfile.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("mirror-content"))
+	}))
+	defer mirror.Close()
+
+	getter := NewCachingHTTPGetter(http.DefaultClient, func() CacheConfig {
+		return CacheConfig{Mirrors: map[string][]string{registry.URL: {mirror.URL + "/"}}}
+	}, nil)
+	resp, err := getter.Get(registry.URL + "/devfiles/test/devfile.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "mirror-content", readBody(t, resp))
+}
+
+func TestCachingHTTPGetterReturnsStaleContentWhenUnavailable(t *testing.T) {
+	available := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("test-content"))
+	}))
+	defer server.Close()
+
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: 0} }, nil)
+	resp, err := getter.Get(server.URL + "/devfile.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	readBody(t, resp)
+
+	available = false
+	resp, err = getter.Get(server.URL + "/devfile.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "test-content", readBody(t, resp))
+}
+
+func TestCachingHTTPGetterDoesNotCacheErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: time.Hour} }, nil)
+	for i := 0; i < 2; i++ {
+		resp, err := getter.Get(server.URL + "/devfile.yaml")
+		if !assert.NoError(t, err) {
+			return
+		}
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingHTTPGetterEvictsLeastRecentlyUsedEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	getter := NewCachingHTTPGetter(server.Client(), func() CacheConfig { return CacheConfig{TTL: time.Hour} }, nil)
+	for i := 0; i <= maxCacheEntries; i++ {
+		if i == maxCacheEntries {
+			// Use the first entry again so that it is not the least recently used
+			resp, err := getter.Get(server.URL + "/0")
+			if !assert.NoError(t, err) {
+				return
+			}
+			readBody(t, resp)
+		}
+		resp, err := getter.Get(fmt.Sprintf("%s/%d", server.URL, i))
+		if !assert.NoError(t, err) {
+			return
+		}
+		readBody(t, resp)
+	}
+	assert.Len(t, getter.entries, maxCacheEntries, "Cache should not grow beyond its maximum size")
+	assert.Contains(t, getter.entries, server.URL+"/0", "Recently used entry should not be evicted")
+	assert.NotContains(t, getter.entries, server.URL+"/1", "Least recently used entry should be evicted")
+}
+
+func TestGetCandidateLocationsPrefersLongestPrefix(t *testing.T) {
+	mirrors := map[string][]string{
+		"https://registry.example.com":              {"https://mirror.example.com"},
+		"https://registry.example.com/devfiles/":    {"https://devfile-mirror.example.com/"},
+		"https://registry.example.com/devfiles/abc": {"https://abc-mirror.example.com"},
+		"https://other.example.com":                 {"https://other-mirror.example.com"},
+	}
+	// Repeat to detect nondeterministic ordering from map iteration
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []string{
+			"https://registry.example.com/devfiles/test/devfile.yaml",
+			"https://devfile-mirror.example.com/test/devfile.yaml",
+			"https://mirror.example.com/devfiles/test/devfile.yaml",
+		}, getCandidateLocations("https://registry.example.com/devfiles/test/devfile.yaml", mirrors))
+	}
+}
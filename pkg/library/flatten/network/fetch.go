@@ -28,8 +28,10 @@ type HTTPGetter interface {
 	Get(location string) (*http.Response, error)
 }
 
-func FetchDevWorkspaceTemplate(location string, httpClient HTTPGetter) (*dw.DevWorkspaceTemplateSpec, error) {
-	resp, err := httpClient.Get(location)
+// FetchDevWorkspaceTemplate fetches a devfile, DevWorkspace, or DevWorkspaceTemplate from location and returns its
+// spec. If any of the provided credentials apply to location, they are used to authenticate the request.
+func FetchDevWorkspaceTemplate(location string, httpClient HTTPGetter, credentials []Credentials) (*dw.DevWorkspaceTemplateSpec, error) {
+	resp, err := get(location, httpClient, credentials)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch file from %s: %w", location, err)
 	}
@@ -74,3 +76,13 @@ func FetchDevWorkspaceTemplate(location string, httpClient HTTPGetter) (*dw.DevW
 
 	return nil, fmt.Errorf("could not find devfile or devworkspace object at '%s'", location)
 }
+
+func get(location string, httpClient HTTPGetter, credentials []Credentials) (*http.Response, error) {
+	if authenticatedClient, ok := httpClient.(AuthenticatedHTTPGetter); ok {
+		return authenticatedClient.GetWithCredentials(location, credentials)
+	}
+	if GetCredentialsForLocation(location, credentials) != nil {
+		return nil, fmt.Errorf("HTTP client does not support authentication")
+	}
+	return httpClient.Get(location)
+}
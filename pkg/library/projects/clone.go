@@ -33,7 +33,7 @@ const (
 )
 
 func GetProjectCloneInitContainer(workspace *dw.DevWorkspaceTemplateSpec) (*corev1.Container, error) {
-	if len(workspace.Projects) == 0 {
+	if len(workspace.Projects) == 0 && !workspace.Attributes.Exists(constants.StarterProjectAttribute) {
 		return nil, nil
 	}
 	if workspace.Attributes.GetString(constants.ProjectCloneAttribute, nil) == constants.ProjectCloneDisable {
@@ -104,3 +104,20 @@ func hasContainerComponents(workspace *dw.DevWorkspaceTemplateSpec) bool {
 	}
 	return false
 }
+
+// GetProjectCloneWarning returns the message reported by the project-clone init container in pod when it terminated
+// successfully, if any. The project-clone container uses its termination message to report changes to projects that
+// could not be applied to the projects already present in the workspace.
+func GetProjectCloneWarning(pod *corev1.Pod) string {
+	for _, initContainerStatus := range pod.Status.InitContainerStatuses {
+		if initContainerStatus.Name != projectClonerContainerName {
+			continue
+		}
+		terminated := initContainerStatus.State.Terminated
+		if terminated == nil || terminated.ExitCode != 0 {
+			return ""
+		}
+		return terminated.Message
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CloneProgressLogPrefix is the prefix used by the project-clone container for log lines that report progress.
+// Progress lines have the format '<prefix> <JSON-serialized CloneProgress>'.
+const CloneProgressLogPrefix = "[project-clone-progress]"
+
+// CloneProgressLogTailLines is the number of lines of the project-clone container's log that are read to find
+// the most recent progress report.
+const CloneProgressLogTailLines = 20
+
+// CloneProgress describes the current progress of setting up a project in the project-clone container
+type CloneProgress struct {
+	// Project is the name of the project currently being set up
+	Project string `json:"project"`
+	// Percent is the completion percentage for the current project, if known
+	Percent *int `json:"percent,omitempty"`
+	// Bytes is the number of bytes received for the current project, if known
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+// FormatCloneProgress formats progress as a log line that can be parsed by ParseCloneProgress
+func FormatCloneProgress(progress CloneProgress) string {
+	progressBytes, err := json.Marshal(progress)
+	if err != nil {
+		// Should never happen
+		return ""
+	}
+	return fmt.Sprintf("%s %s", CloneProgressLogPrefix, string(progressBytes))
+}
+
+// ParseCloneProgress returns the most recent progress reported in project-clone container logs. Lines may be separated
+// by newlines or carriage returns (as used by git to update progress in place); incomplete or malformed progress
+// reports are ignored. Returns nil if logs do not contain any progress reports.
+func ParseCloneProgress(logs string) *CloneProgress {
+	lines := strings.FieldsFunc(logs, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, CloneProgressLogPrefix) {
+			continue
+		}
+		progress := &CloneProgress{}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, CloneProgressLogPrefix)), progress); err != nil {
+			continue
+		}
+		return progress
+	}
+	return nil
+}
+
+// Message returns a human-readable description of progress, suitable for use in a DevWorkspace's status
+func (p *CloneProgress) Message() string {
+	switch {
+	case p.Percent != nil:
+		return fmt.Sprintf("Cloning project %s (%d%%)", p.Project, *p.Percent)
+	case p.Bytes > 0:
+		return fmt.Sprintf("Cloning project %s (%s received)", p.Project, formatBytes(p.Bytes))
+	default:
+		return fmt.Sprintf("Cloning project %s", p.Project)
+	}
+}
+
+// IsProjectCloneRunning returns true if the project-clone init container in pod is currently running
+func IsProjectCloneRunning(pod *corev1.Pod) bool {
+	for _, initContainerStatus := range pod.Status.InitContainerStatuses {
+		if initContainerStatus.Name == projectClonerContainerName {
+			return initContainerStatus.State.Running != nil
+		}
+	}
+	return false
+}
+
+// GetProjectCloneContainerName returns the name used for the project-clone init container
+func GetProjectCloneContainerName() string {
+	return projectClonerContainerName
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCloneProgress(t *testing.T) {
+	fiftyPercent, hundredPercent := 50, 100
+	tests := []struct {
+		name     string
+		logs     string
+		expected *CloneProgress
+	}{
+		{
+			name:     "No progress reported",
+			logs:     "Cloning into 'test-project'...\nremote: Enumerating objects: 302, done.        \n",
+			expected: nil,
+		},
+		{
+			name: "Returns most recent progress",
+			logs: strings.Join([]string{
+				"2022/05/10 13:04:05 Processing project test-project",
+				`[project-clone-progress] {"project":"test-project"}`,
+				"Cloning into 'test-project'...",
+				"remote: Enumerating objects: 302, done.        ",
+				`[project-clone-progress] {"project":"test-project","percent":50}`,
+				"remote: Counting objects: 100% (302/302), done.        ",
+				"",
+			}, "\n"),
+			expected: &CloneProgress{Project: "test-project", Percent: &fiftyPercent},
+		},
+		{
+			name: "Progress separated by carriage returns",
+			logs: "Receiving objects:  49% (148/302)\r" +
+				`[project-clone-progress] {"project":"test-project","percent":50}` + "\r" +
+				"Receiving objects: 100% (302/302)\r" +
+				`[project-clone-progress] {"project":"test-project","percent":100}` + "\r" +
+				"Receiving objects: 100% (302/302), 616.95 KiB | 9.49 MiB/s, done.\n",
+			expected: &CloneProgress{Project: "test-project", Percent: &hundredPercent},
+		},
+		{
+			name: "Partial last line is ignored",
+			logs: `[project-clone-progress] {"project":"test-project","percent":50}` + "\n" +
+				`[project-clone-progress] {"project":"test-proj`,
+			expected: &CloneProgress{Project: "test-project", Percent: &fiftyPercent},
+		},
+		{
+			name: "Partial first line is ignored",
+			logs: `ress] {"project":"other-project","percent":100}` + "\n" +
+				`[project-clone-progress] {"project":"test-project","bytes":2048}` + "\n" +
+				"Receiving objects:  99% (299/302)\r",
+			expected: &CloneProgress{Project: "test-project", Bytes: 2048},
+		},
+		{
+			name:     "Progress written by FormatCloneProgress",
+			logs:     FormatCloneProgress(CloneProgress{Project: "test-project", Percent: &fiftyPercent}) + "\n",
+			expected: &CloneProgress{Project: "test-project", Percent: &fiftyPercent},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseCloneProgress(tt.logs))
+		})
+	}
+}
+
+func TestCloneProgressMessage(t *testing.T) {
+	percent := 42
+	assert.Equal(t, "Cloning project test-project (42%)", (&CloneProgress{Project: "test-project", Percent: &percent, Bytes: 2048}).Message())
+	assert.Equal(t, "Cloning project test-project (1.5 MiB received)", (&CloneProgress{Project: "test-project", Bytes: 1536 * 1024}).Message())
+	assert.Equal(t, "Cloning project test-project (512 B received)", (&CloneProgress{Project: "test-project", Bytes: 512}).Message())
+	assert.Equal(t, "Cloning project test-project", (&CloneProgress{Project: "test-project"}).Message())
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// GetStarterProject returns the starter project selected via the StarterProjectAttribute in the workspace, or nil
+// if no starter project is selected. An error is returned if the selected starter project is not defined in the
+// workspace or cannot be set up by the project-clone container.
+//
+// Note: the workspace should be flattened, as starter projects are commonly defined in a parent devfile.
+func GetStarterProject(workspace *dw.DevWorkspaceTemplateSpec) (*dw.StarterProject, error) {
+	if !workspace.Attributes.Exists(constants.StarterProjectAttribute) {
+		return nil, nil
+	}
+	var err error
+	starterProjectName := workspace.Attributes.GetString(constants.StarterProjectAttribute, &err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute %s: %w", constants.StarterProjectAttribute, err)
+	}
+	if starterProjectName == "" {
+		return nil, nil
+	}
+
+	var starterProject *dw.StarterProject
+	for idx, sp := range workspace.StarterProjects {
+		if sp.Name == starterProjectName {
+			starterProject = &workspace.StarterProjects[idx]
+			break
+		}
+	}
+	if starterProject == nil {
+		return nil, fmt.Errorf("selected starter project %s is not defined in the DevWorkspace", starterProjectName)
+	}
+
+	if starterProject.Git == nil && starterProject.Zip == nil {
+		return nil, fmt.Errorf("starter project %s does not define a git or zip source", starterProjectName)
+	}
+	if starterProject.SubDir != "" {
+		cleanSubDir := path.Clean(starterProject.SubDir)
+		if path.IsAbs(cleanSubDir) || cleanSubDir == ".." || strings.HasPrefix(cleanSubDir, "../") {
+			return nil, fmt.Errorf("subDir %s for starter project %s must be a relative path within the project", starterProject.SubDir, starterProjectName)
+		}
+	}
+	for _, project := range workspace.Projects {
+		clonePath := project.ClonePath
+		if clonePath == "" {
+			clonePath = project.Name
+		}
+		if path.Clean(clonePath) == starterProject.Name {
+			return nil, fmt.Errorf("starter project %s conflicts with project %s, which uses the same path", starterProjectName, project.Name)
+		}
+	}
+
+	return starterProject, nil
+}
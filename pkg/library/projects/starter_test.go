@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetStarterProject(t *testing.T) {
+	gitStarter := dw.StarterProject{
+		Name: "git-starter",
+		ProjectSource: dw.ProjectSource{
+			Git: &dw.GitProjectSource{
+				GitLikeProjectSource: dw.GitLikeProjectSource{
+					Remotes: map[string]string{"origin": "https://github.com/example/git-starter"},
+				},
+			},
+		},
+	}
+	zipStarter := dw.StarterProject{
+		Name: "zip-starter",
+		ProjectSource: dw.ProjectSource{
+			Zip: &dw.ZipProjectSource{Location: "https://example.com/zip-starter.zip"},
+		},
+	}
+	withSubDir := func(starterProject dw.StarterProject, subDir string) dw.StarterProject {
+		starterProject.SubDir = subDir
+		return starterProject
+	}
+
+	tests := []struct {
+		name            string
+		attribute       interface{}
+		starterProjects []dw.StarterProject
+		projects        []dw.Project
+		expected        *dw.StarterProject
+		expectedErr     string
+	}{
+		{
+			name:            "No starter project selected",
+			starterProjects: []dw.StarterProject{gitStarter},
+		},
+		{
+			name:            "Empty starter project attribute",
+			attribute:       "",
+			starterProjects: []dw.StarterProject{gitStarter},
+		},
+		{
+			name:            "Selects git starter project",
+			attribute:       "git-starter",
+			starterProjects: []dw.StarterProject{zipStarter, gitStarter},
+			expected:        &gitStarter,
+		},
+		{
+			name:            "Selects zip starter project with subDir",
+			attribute:       "zip-starter",
+			starterProjects: []dw.StarterProject{withSubDir(zipStarter, "backend/src")},
+			expected:        &dw.StarterProject{Name: "zip-starter", SubDir: "backend/src", ProjectSource: zipStarter.ProjectSource},
+		},
+		{
+			name:        "Attribute is not a string",
+			attribute:   map[string]string{"name": "git-starter"},
+			expectedErr: "failed to read attribute " + constants.StarterProjectAttribute,
+		},
+		{
+			name:            "Selected starter project is not defined",
+			attribute:       "missing-starter",
+			starterProjects: []dw.StarterProject{gitStarter},
+			expectedErr:     "selected starter project missing-starter is not defined in the DevWorkspace",
+		},
+		{
+			name:      "Starter project without git or zip source",
+			attribute: "custom-starter",
+			starterProjects: []dw.StarterProject{{
+				Name: "custom-starter",
+				ProjectSource: dw.ProjectSource{
+					Custom: &dw.CustomProjectSource{ProjectSourceClass: "test"},
+				},
+			}},
+			expectedErr: "starter project custom-starter does not define a git or zip source",
+		},
+		{
+			name:            "Absolute subDir",
+			attribute:       "git-starter",
+			starterProjects: []dw.StarterProject{withSubDir(gitStarter, "/etc")},
+			expectedErr:     "subDir /etc for starter project git-starter must be a relative path within the project",
+		},
+		{
+			name:            "SubDir outside of project",
+			attribute:       "git-starter",
+			starterProjects: []dw.StarterProject{withSubDir(gitStarter, "src/../../other")},
+			expectedErr:     "subDir src/../../other for starter project git-starter must be a relative path within the project",
+		},
+		{
+			name:            "Starter project conflicts with project name",
+			attribute:       "git-starter",
+			starterProjects: []dw.StarterProject{gitStarter},
+			projects:        []dw.Project{{Name: "git-starter"}},
+			expectedErr:     "starter project git-starter conflicts with project git-starter, which uses the same path",
+		},
+		{
+			name:            "Starter project conflicts with project clonePath",
+			attribute:       "git-starter",
+			starterProjects: []dw.StarterProject{gitStarter},
+			projects:        []dw.Project{{Name: "other-project", ClonePath: "./git-starter/"}},
+			expectedErr:     "starter project git-starter conflicts with project other-project, which uses the same path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					StarterProjects: tt.starterProjects,
+					Projects:        tt.projects,
+				},
+			}
+			if tt.attribute != nil {
+				workspace.Attributes = attributes.Attributes{}.Put(constants.StarterProjectAttribute, tt.attribute, nil)
+			}
+			starterProject, err := GetStarterProject(workspace)
+			if tt.expectedErr != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Contains(t, err.Error(), tt.expectedErr)
+				}
+				return
+			}
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.Equal(t, tt.expected, starterProject)
+		})
+	}
+}
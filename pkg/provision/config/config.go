@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 )
 
@@ -106,3 +107,75 @@ func GetNamespacePodTolerationsAndNodeSelector(namespace string, api sync.Cluste
 
 	return podTolerations, nodeSelector, nil
 }
+
+// GetNamespacePodAffinityAndTopologySpreadConstraints gets the pod affinity and topology spread constraints that should
+// be applied to all pods created for workspaces in a given namespace. Values are unmarshalled from json-formatted
+// annotations on the namespace itself. Returns an error if annotations are not valid JSON.
+func GetNamespacePodAffinityAndTopologySpreadConstraints(namespace string, api sync.ClusterAPI) (*corev1.Affinity, []corev1.TopologySpreadConstraint, error) {
+	ns := &corev1.Namespace{}
+	err := api.Client.Get(api.Ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var affinity *corev1.Affinity
+	affinityAnnot, ok := ns.Annotations[constants.NamespacePodAffinityAnnotation]
+	if ok && affinityAnnot != "" {
+		affinity = &corev1.Affinity{}
+		if err := json.Unmarshal([]byte(affinityAnnot), affinity); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s annotation: %w", constants.NamespacePodAffinityAnnotation, err)
+		}
+	}
+
+	var topologySpreadConstraints []corev1.TopologySpreadConstraint
+	constraintsAnnot, ok := ns.Annotations[constants.NamespaceTopologySpreadConstraintsAnnotation]
+	if ok && constraintsAnnot != "" {
+		if err := json.Unmarshal([]byte(constraintsAnnot), &topologySpreadConstraints); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s annotation: %w", constants.NamespaceTopologySpreadConstraintsAnnotation, err)
+		}
+	}
+
+	return affinity, topologySpreadConstraints, nil
+}
+
+// GetDevfileVariables returns the variables that should be available for substitution in DevWorkspaces in a namespace.
+// Variables are read from the global configuration and from configmaps in the namespace labelled with
+// constants.DevfileVariablesLabel, with variables defined in the namespace taking precedence. Returns an error if
+// multiple configmaps in the namespace define different values for the same variable.
+func GetDevfileVariables(namespace string, api sync.ClusterAPI) (map[string]string, error) {
+	cmList := &corev1.ConfigMapList{}
+	labelSelector, err := labels.Parse(fmt.Sprintf("%s=true", constants.DevfileVariablesLabel))
+	if err != nil {
+		return nil, err
+	}
+	selector := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+	}
+	if err := api.Client.List(api.Ctx, cmList, selector); err != nil {
+		return nil, err
+	}
+
+	namespaceVariables := map[string]string{}
+	variableSources := map[string]string{}
+	for _, cm := range cmList.Items {
+		for name, value := range cm.Data {
+			if existing, ok := namespaceVariables[name]; ok && existing != value {
+				return nil, fmt.Errorf("variable %s is defined with different values in configmaps %s and %s", name, variableSources[name], cm.Name)
+			}
+			namespaceVariables[name] = value
+			variableSources[name] = cm.Name
+		}
+	}
+
+	devfileVariables := map[string]string{}
+	if config.Workspace != nil {
+		for name, value := range config.Workspace.DevfileVariables {
+			devfileVariables[name] = value
+		}
+	}
+	for name, value := range namespaceVariables {
+		devfileVariables[name] = value
+	}
+	return devfileVariables, nil
+}
@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagebuild
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const authConfigFilename = "config.json"
+
+// dockerConfigJson is the format of the .dockerconfigjson key in kubernetes.io/dockerconfigjson secrets and of
+// the config.json file read by builders
+type dockerConfigJson struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// syncAuthSecret merges the registry credentials in the workspace's pull secrets into a single secret that can be
+// mounted into build containers. Returns the name of the secret, or an empty string if the workspace does not
+// use any pull secrets.
+func syncAuthSecret(workspace *dw.DevWorkspace, pullSecrets []corev1.LocalObjectReference, api sync.ClusterAPI) (string, error) {
+	merged := dockerConfigJson{Auths: map[string]json.RawMessage{}}
+	for _, pullSecret := range pullSecrets {
+		auths, err := readPullSecretAuths(pullSecret.Name, workspace.Namespace, api)
+		if err != nil {
+			return "", err
+		}
+		for registry, auth := range auths {
+			if _, exists := merged.Auths[registry]; !exists {
+				merged.Auths[registry] = auth
+			}
+		}
+	}
+	if len(merged.Auths) == 0 {
+		return "", nil
+	}
+	mergedJson, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ImageBuildAuthSecretName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:          workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceWatchSecretLabel: "true",
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: mergedJson,
+		},
+	}
+	if err := controllerutil.SetControllerReference(workspace, secret, api.Scheme); err != nil {
+		return "", err
+	}
+	if _, err := sync.SyncObjectWithCluster(secret, api); err != nil {
+		switch t := err.(type) {
+		case *sync.NotInSyncError:
+			return "", &NotReadyError{Message: "Preparing registry credentials for image build"}
+		case *sync.UnrecoverableSyncError:
+			return "", &ProvisioningError{Message: "Failed to sync image build credentials with cluster", Err: t.Cause}
+		default:
+			return "", err
+		}
+	}
+	return secret.Name, nil
+}
+
+// readPullSecretAuths reads the credentials for each registry defined in a pull secret. Pull secrets are read
+// without using the controller's cache, as they may not be labelled to be watched by the controller (e.g.
+// pull secrets created for the workspace's ServiceAccount on OpenShift).
+func readPullSecretAuths(name, namespace string, api sync.ClusterAPI) (map[string]json.RawMessage, error) {
+	secret := &corev1.Secret{}
+	err := api.NonCachingClient.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		config := dockerConfigJson{}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+			return nil, fmt.Errorf("failed to read pull secret %s: %w", name, err)
+		}
+		return config.Auths, nil
+	case corev1.SecretTypeDockercfg:
+		auths := map[string]json.RawMessage{}
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			return nil, fmt.Errorf("failed to read pull secret %s: %w", name, err)
+		}
+		return auths, nil
+	default:
+		return nil, nil
+	}
+}
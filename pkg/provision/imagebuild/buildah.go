@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagebuild
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	buildahDefaultImage = "quay.io/buildah/stable:v1.28"
+
+	// buildahBuildScript builds and pushes an image. Values are passed as environment variables and additional
+	// build arguments as positional parameters to avoid interpreting them in the shell.
+	buildahBuildScript = `buildah bud --storage-driver=vfs --isolation=chroot -f "$DOCKERFILE" -t "$IMAGE" "$@" "$BUILD_CONTEXT" && ` +
+		`buildah push --storage-driver=vfs --digestfile "$DIGEST_FILE" "$IMAGE"`
+)
+
+// buildahBuilder builds images using Buildah with chroot isolation and the vfs storage driver.
+type buildahBuilder struct{}
+
+var _ Builder = (*buildahBuilder)(nil)
+
+func (b *buildahBuilder) DefaultImage() string {
+	return buildahDefaultImage
+}
+
+func (b *buildahBuilder) GetBuildContainer(builderImage string, spec *BuildSpec) (*corev1.Container, error) {
+	buildContext := spec.Context
+	dockerfile := spec.Dockerfile
+	if spec.Git != nil {
+		if spec.Git.SubPath != "" {
+			return nil, fmt.Errorf("buildah builder does not support build contexts in a subdirectory of a Git repository")
+		}
+		buildContext = spec.Git.URL
+		if spec.Git.Revision != "" {
+			buildContext = fmt.Sprintf("%s#%s", buildContext, spec.Git.Revision)
+		}
+	}
+
+	rootUser := int64(0)
+	runAsNonRoot := false
+	container := &corev1.Container{
+		Image:   builderImage,
+		Command: []string{"/bin/sh"},
+		Args:    append([]string{"-c", buildahBuildScript, "buildah"}, spec.Args...),
+		Env: []corev1.EnvVar{
+			{Name: "IMAGE", Value: spec.ImageName},
+			{Name: "DOCKERFILE", Value: dockerfile},
+			{Name: "BUILD_CONTEXT", Value: buildContext},
+			{Name: "DIGEST_FILE", Value: digestFilePath},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    &rootUser,
+			RunAsNonRoot: &runAsNonRoot,
+		},
+	}
+	if spec.RootRequired {
+		privileged := true
+		container.SecurityContext.Privileged = &privileged
+	}
+	if spec.AuthPath != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "REGISTRY_AUTH_FILE",
+			Value: path.Join(spec.AuthPath, "config.json"),
+		})
+	}
+	return container, nil
+}
@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagebuild
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+)
+
+const (
+	KanikoBuilderName  = "kaniko"
+	BuildahBuilderName = "buildah"
+
+	// digestFilePath is the path builders write the digest of the pushed image to. Using the termination message
+	// path allows the controller to read the digest from the build pod's status.
+	digestFilePath = corev1.TerminationMessagePathDefault
+)
+
+// Builder creates containers that build and push images for image components in a DevWorkspace.
+type Builder interface {
+	// DefaultImage returns the image used to run builds when no builder image is configured.
+	DefaultImage() string
+	// GetBuildContainer returns a container using builderImage that builds the image described by spec and pushes it
+	// to spec.ImageName. The container must write the digest of the pushed image to /dev/termination-log.
+	GetBuildContainer(builderImage string, spec *BuildSpec) (*corev1.Container, error)
+}
+
+// BuildSpec describes an image build
+type BuildSpec struct {
+	// ImageName is the reference the built image is pushed to
+	ImageName string
+	// Context is the absolute path of the local directory used as the build context. Ignored if Git is set.
+	Context string
+	// Dockerfile is the path to the Dockerfile. For local contexts, this is an absolute path; for Git contexts,
+	// it is relative to the root of the repository.
+	Dockerfile string
+	// Git, if set, defines a Git repository used as the build context
+	Git *GitContext
+	// Args are additional arguments passed to the builder
+	Args []string
+	// RootRequired defines whether the build requires a privileged container
+	RootRequired bool
+	// AuthPath, if set, is the path of a directory containing a config.json file with credentials for registries
+	AuthPath string
+}
+
+// GitContext describes a Git repository used as a build context
+type GitContext struct {
+	// URL is the URL of the repository
+	URL string
+	// Revision is the branch to build from. If empty, the repository's default branch is used.
+	Revision string
+	// SubPath is the directory within the repository to use as the build context, if not the root of the repository.
+	SubPath string
+}
+
+var builders = map[string]Builder{
+	KanikoBuilderName:  &kanikoBuilder{},
+	BuildahBuilderName: &buildahBuilder{},
+}
+
+// RegisterBuilder makes a builder available under name. Builders are selected using the
+// workspace.imageBuild.builder field in the DevWorkspaceOperatorConfig.
+func RegisterBuilder(name string, builder Builder) {
+	builders[name] = builder
+}
+
+// checkBuildContainerAllowed returns an error if a build container runs as root or is privileged and the
+// workspace.imageBuild configuration in the DevWorkspaceOperatorConfig does not allow it.
+func checkBuildContainerAllowed(container *corev1.Container) error {
+	securityContext := container.SecurityContext
+	if securityContext == nil {
+		return nil
+	}
+	imageBuildConfig := config.Workspace.ImageBuild
+	if securityContext.Privileged != nil && *securityContext.Privileged {
+		if imageBuildConfig == nil || imageBuildConfig.AllowPrivilegedBuilds == nil || !*imageBuildConfig.AllowPrivilegedBuilds {
+			return fmt.Errorf("builds requiring privileged containers are disabled; they can be enabled by an administrator via workspace.imageBuild.allowPrivilegedBuilds")
+		}
+	}
+	if securityContext.RunAsUser != nil && *securityContext.RunAsUser == 0 {
+		if imageBuildConfig == nil || imageBuildConfig.AllowRootBuilds == nil || !*imageBuildConfig.AllowRootBuilds {
+			return fmt.Errorf("builds running as root are disabled; they can be enabled by an administrator via workspace.imageBuild.allowRootBuilds")
+		}
+	}
+	return nil
+}
+
+// getBuilder returns the configured builder and the image that should be used to run it
+func getBuilder() (Builder, string, error) {
+	builderName := KanikoBuilderName
+	builderImage := ""
+	if config.Workspace.ImageBuild != nil {
+		if config.Workspace.ImageBuild.Builder != "" {
+			builderName = config.Workspace.ImageBuild.Builder
+		}
+		builderImage = config.Workspace.ImageBuild.BuilderImage
+	}
+	builder, ok := builders[builderName]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported image builder '%s'", builderName)
+	}
+	if builderImage == "" {
+		builderImage = builder.DefaultImage()
+	}
+	return builder, builderImage, nil
+}
@@ -0,0 +1,518 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package imagebuild builds devfile image components in-cluster before a DevWorkspace's deployment is created.
+// Each image component is built by a Job in the DevWorkspace's namespace using a pluggable Builder, and the
+// resulting image reference (including digest) replaces references to the image in the DevWorkspace's containers.
+package imagebuild
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+const (
+	builderContainerName  = "image-build"
+	authVolumeName        = "image-build-auth"
+	authMountPath         = "/devworkspace-image-build-auth"
+	defaultDockerfilePath = "Dockerfile"
+
+	projectsRootVariable  = "${PROJECTS_ROOT}"
+	projectSourceVariable = "${PROJECT_SOURCE}"
+)
+
+var buildJobBackoffLimit = int32(0)
+
+// GetImageComponents returns the image components in a flattened DevWorkspace that should be built when the
+// DevWorkspace starts: image components referenced by apply commands bound to the preStart event, and image
+// components with autoBuild set to true. Returns an error if any of these components cannot be built. As permission
+// to build images is only checked for components defined in the DevWorkspace itself, image components imported from
+// a parent or plugin cannot be built.
+func GetImageComponents(workspace *dw.DevWorkspaceTemplateSpec) ([]dw.Component, error) {
+	buildComponents := map[string]bool{}
+	if workspace.Events != nil {
+		for _, eventCommand := range workspace.Events.PreStart {
+			for _, command := range workspace.Commands {
+				if command.Id == eventCommand && command.Apply != nil {
+					buildComponents[command.Apply.Component] = true
+				}
+			}
+		}
+	}
+
+	var imageComponents []dw.Component
+	for _, component := range workspace.Components {
+		if component.Image == nil {
+			continue
+		}
+		autoBuild := component.Image.AutoBuild != nil && *component.Image.AutoBuild
+		if !autoBuild && !buildComponents[component.Name] {
+			continue
+		}
+		if component.Attributes.Exists(constants.PluginSourceAttribute) {
+			var err error
+			source := component.Attributes.GetString(constants.PluginSourceAttribute, &err)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read attribute %s on component %s: %w", constants.PluginSourceAttribute, component.Name, err)
+			}
+			return nil, fmt.Errorf("image component %s is imported by %s; only image components defined in the DevWorkspace can be built", component.Name, source)
+		}
+		if _, err := getBuildSpec(component, workspace.Projects); err != nil {
+			return nil, err
+		}
+		imageComponents = append(imageComponents, component)
+	}
+	return imageComponents, nil
+}
+
+// BuildImages builds the image components returned by GetImageComponents, returning a map from the image name
+// of each component to the reference of the built image. Build jobs use the workspace's ServiceAccount and mount
+// the project-clone init container and volumes defined in podAdditions, so that images are built from the
+// workspace's projects. Credentials for pushing images are read from the pull secrets defined in podAdditions.
+//
+// If builds are still running, a NotReadyError is returned. If a build fails, a ProvisioningError is returned.
+func BuildImages(workspace *dw.DevWorkspace, imageComponents []dw.Component, podAdditions []v1alpha1.PodAdditions,
+	serviceAccountName string, api sync.ClusterAPI) (map[string]string, error) {
+
+	builder, builderImage, err := getBuilder()
+	if err != nil {
+		return nil, &ProvisioningError{Message: "Failed to get image builder", Err: err}
+	}
+
+	var pullSecrets []corev1.LocalObjectReference
+	for _, additions := range podAdditions {
+		pullSecrets = append(pullSecrets, additions.PullSecrets...)
+	}
+	authSecretName, err := syncAuthSecret(workspace, pullSecrets, api)
+	if err != nil {
+		return nil, err
+	}
+
+	builtImages := map[string]string{}
+	var pendingBuilds []string
+	for _, component := range imageComponents {
+		spec, err := getBuildSpec(component, workspace.Spec.Template.Projects)
+		if err != nil {
+			return nil, &ProvisioningError{Message: fmt.Sprintf("Failed to build image component %s", component.Name), Err: err}
+		}
+		if authSecretName != "" {
+			spec.AuthPath = authMountPath
+		}
+		buildContainer, err := builder.GetBuildContainer(builderImage, spec)
+		if err != nil {
+			return nil, &ProvisioningError{Message: fmt.Sprintf("Failed to build image component %s", component.Name), Err: err}
+		}
+		if err := checkBuildContainerAllowed(buildContainer); err != nil {
+			return nil, &ProvisioningError{Message: fmt.Sprintf("Failed to build image component %s", component.Name), Err: err}
+		}
+		specJob, err := getSpecBuildJob(workspace, component.Name, buildContainer, spec, podAdditions, pullSecrets,
+			serviceAccountName, authSecretName, api)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterObj, err := sync.SyncObjectWithCluster(specJob, api)
+		switch t := err.(type) {
+		case nil:
+			break
+		case *sync.NotInSyncError:
+			pendingBuilds = append(pendingBuilds, component.Name)
+			continue
+		case *sync.UnrecoverableSyncError:
+			return nil, &ProvisioningError{Message: fmt.Sprintf("Failed to sync image build job for component %s", component.Name), Err: t.Cause}
+		default:
+			return nil, err
+		}
+
+		clusterJob := clusterObj.(*batchv1.Job)
+		complete, failed := getJobStatus(clusterJob)
+		switch {
+		case failed:
+			return nil, &ProvisioningError{
+				Message: fmt.Sprintf("Image build for component %s failed: see logs for job %q for details", component.Name, clusterJob.Name),
+			}
+		case !complete:
+			pendingBuilds = append(pendingBuilds, component.Name)
+			continue
+		}
+
+		builtImage, err := getBuiltImage(clusterJob, component.Image.ImageName, api)
+		if err != nil {
+			return nil, err
+		}
+		builtImages[component.Image.ImageName] = builtImage
+	}
+
+	if len(pendingBuilds) > 0 {
+		return nil, &NotReadyError{
+			Message:      fmt.Sprintf("Building images for components %s", strings.Join(pendingBuilds, ", ")),
+			RequeueAfter: 5 * time.Second,
+		}
+	}
+	return builtImages, nil
+}
+
+// DeleteBuildJobs deletes the image build jobs of a DevWorkspace. Image components are built each time the DevWorkspace
+// starts, so build jobs are removed when the DevWorkspace is stopped to ensure changes to the sources of an image are
+// picked up on the next start.
+func DeleteBuildJobs(workspace *dw.DevWorkspace, api sync.ClusterAPI) error {
+	workspaceIDRequirement, err := labels.NewRequirement(constants.DevWorkspaceIDLabel, selection.Equals, []string{workspace.Status.DevWorkspaceId})
+	if err != nil {
+		return err
+	}
+	imageComponentRequirement, err := labels.NewRequirement(constants.DevWorkspaceImageComponentLabel, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	jobs := &batchv1.JobList{}
+	err = api.Client.List(api.Ctx, jobs, client.InNamespace(workspace.Namespace), client.MatchingLabelsSelector{
+		Selector: labels.NewSelector().Add(*workspaceIDRequirement, *imageComponentRequirement),
+	})
+	if err != nil {
+		return err
+	}
+	for idx := range jobs.Items {
+		err := api.Client.Delete(api.Ctx, &jobs.Items[idx], client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectBuiltImages replaces the image of every container and init container in podAdditions that uses an image
+// name that was built with the reference to the built image.
+func InjectBuiltImages(podAdditions []v1alpha1.PodAdditions, builtImages map[string]string) {
+	for idx := range podAdditions {
+		for _, containers := range [][]corev1.Container{podAdditions[idx].Containers, podAdditions[idx].InitContainers} {
+			for cIdx, container := range containers {
+				if builtImage, ok := builtImages[container.Image]; ok {
+					containers[cIdx].Image = builtImage
+				}
+			}
+		}
+	}
+}
+
+// FormatBuiltImages returns a message listing the images that were built
+func FormatBuiltImages(builtImages map[string]string) string {
+	var images []string
+	for _, builtImage := range builtImages {
+		images = append(images, builtImage)
+	}
+	sort.Strings(images)
+	return fmt.Sprintf("Built images %s", strings.Join(images, ", "))
+}
+
+// getBuildSpec returns the BuildSpec for an image component. Dockerfiles can be read from a relative URI (relative
+// to the source of the first project in the workspace) or from a Git repository.
+func getBuildSpec(component dw.Component, projects []dw.Project) (*BuildSpec, error) {
+	image := component.Image
+	if image.Dockerfile == nil {
+		return nil, fmt.Errorf("image component %s does not define a Dockerfile build", component.Name)
+	}
+	dockerfile := image.Dockerfile
+	spec := &BuildSpec{
+		ImageName:    image.ImageName,
+		Args:         dockerfile.Args,
+		RootRequired: dockerfile.RootRequired != nil && *dockerfile.RootRequired,
+	}
+
+	switch {
+	case dockerfile.Git != nil:
+		gitContext, err := getGitContext(dockerfile.Git)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Git source for image component %s: %w", component.Name, err)
+		}
+		gitContext.SubPath = strings.TrimPrefix(dockerfile.BuildContext, "/")
+		spec.Git = gitContext
+		spec.Dockerfile = dockerfile.Git.FileLocation
+		if spec.Dockerfile == "" {
+			spec.Dockerfile = defaultDockerfilePath
+		}
+	case dockerfile.Uri != "":
+		if urlSchemeRegexp.MatchString(dockerfile.Uri) || path.IsAbs(dockerfile.Uri) {
+			return nil, fmt.Errorf("image component %s: only Dockerfile URIs relative to the project source are supported", component.Name)
+		}
+		if len(projects) == 0 {
+			return nil, fmt.Errorf("image component %s uses a Dockerfile from project sources, but no projects are defined", component.Name)
+		}
+		projectSource := getProjectSourcePath(projects)
+		spec.Dockerfile = path.Join(projectSource, dockerfile.Uri)
+		spec.Context = resolveBuildContext(dockerfile.BuildContext, projectSource)
+	case dockerfile.DevfileRegistry != nil:
+		return nil, fmt.Errorf("image component %s: Dockerfiles from devfile registries are not supported", component.Name)
+	default:
+		return nil, fmt.Errorf("image component %s does not define a source for its Dockerfile", component.Name)
+	}
+	return spec, nil
+}
+
+func getGitContext(source *dw.DockerfileGitProjectSource) (*GitContext, error) {
+	remotes := source.Remotes
+	if len(remotes) == 0 {
+		return nil, fmt.Errorf("no remotes are defined")
+	}
+	remoteName, revision := "", ""
+	if source.CheckoutFrom != nil {
+		remoteName = source.CheckoutFrom.Remote
+		revision = source.CheckoutFrom.Revision
+	}
+	if remoteName == "" {
+		if len(remotes) > 1 {
+			return nil, fmt.Errorf("checkoutFrom remote can't be omitted with multiple remotes")
+		}
+		for name := range remotes {
+			remoteName = name
+		}
+	}
+	remoteURL, ok := remotes[remoteName]
+	if !ok {
+		return nil, fmt.Errorf("checkoutFrom refers to non-existing remote %s", remoteName)
+	}
+	return &GitContext{URL: remoteURL, Revision: revision}, nil
+}
+
+// getProjectSourcePath returns the path that is used for the PROJECT_SOURCE environment variable in workspace containers
+func getProjectSourcePath(projects []dw.Project) string {
+	firstProject := projects[0]
+	if firstProject.ClonePath != "" {
+		return path.Join(constants.DefaultProjectsSourcesRoot, firstProject.ClonePath)
+	}
+	return path.Join(constants.DefaultProjectsSourcesRoot, firstProject.Name)
+}
+
+// resolveBuildContext returns the absolute path of a Dockerfile build context, resolving references to the
+// PROJECTS_ROOT and PROJECT_SOURCE variables. Relative paths are resolved against the project source.
+func resolveBuildContext(buildContext, projectSource string) string {
+	buildContext = strings.ReplaceAll(buildContext, projectsRootVariable, constants.DefaultProjectsSourcesRoot)
+	buildContext = strings.ReplaceAll(buildContext, projectSourceVariable, projectSource)
+	if buildContext == "" {
+		return projectSource
+	}
+	if !path.IsAbs(buildContext) {
+		return path.Join(projectSource, buildContext)
+	}
+	return path.Clean(buildContext)
+}
+
+func getSpecBuildJob(workspace *dw.DevWorkspace, componentName string, buildContainer *corev1.Container, spec *BuildSpec,
+	podAdditions []v1alpha1.PodAdditions, pullSecrets []corev1.LocalObjectReference, serviceAccountName, authSecretName string,
+	api sync.ClusterAPI) (*batchv1.Job, error) {
+
+	workspaceId := workspace.Status.DevWorkspaceId
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:             workspaceId,
+		constants.DevWorkspaceNameLabel:           workspace.Name,
+		constants.DevWorkspaceImageComponentLabel: componentName,
+	}
+	if restrictedAccess, needsRestrictedAccess := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; needsRestrictedAccess {
+		jobLabels[constants.DevWorkspaceRestrictedAccessAnnotation] = restrictedAccess
+	}
+
+	buildContainer.Name = builderContainerName
+	var initContainers []corev1.Container
+	var volumes []corev1.Volume
+	if spec.Git == nil {
+		// Local builds require the workspace's projects; clone them using the same init container and volumes
+		// as the workspace deployment
+		cloneContainer, cloneVolumes := getProjectCloneContainer(podAdditions)
+		if cloneContainer == nil {
+			return nil, &ProvisioningError{Message: fmt.Sprintf("Image component %s requires projects, but projects are not cloned", componentName)}
+		}
+		for _, vm := range cloneContainer.VolumeMounts {
+			if vm.MountPath == constants.DefaultProjectsSourcesRoot {
+				buildContainer.VolumeMounts = append(buildContainer.VolumeMounts, vm)
+			}
+		}
+		initContainers = append(initContainers, *cloneContainer)
+		volumes = append(volumes, cloneVolumes...)
+	}
+	if authSecretName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: authVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: authSecretName,
+					Items: []corev1.KeyToPath{
+						{Key: corev1.DockerConfigJsonKey, Path: authConfigFilename},
+					},
+				},
+			},
+		})
+		buildContainer.VolumeMounts = append(buildContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      authVolumeName,
+			MountPath: authMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ImageBuildJobName(workspaceId, componentName),
+			Namespace: workspace.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &buildJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					SecurityContext:    wsprovision.GetDevWorkspaceSecurityContext(),
+					ServiceAccountName: serviceAccountName,
+					ImagePullSecrets:   pullSecrets,
+					InitContainers:     initContainers,
+					Containers:         []corev1.Container{*buildContainer},
+					Volumes:            volumes,
+				},
+			},
+		},
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, api)
+	if err != nil {
+		return nil, err
+	}
+	if len(podTolerations) > 0 {
+		job.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		job.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+
+	if err := controllerutil.SetControllerReference(workspace, job, api.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// getProjectCloneContainer returns a copy of the project-clone init container in podAdditions, along with the
+// volumes it mounts. Returns nil if podAdditions does not contain a project-clone init container.
+func getProjectCloneContainer(podAdditions []v1alpha1.PodAdditions) (*corev1.Container, []corev1.Volume) {
+	var cloneContainer *corev1.Container
+	allVolumes := map[string]corev1.Volume{}
+	for _, additions := range podAdditions {
+		for _, container := range additions.InitContainers {
+			if container.Name == projects.GetProjectCloneContainerName() {
+				cloneContainer = container.DeepCopy()
+			}
+		}
+		for _, volume := range additions.Volumes {
+			allVolumes[volume.Name] = volume
+		}
+	}
+	if cloneContainer == nil {
+		return nil, nil
+	}
+	var volumes []corev1.Volume
+	added := map[string]bool{}
+	for _, vm := range cloneContainer.VolumeMounts {
+		if volume, ok := allVolumes[vm.Name]; ok && !added[vm.Name] {
+			volumes = append(volumes, volume)
+			added[vm.Name] = true
+		}
+	}
+	return cloneContainer, volumes
+}
+
+func getJobStatus(job *batchv1.Job) (complete, failed bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, false
+		case batchv1.JobFailed:
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// getBuiltImage returns the reference, including digest, of the image built by a completed build job. The digest
+// is read from the termination message of the job's build container and stored in an annotation on the job,
+// so that it is available after the job's pod is removed.
+func getBuiltImage(job *batchv1.Job, imageName string, api sync.ClusterAPI) (string, error) {
+	if builtImage := job.Annotations[constants.DevWorkspaceBuiltImageAnnotation]; builtImage != "" {
+		return builtImage, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := api.Client.List(api.Ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+	var digest string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == builderContainerName && status.State.Terminated != nil {
+				digest = strings.TrimSpace(status.State.Terminated.Message)
+			}
+		}
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		return "", &ProvisioningError{Message: fmt.Sprintf("Could not determine digest of image built by job %q", job.Name)}
+	}
+
+	builtImage := fmt.Sprintf("%s@%s", getImageRepository(imageName), digest)
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[constants.DevWorkspaceBuiltImageAnnotation] = builtImage
+	if err := api.Client.Update(api.Ctx, job); err != nil {
+		if k8sErrors.IsConflict(err) {
+			return "", &NotReadyError{Message: "Recording built image"}
+		}
+		return "", err
+	}
+	return builtImage, nil
+}
+
+// getImageRepository returns an image name without its tag or digest
+func getImageRepository(imageName string) string {
+	repository := strings.SplitN(imageName, "@", 2)[0]
+	lastSlash := strings.LastIndex(repository, "/")
+	if tagIdx := strings.LastIndex(repository, ":"); tagIdx > lastSlash {
+		repository = repository[:tagIdx]
+	}
+	return repository
+}
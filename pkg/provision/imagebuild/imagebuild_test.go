@@ -0,0 +1,275 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagebuild
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func TestGetImageComponents(t *testing.T) {
+	autoBuild := true
+	workspace := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Projects: []dw.Project{{Name: "test-project"}},
+			Components: []dw.Component{
+				testImageComponent("prestart-image", nil),
+				testImageComponent("autobuild-image", &autoBuild),
+				testImageComponent("unused-image", nil),
+			},
+			Commands: []dw.Command{
+				{
+					Id: "build",
+					CommandUnion: dw.CommandUnion{
+						Apply: &dw.ApplyCommand{Component: "prestart-image"},
+					},
+				},
+			},
+			Events: &dw.Events{
+				DevWorkspaceEvents: dw.DevWorkspaceEvents{PreStart: []string{"build"}},
+			},
+		},
+	}
+
+	components, err := GetImageComponents(workspace)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var names []string
+	for _, component := range components {
+		names = append(names, component.Name)
+	}
+	assert.Equal(t, []string{"prestart-image", "autobuild-image"}, names)
+}
+
+func TestGetBuildSpec(t *testing.T) {
+	projects := []dw.Project{{Name: "test-project", ClonePath: "src/app"}}
+	tests := []struct {
+		name         string
+		dockerfile   dw.DockerfileImage
+		expectedSpec *BuildSpec
+		errRegexp    string
+	}{
+		{
+			name: "Resolves relative URI and default build context against project source",
+			dockerfile: dw.DockerfileImage{
+				DockerfileSrc: dw.DockerfileSrc{Uri: "build/Dockerfile"},
+			},
+			expectedSpec: &BuildSpec{
+				ImageName:  "test-image",
+				Dockerfile: "/projects/src/app/build/Dockerfile",
+				Context:    "/projects/src/app",
+			},
+		},
+		{
+			name: "Resolves variables in build context",
+			dockerfile: dw.DockerfileImage{
+				DockerfileSrc: dw.DockerfileSrc{Uri: "Dockerfile"},
+				Dockerfile:    dw.Dockerfile{BuildContext: "${PROJECTS_ROOT}/other"},
+			},
+			expectedSpec: &BuildSpec{
+				ImageName:  "test-image",
+				Dockerfile: "/projects/src/app/Dockerfile",
+				Context:    "/projects/other",
+			},
+		},
+		{
+			name: "Uses Git source",
+			dockerfile: dw.DockerfileImage{
+				DockerfileSrc: dw.DockerfileSrc{
+					Git: &dw.DockerfileGitProjectSource{
+						GitProjectSource: dw.GitProjectSource{
+							GitLikeProjectSource: dw.GitLikeProjectSource{
+								Remotes:      map[string]string{"origin": "https://github.com/example/repo.git"},
+								CheckoutFrom: &dw.CheckoutFrom{Revision: "main"},
+							},
+						},
+					},
+				},
+				Dockerfile: dw.Dockerfile{BuildContext: "app"},
+			},
+			expectedSpec: &BuildSpec{
+				ImageName:  "test-image",
+				Dockerfile: "Dockerfile",
+				Git: &GitContext{
+					URL:      "https://github.com/example/repo.git",
+					Revision: "main",
+					SubPath:  "app",
+				},
+			},
+		},
+		{
+			name: "Rejects absolute Dockerfile URIs",
+			dockerfile: dw.DockerfileImage{
+				DockerfileSrc: dw.DockerfileSrc{Uri: "https://example.com/Dockerfile"},
+			},
+			errRegexp: "only Dockerfile URIs relative to the project source are supported",
+		},
+		{
+			name: "Rejects Dockerfiles from devfile registries",
+			dockerfile: dw.DockerfileImage{
+				DockerfileSrc: dw.DockerfileSrc{DevfileRegistry: &dw.DockerfileDevfileRegistrySource{Id: "test"}},
+			},
+			errRegexp: "Dockerfiles from devfile registries are not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := testImageComponent("test-component", nil)
+			component.Image.Dockerfile = &tt.dockerfile
+			spec, err := getBuildSpec(component, projects)
+			if tt.errRegexp != "" {
+				assert.Regexp(t, tt.errRegexp, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expectedSpec, spec)
+			}
+		})
+	}
+}
+
+func TestInjectBuiltImages(t *testing.T) {
+	podAdditions := []v1alpha1.PodAdditions{
+		{
+			Containers:     []corev1.Container{{Name: "app", Image: "test-image"}, {Name: "other", Image: "other-image"}},
+			InitContainers: []corev1.Container{{Name: "init", Image: "test-image"}},
+		},
+	}
+	InjectBuiltImages(podAdditions, map[string]string{"test-image": "test-image@sha256:abc"})
+	assert.Equal(t, "test-image@sha256:abc", podAdditions[0].Containers[0].Image)
+	assert.Equal(t, "other-image", podAdditions[0].Containers[1].Image)
+	assert.Equal(t, "test-image@sha256:abc", podAdditions[0].InitContainers[0].Image)
+}
+
+func TestGetImageComponentsRejectsImportedComponents(t *testing.T) {
+	autoBuild := true
+	component := testImageComponent("imported-image", &autoBuild)
+	component.Attributes = attributes.Attributes{}.PutString(constants.PluginSourceAttribute, "parent")
+	workspace := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Projects:   []dw.Project{{Name: "test-project"}},
+			Components: []dw.Component{component},
+		},
+	}
+	_, err := GetImageComponents(workspace)
+	assert.EqualError(t, err, "image component imported-image is imported by parent; only image components defined in the DevWorkspace can be built")
+}
+
+func TestCheckBuildContainerAllowed(t *testing.T) {
+	boolTrue := true
+	builder := &kanikoBuilder{}
+	rootContainer, err := builder.GetBuildContainer("test-builder", &BuildSpec{ImageName: "test-image"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	privilegedContainer, err := builder.GetBuildContainer("test-builder", &BuildSpec{ImageName: "test-image", RootRequired: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	config.SetConfigForTesting(nil)
+	assert.Error(t, checkBuildContainerAllowed(rootContainer), "Should not allow root builds by default")
+	assert.NoError(t, checkBuildContainerAllowed(&corev1.Container{}), "Should allow builds that do not run as root")
+
+	config.SetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			ImageBuild: &v1alpha1.ImageBuildConfig{AllowRootBuilds: &boolTrue},
+		},
+	})
+	assert.NoError(t, checkBuildContainerAllowed(rootContainer), "Should allow root builds when enabled")
+	assert.Error(t, checkBuildContainerAllowed(privilegedContainer), "Should not allow privileged builds unless enabled")
+
+	config.SetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			ImageBuild: &v1alpha1.ImageBuildConfig{AllowRootBuilds: &boolTrue, AllowPrivilegedBuilds: &boolTrue},
+		},
+	})
+	assert.NoError(t, checkBuildContainerAllowed(privilegedContainer), "Should allow privileged builds when enabled")
+	config.SetConfigForTesting(nil)
+}
+
+func TestGetImageRepository(t *testing.T) {
+	assert.Equal(t, "quay.io/example/app", getImageRepository("quay.io/example/app:dev"))
+	assert.Equal(t, "registry:5000/app", getImageRepository("registry:5000/app"))
+	assert.Equal(t, "registry:5000/app", getImageRepository("registry:5000/app:v1@sha256:abc"))
+}
+
+func testImageComponent(name string, autoBuild *bool) dw.Component {
+	return dw.Component{
+		Name: name,
+		ComponentUnion: dw.ComponentUnion{
+			Image: &dw.ImageComponent{
+				Image: dw.Image{
+					ImageName: "test-image",
+					ImageUnion: dw.ImageUnion{
+						AutoBuild:  autoBuild,
+						Dockerfile: &dw.DockerfileImage{DockerfileSrc: dw.DockerfileSrc{Uri: "Dockerfile"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDeleteBuildJobs(t *testing.T) {
+	getJob := func(name, workspaceId, componentName string) *batchv1.Job {
+		labels := map[string]string{constants.DevWorkspaceIDLabel: workspaceId}
+		if componentName != "" {
+			labels[constants.DevWorkspaceImageComponentLabel] = componentName
+		}
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns", Labels: labels},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(
+		getJob("build-frontend", "test-id", "frontend"),
+		getJob("build-backend", "test-id", "backend"),
+		getJob("other-workspace-build", "other-id", "frontend"),
+		getJob("other-job", "test-id", ""),
+	).Build()
+	workspace := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "test-ns"},
+		Status:     dw.DevWorkspaceStatus{DevWorkspaceId: "test-id"},
+	}
+
+	err := DeleteBuildJobs(workspace, sync.ClusterAPI{Client: fakeClient, Ctx: context.Background()})
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	jobs := &batchv1.JobList{}
+	if assert.NoError(t, fakeClient.List(context.Background(), jobs)) {
+		var remaining []string
+		for _, job := range jobs.Items {
+			remaining = append(remaining, job.Name)
+		}
+		assert.ElementsMatch(t, []string{"other-workspace-build", "other-job"}, remaining,
+			"Should only delete build jobs for the workspace")
+	}
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagebuild
+
+import (
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const kanikoDefaultImage = "gcr.io/kaniko-project/executor:v1.9.1"
+
+var urlSchemeRegexp = regexp.MustCompile(`^[a-z]+://`)
+
+// kanikoBuilder builds images using the Kaniko executor, which builds images in userspace but must run as root
+// within its container.
+type kanikoBuilder struct{}
+
+var _ Builder = (*kanikoBuilder)(nil)
+
+func (b *kanikoBuilder) DefaultImage() string {
+	return kanikoDefaultImage
+}
+
+func (b *kanikoBuilder) GetBuildContainer(builderImage string, spec *BuildSpec) (*corev1.Container, error) {
+	args := []string{
+		fmt.Sprintf("--dockerfile=%s", spec.Dockerfile),
+		fmt.Sprintf("--destination=%s", spec.ImageName),
+		fmt.Sprintf("--digest-file=%s", digestFilePath),
+	}
+	if spec.Git != nil {
+		// Kaniko clones git contexts over https when using the git:// prefix
+		gitContext := fmt.Sprintf("git://%s", urlSchemeRegexp.ReplaceAllString(spec.Git.URL, ""))
+		if spec.Git.Revision != "" {
+			gitContext = fmt.Sprintf("%s#refs/heads/%s", gitContext, spec.Git.Revision)
+		}
+		args = append(args, fmt.Sprintf("--context=%s", gitContext))
+		if spec.Git.SubPath != "" {
+			args = append(args, fmt.Sprintf("--context-sub-path=%s", spec.Git.SubPath))
+		}
+	} else {
+		args = append(args, fmt.Sprintf("--context=dir://%s", spec.Context))
+	}
+	args = append(args, spec.Args...)
+
+	rootUser := int64(0)
+	runAsNonRoot := false
+	container := &corev1.Container{
+		Image: builderImage,
+		Args:  args,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    &rootUser,
+			RunAsNonRoot: &runAsNonRoot,
+		},
+	}
+	if spec.RootRequired {
+		privileged := true
+		container.SecurityContext.Privileged = &privileged
+	}
+	if spec.AuthPath != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "DOCKER_CONFIG",
+			Value: spec.AuthPath,
+		})
+	}
+	return container, nil
+}
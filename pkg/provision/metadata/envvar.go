@@ -16,9 +16,15 @@
 package metadata
 
 import (
+	"fmt"
 	"path"
+	"regexp"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 )
 
 const (
@@ -30,8 +36,21 @@ const (
 
 	// OriginalDevfileMountPathEnvVar is an environment variable holding the path to the original devworkspace template spec
 	OriginalDevfileMountPathEnvVar = "DEVWORKSPACE_ORIGINAL_DEVFILE"
+
+	// EndpointsMountPathEnvVar is an environment variable holding the path to the exposed endpoints of the workspace
+	EndpointsMountPathEnvVar = "DEVWORKSPACE_ENDPOINTS"
+
+	// StatusMountPathEnvVar is an environment variable holding the path to the current phase and main URL of the workspace
+	StatusMountPathEnvVar = "DEVWORKSPACE_STATUS"
+
+	// endpointURLEnvVarFormat is the format of environment variables holding the URL of an exposed endpoint. The
+	// endpoint name is converted to upper case, with any character that is not valid in an environment variable name
+	// replaced by an underscore.
+	endpointURLEnvVarFormat = "DEVWORKSPACE_ENDPOINT_%s_URL"
 )
 
+var invalidEnvVarCharRegexp = regexp.MustCompile(`[^A-Z0-9_]`)
+
 func getWorkspaceMetaEnvVar() []corev1.EnvVar {
 	return []corev1.EnvVar{
 		{
@@ -46,5 +65,32 @@ func getWorkspaceMetaEnvVar() []corev1.EnvVar {
 			Name:  OriginalDevfileMountPathEnvVar,
 			Value: path.Join(metadataMountPath, originalYamlFilename),
 		},
+		{
+			Name:  EndpointsMountPathEnvVar,
+			Value: path.Join(metadataMountPath, endpointsFilename),
+		},
+		{
+			Name:  StatusMountPathEnvVar,
+			Value: path.Join(metadataMountPath, statusFilename),
+		},
+	}
+}
+
+// getEndpointURLEnvVars returns an environment variable for each exposed endpoint, holding the endpoint's URL.
+// Environment variables are sorted by name to avoid unnecessary updates to the workspace deployment.
+func getEndpointURLEnvVars(exposedEndpoints map[string]v1alpha1.ExposedEndpointList) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, endpoints := range exposedEndpoints {
+		for _, endpoint := range endpoints {
+			envName := invalidEnvVarCharRegexp.ReplaceAllString(strings.ToUpper(endpoint.Name), "_")
+			env = append(env, corev1.EnvVar{
+				Name:  fmt.Sprintf(endpointURLEnvVarFormat, envName),
+				Value: endpoint.Url,
+			})
+		}
 	}
+	sort.Slice(env, func(i, j int) bool {
+		return env[i].Name < env[j].Name
+	})
+	return env
 }
@@ -0,0 +1,218 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metadata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// GetResolvedImports returns the imports recorded in the workspace's DevWorkspaceResolvedImportsAnnotation. Content
+// is filled in for imports whose content is stored in the workspace's pinned imports configmap; content that does not
+// match the recorded digest is ignored. If no imports are recorded, nil is returned.
+func GetResolvedImports(workspace *dw.DevWorkspace, api sync.ClusterAPI) ([]flatten.ResolvedImport, error) {
+	recorded, ok := workspace.Annotations[constants.DevWorkspaceResolvedImportsAnnotation]
+	if !ok || workspace.Status.DevWorkspaceId == "" {
+		return nil, nil
+	}
+	var resolvedImports []flatten.ResolvedImport
+	if err := json.Unmarshal([]byte(recorded), &resolvedImports); err != nil {
+		return nil, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceResolvedImportsAnnotation, err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{
+		Name:      common.PinnedImportsConfigMapName(workspace.Status.DevWorkspaceId),
+		Namespace: workspace.Namespace,
+	}
+	if err := api.Client.Get(api.Ctx, namespacedName, cm); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return resolvedImports, nil
+		}
+		return nil, err
+	}
+	for idx, resolvedImport := range resolvedImports {
+		compressed, ok := cm.BinaryData[digestToKey(resolvedImport.Digest)]
+		if !ok {
+			continue
+		}
+		content, err := decompressContent(compressed, resolvedImport.Digest)
+		if err != nil {
+			api.Logger.Info("Ignoring pinned content for import", "import", resolvedImport.Name, "error", err.Error())
+			continue
+		}
+		resolvedImports[idx].Content = content
+	}
+	return resolvedImports, nil
+}
+
+// SyncResolvedImports records the name, source and digest of resolved imports in the workspace's
+// DevWorkspaceResolvedImportsAnnotation, removing the DevWorkspaceRefreshImportsAnnotation if present. The content
+// of pinned imports is stored, compressed, in a workspace-owned configmap so that it can be reused on subsequent
+// reconciles; if there are no pinned imports, the configmap is deleted.
+func SyncResolvedImports(workspace *dw.DevWorkspace, resolved, pinned []flatten.ResolvedImport, api sync.ClusterAPI) error {
+	if err := syncPinnedImportsConfigMap(workspace, pinned, api); err != nil {
+		return err
+	}
+
+	var annotation string
+	if len(resolved) > 0 {
+		resolvedJson, err := json.Marshal(resolved)
+		if err != nil {
+			return fmt.Errorf("failed to marshal resolved imports: %w", err)
+		}
+		annotation = string(resolvedJson)
+	}
+	current, hasCurrent := workspace.Annotations[constants.DevWorkspaceResolvedImportsAnnotation]
+	_, needsRefresh := workspace.Annotations[constants.DevWorkspaceRefreshImportsAnnotation]
+	if current == annotation && hasCurrent == (annotation != "") && !needsRefresh {
+		return nil
+	}
+
+	patch := client.MergeFrom(workspace.DeepCopy())
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	if annotation != "" {
+		workspace.Annotations[constants.DevWorkspaceResolvedImportsAnnotation] = annotation
+	} else {
+		delete(workspace.Annotations, constants.DevWorkspaceResolvedImportsAnnotation)
+	}
+	delete(workspace.Annotations, constants.DevWorkspaceRefreshImportsAnnotation)
+	if err := api.Client.Patch(api.Ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to record resolved imports for workspace: %w", err)
+	}
+	return nil
+}
+
+func syncPinnedImportsConfigMap(workspace *dw.DevWorkspace, pinned []flatten.ResolvedImport, api sync.ClusterAPI) error {
+	cmName := common.PinnedImportsConfigMapName(workspace.Status.DevWorkspaceId)
+	if len(pinned) == 0 {
+		cm := &corev1.ConfigMap{}
+		err := api.Client.Get(api.Ctx, types.NamespacedName{Name: cmName, Namespace: workspace.Namespace}, cm)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if err := api.Client.Delete(api.Ctx, cm); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	binaryData := map[string][]byte{}
+	for _, pinnedImport := range pinned {
+		if pinnedImport.Content == nil {
+			continue
+		}
+		compressed, err := compressContent(pinnedImport.Content)
+		if err != nil {
+			return err
+		}
+		binaryData[digestToKey(pinnedImport.Digest)] = compressed
+	}
+
+	cmLabels := constants.ControllerAppLabels()
+	cmLabels[constants.DevWorkspaceWatchConfigMapLabel] = "true"
+	cmLabels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cmName,
+			Namespace: workspace.Namespace,
+			Labels:    cmLabels,
+		},
+		BinaryData: binaryData,
+	}
+	if err := controllerutil.SetControllerReference(workspace, cm, api.Scheme); err != nil {
+		return err
+	}
+	_, err := sync.SyncObjectWithCluster(cm, api)
+	switch t := err.(type) {
+	case nil:
+		return nil
+	case *sync.NotInSyncError:
+		return &NotReadyError{Message: "Waiting for DevWorkspace pinned imports configmap to be ready"}
+	case *sync.UnrecoverableSyncError:
+		return &ProvisioningError{
+			Err:     t.Cause,
+			Message: "Failed to sync DevWorkspace pinned imports configmap with cluster",
+		}
+	default:
+		return err
+	}
+}
+
+// digestToKey converts a digest in the format sha256:<hex> into a valid configmap key
+func digestToKey(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+func compressContent(content *dw.DevWorkspaceTemplateSpec) ([]byte, error) {
+	contentYaml, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pinned content: %w", err)
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(contentYaml); err != nil {
+		return nil, fmt.Errorf("failed to compress pinned content: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress pinned content: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressContent(compressed []byte, digest string) (*dw.DevWorkspaceTemplateSpec, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress pinned content: %w", err)
+	}
+	defer reader.Close()
+	contentYaml, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress pinned content: %w", err)
+	}
+	if actual := fmt.Sprintf("sha256:%x", sha256.Sum256(contentYaml)); actual != digest {
+		return nil, fmt.Errorf("pinned content has digest %s, expected %s", actual, digest)
+	}
+	content := &dw.DevWorkspaceTemplateSpec{}
+	if err := yaml.Unmarshal(contentYaml, content); err != nil {
+		return nil, fmt.Errorf("failed to read pinned content: %w", err)
+	}
+	return content, nil
+}
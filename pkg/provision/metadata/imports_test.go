@@ -0,0 +1,182 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metadata
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testNamespace = "test-namespace"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dw.AddToScheme(scheme))
+}
+
+func TestSyncAndGetResolvedImports(t *testing.T) {
+	workspace := getTestWorkspace()
+	workspace.Annotations = map[string]string{constants.DevWorkspaceRefreshImportsAnnotation: "true"}
+	api := getTestClusterAPI(workspace)
+	pinned := getTestImport("pinned-plugin", "https://registry.io/pinned", "pinned-image")
+	unpinned := getTestImport("unpinned-plugin", "https://registry.io/unpinned", "unpinned-image")
+	resolved := []flatten.ResolvedImport{pinned, unpinned}
+
+	// First sync creates the pinned imports configmap
+	err := SyncResolvedImports(workspace, resolved, []flatten.ResolvedImport{pinned}, api)
+	assert.IsType(t, &NotReadyError{}, err, "Should wait for configmap to be created")
+	err = SyncResolvedImports(workspace, resolved, []flatten.ResolvedImport{pinned}, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+
+	clusterWorkspace := &dw.DevWorkspace{}
+	if !assert.NoError(t, api.Client.Get(api.Ctx, types.NamespacedName{Name: workspace.Name, Namespace: testNamespace}, clusterWorkspace)) {
+		return
+	}
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceRefreshImportsAnnotation, "Should remove refresh annotation")
+	assert.NotContains(t, clusterWorkspace.Annotations[constants.DevWorkspaceResolvedImportsAnnotation], "image", "Should only record digests on workspace")
+
+	recorded, err := GetResolvedImports(clusterWorkspace, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	if !assert.Len(t, recorded, 2, "Should return all recorded imports") {
+		return
+	}
+	assert.Equal(t, pinned.Digest, recorded[0].Digest)
+	assert.Equal(t, pinned.Source, recorded[0].Source)
+	if assert.NotNil(t, recorded[0].Content, "Should return content of pinned import") {
+		assert.Equal(t, "pinned-image", recorded[0].Content.Components[0].Container.Image)
+	}
+	assert.Equal(t, unpinned.Digest, recorded[1].Digest)
+	assert.Nil(t, recorded[1].Content, "Should not return content of import that is not pinned")
+
+	// Sync without pinned imports deletes the configmap
+	err = SyncResolvedImports(clusterWorkspace, resolved, nil, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	cm := &corev1.ConfigMap{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{Name: common.PinnedImportsConfigMapName(workspace.Status.DevWorkspaceId), Namespace: testNamespace}, cm)
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete pinned imports configmap")
+}
+
+func TestGetResolvedImportsIgnoresContentWithWrongDigest(t *testing.T) {
+	workspace := getTestWorkspace()
+	api := getTestClusterAPI(workspace)
+	pinned := getTestImport("pinned-plugin", "https://registry.io/pinned", "pinned-image")
+	err := SyncResolvedImports(workspace, []flatten.ResolvedImport{pinned}, nil, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	tampered, err := compressContent(getTestImport("pinned-plugin", "https://registry.io/pinned", "other-image").Content)
+	if !assert.NoError(t, err) {
+		return
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.PinnedImportsConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: testNamespace,
+		},
+		BinaryData: map[string][]byte{digestToKey(pinned.Digest): tampered},
+	}
+	if !assert.NoError(t, api.Client.Create(api.Ctx, cm)) {
+		return
+	}
+
+	recorded, err := GetResolvedImports(workspace, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	if assert.Len(t, recorded, 1) {
+		assert.Nil(t, recorded[0].Content, "Should ignore content that does not match recorded digest")
+	}
+}
+
+func getTestImport(name, source, image string) flatten.ResolvedImport {
+	content := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Components: []dw.Component{
+				{
+					Name: "test-component",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{
+							Container: dw.Container{Image: image},
+						},
+					},
+				},
+			},
+		},
+	}
+	contentYaml, err := yaml.Marshal(content)
+	if err != nil {
+		panic(err)
+	}
+	return flatten.ResolvedImport{
+		Name:    name,
+		Source:  source,
+		Digest:  fmt.Sprintf("sha256:%x", sha256.Sum256(contentYaml)),
+		Content: content,
+	}
+}
+
+func getTestWorkspace() *dw.DevWorkspace {
+	return &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: testNamespace,
+			UID:       "test-uid",
+		},
+		Status: dw.DevWorkspaceStatus{
+			DevWorkspaceId: "test-id",
+		},
+	}
+}
+
+func getTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	return sync.ClusterAPI{
+		Ctx:    context.Background(),
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+		Logger: zap.New(),
+	}
+}
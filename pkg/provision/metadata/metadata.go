@@ -16,6 +16,7 @@
 package metadata
 
 import (
+	"encoding/json"
 	"fmt"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
@@ -28,6 +29,7 @@ import (
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
 )
 
 const (
@@ -38,15 +40,35 @@ const (
 	// resolved plugins and parent) DevWorkspace yaml
 	flattenedYamlFilename = "flattened.devworkspace.yaml"
 
+	// resolvedImportsFilename is the filename mounted to workspace containers which contains the name, source and
+	// digest of the content resolved for imported parents and plugins
+	resolvedImportsFilename = "resolved-imports.yaml"
+
+	// endpointsFilename is the filename mounted to workspace containers which contains the exposed endpoints of the
+	// workspace as JSON
+	endpointsFilename = "endpoints.json"
+
+	// statusFilename is the filename mounted to workspace containers which contains the current phase and main URL
+	// of the workspace as JSON
+	statusFilename = "status.json"
+
 	// metadataMountPath is where files containing workspace metadata are mounted
 	metadataMountPath = "/devworkspace-metadata"
 )
 
+// workspaceStatus is the content of the status file mounted to workspace containers
+type workspaceStatus struct {
+	Phase   dw.DevWorkspacePhase `json:"phase"`
+	MainUrl string               `json:"mainUrl,omitempty"`
+}
+
 // ProvisionWorkspaceMetadata creates a configmap on the cluster that stores metadata about the workspace and configures all
 // workspace containers to mount that configmap at /devworkspace-metadata. Each container has the environment
-// variable DEVWORKSPACE_METADATA set to the mount path for the configmap
-func ProvisionWorkspaceMetadata(podAdditions *v1alpha1.PodAdditions, original, flattened *dw.DevWorkspace, api sync.ClusterAPI) error {
-	cm, err := getSpecMetadataConfigMap(original, flattened)
+// variable DEVWORKSPACE_METADATA set to the mount path for the configmap, and an environment variable containing
+// the URL of each exposed endpoint. The configmap also stores the exposed endpoints, and the current phase and main
+// URL of the workspace; as these are read from the original workspace, the configmap is updated as its status changes.
+func ProvisionWorkspaceMetadata(podAdditions *v1alpha1.PodAdditions, original, flattened *dw.DevWorkspace, resolvedImports []flatten.ResolvedImport, exposedEndpoints map[string]v1alpha1.ExposedEndpointList, api sync.ClusterAPI) error {
+	cm, err := getSpecMetadataConfigMap(original, flattened, resolvedImports, exposedEndpoints)
 	if err != nil {
 		return err
 	}
@@ -72,8 +94,10 @@ func ProvisionWorkspaceMetadata(podAdditions *v1alpha1.PodAdditions, original, f
 	vm := getVolumeMountFromVolume(vol)
 	podAdditions.VolumeMounts = append(podAdditions.VolumeMounts, *vm)
 
+	endpointEnv := getEndpointURLEnvVars(exposedEndpoints)
 	for idx := range podAdditions.Containers {
 		podAdditions.Containers[idx].Env = append(podAdditions.Containers[idx].Env, getWorkspaceMetaEnvVar()...)
+		podAdditions.Containers[idx].Env = append(podAdditions.Containers[idx].Env, endpointEnv...)
 	}
 
 	for idx := range podAdditions.InitContainers {
@@ -83,7 +107,7 @@ func ProvisionWorkspaceMetadata(podAdditions *v1alpha1.PodAdditions, original, f
 	return nil
 }
 
-func getSpecMetadataConfigMap(original, flattened *dw.DevWorkspace) (*corev1.ConfigMap, error) {
+func getSpecMetadataConfigMap(original, flattened *dw.DevWorkspace, resolvedImports []flatten.ResolvedImport, exposedEndpoints map[string]v1alpha1.ExposedEndpointList) (*corev1.ConfigMap, error) {
 	originalYaml, err := yaml.Marshal(original.Spec.Template)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal original DevWorkspace yaml: %w", err)
@@ -109,6 +133,32 @@ func getSpecMetadataConfigMap(original, flattened *dw.DevWorkspace) (*corev1.Con
 		},
 	}
 
+	if len(resolvedImports) > 0 {
+		resolvedImportsYaml, err := yaml.Marshal(resolvedImports)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resolved imports yaml: %w", err)
+		}
+		cm.Data[resolvedImportsFilename] = string(resolvedImportsYaml)
+	}
+
+	if exposedEndpoints == nil {
+		exposedEndpoints = map[string]v1alpha1.ExposedEndpointList{}
+	}
+	endpointsJson, err := json.Marshal(exposedEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal exposed endpoints: %w", err)
+	}
+	cm.Data[endpointsFilename] = string(endpointsJson)
+
+	statusJson, err := json.Marshal(workspaceStatus{
+		Phase:   original.Status.Phase,
+		MainUrl: original.Status.MainUrl,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workspace status: %w", err)
+	}
+	cm.Data[statusFilename] = string(statusJson)
+
 	return cm, nil
 }
 
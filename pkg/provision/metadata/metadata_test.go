@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+)
+
+var testExposedEndpoints = map[string]v1alpha1.ExposedEndpointList{
+	"tools": {
+		{Name: "http-server", Url: "https://test-id-1.example.com/"},
+		{Name: "debug.port", Url: "https://test-id-2.example.com/"},
+	},
+	"web": {
+		{Name: "app", Url: "https://test-id-3.example.com/"},
+	},
+}
+
+func TestMetadataConfigMapContainsEndpointsAndStatus(t *testing.T) {
+	workspace := getTestWorkspace()
+	workspace.Status.Phase = dw.DevWorkspaceStatusRunning
+	workspace.Status.MainUrl = "https://test-id-1.example.com/"
+
+	cm, err := getSpecMetadataConfigMap(workspace, workspace, nil, testExposedEndpoints)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+
+	endpoints := map[string]v1alpha1.ExposedEndpointList{}
+	if assert.NoError(t, json.Unmarshal([]byte(cm.Data[endpointsFilename]), &endpoints), "Endpoints file should be valid JSON") {
+		assert.Equal(t, testExposedEndpoints, endpoints, "Endpoints file should contain exposed endpoints")
+	}
+	status := workspaceStatus{}
+	if assert.NoError(t, json.Unmarshal([]byte(cm.Data[statusFilename]), &status), "Status file should be valid JSON") {
+		assert.Equal(t, dw.DevWorkspaceStatusRunning, status.Phase)
+		assert.Equal(t, "https://test-id-1.example.com/", status.MainUrl)
+	}
+	assert.NotContains(t, cm.Data, resolvedImportsFilename, "Should not add resolved imports file when there are no imports")
+}
+
+func TestMetadataConfigMapWithoutEndpoints(t *testing.T) {
+	workspace := getTestWorkspace()
+	workspace.Status.Phase = dw.DevWorkspaceStatusStarting
+
+	cm, err := getSpecMetadataConfigMap(workspace, workspace, nil, nil)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, "{}", cm.Data[endpointsFilename], "Endpoints file should contain empty object")
+	assert.JSONEq(t, `{"phase": "Starting"}`, cm.Data[statusFilename], "Status file should omit unset main URL")
+}
+
+func TestGetEndpointURLEnvVars(t *testing.T) {
+	env := getEndpointURLEnvVars(testExposedEndpoints)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "DEVWORKSPACE_ENDPOINT_APP_URL", Value: "https://test-id-3.example.com/"},
+		{Name: "DEVWORKSPACE_ENDPOINT_DEBUG_PORT_URL", Value: "https://test-id-2.example.com/"},
+		{Name: "DEVWORKSPACE_ENDPOINT_HTTP_SERVER_URL", Value: "https://test-id-1.example.com/"},
+	}, env, "Should return sorted env vars with valid names")
+
+	assert.Empty(t, getEndpointURLEnvVars(nil), "Should return no env vars when there are no endpoints")
+}
+
+func TestProvisionWorkspaceMetadata(t *testing.T) {
+	workspace := getTestWorkspace()
+	workspace.Status.Phase = dw.DevWorkspaceStatusRunning
+	api := getTestClusterAPI(workspace)
+	podAdditions := &v1alpha1.PodAdditions{
+		Containers:     []corev1.Container{{Name: "tools"}},
+		InitContainers: []corev1.Container{{Name: "project-clone"}},
+	}
+
+	err := ProvisionWorkspaceMetadata(podAdditions, workspace, workspace, nil, testExposedEndpoints, api)
+	assert.IsType(t, &NotReadyError{}, err, "Should wait for configmap to be created")
+	podAdditions = &v1alpha1.PodAdditions{
+		Containers:     []corev1.Container{{Name: "tools"}},
+		InitContainers: []corev1.Container{{Name: "project-clone"}},
+	}
+	err = ProvisionWorkspaceMetadata(podAdditions, workspace, workspace, nil, testExposedEndpoints, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{Name: common.MetadataConfigMapName(workspace.Status.DevWorkspaceId), Namespace: testNamespace}, cm)
+	if assert.NoError(t, err, "Should create metadata configmap") {
+		assert.Contains(t, cm.Data, endpointsFilename)
+		assert.Contains(t, cm.Data, statusFilename)
+	}
+
+	containerEnv := map[string]string{}
+	for _, env := range podAdditions.Containers[0].Env {
+		containerEnv[env.Name] = env.Value
+	}
+	assert.Equal(t, "/devworkspace-metadata/endpoints.json", containerEnv[EndpointsMountPathEnvVar])
+	assert.Equal(t, "/devworkspace-metadata/status.json", containerEnv[StatusMountPathEnvVar])
+	assert.Equal(t, "https://test-id-1.example.com/", containerEnv["DEVWORKSPACE_ENDPOINT_HTTP_SERVER_URL"])
+
+	for _, env := range podAdditions.InitContainers[0].Env {
+		assert.NotRegexp(t, "^DEVWORKSPACE_ENDPOINT_", env.Name, "Should not add endpoint URLs to init containers")
+	}
+}
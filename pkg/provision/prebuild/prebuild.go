@@ -0,0 +1,212 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package prebuild provisions the latest prebuild for a DevWorkspace, allowing projects to be copied from a prebuild
+// instead of being cloned from scratch when the DevWorkspace is first started.
+package prebuild
+
+import (
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	prebuildVolumeName = "devworkspace-prebuild"
+	prebuildMountPath  = "/prebuild"
+)
+
+// ProvisionPrebuildInto mounts a successful prebuild for a DevWorkspaceTemplate imported by the workspace (as a
+// parent or plugin) into the project-clone init container in podAdditions, but only until the workspace has started
+// successfully for the first time. The prebuild used is recorded in the DevWorkspacePrebuildAnnotation on the
+// workspace, and is not changed while the workspace is running, so that completing new prebuilds does not change the
+// workspace's deployment. If the workspace does not import any prebuilt DevWorkspaceTemplates, was set up before
+// any prebuild was available, or podAdditions does not contain a project-clone init container, podAdditions is not
+// modified.
+//
+// Note: the workspace passed in must not be flattened, as imported DevWorkspaceTemplates are determined from
+// the parent and plugins defined in the workspace.
+func ProvisionPrebuildInto(podAdditions *v1alpha1.PodAdditions, workspace *dw.DevWorkspace, api sync.ClusterAPI) error {
+	var cloneContainer *corev1.Container
+	for idx, container := range podAdditions.InitContainers {
+		if container.Name == projects.GetProjectCloneContainerName() {
+			cloneContainer = &podAdditions.InitContainers[idx]
+		}
+	}
+	if cloneContainer == nil {
+		return nil
+	}
+
+	recorded := workspace.Annotations[constants.DevWorkspacePrebuildAnnotation]
+	if recorded == constants.DevWorkspacePrebuildRestored {
+		return nil
+	}
+
+	importedTemplates := getImportedTemplates(workspace)
+	if len(importedTemplates) == 0 {
+		return nil
+	}
+
+	deploymentExists, deploymentRunning, err := getDeploymentState(workspace, api)
+	if err != nil {
+		return err
+	}
+	if recorded == "" && deploymentExists {
+		// Workspace was started before a prebuild was available, so its projects are already set up
+		return nil
+	}
+
+	prebuilds := &v1alpha1.DevWorkspacePrebuildList{}
+	if err := api.Client.List(api.Ctx, prebuilds, client.InNamespace(workspace.Namespace)); err != nil {
+		return fmt.Errorf("failed to list DevWorkspacePrebuilds: %w", err)
+	}
+
+	var prebuild *v1alpha1.DevWorkspacePrebuild
+	var run string
+	if recorded != "" && deploymentRunning {
+		// Keep using the recorded prebuild while the workspace is running, as changing it would restart the workspace
+		prebuild, run = getRecordedPrebuild(recorded, prebuilds.Items, importedTemplates)
+	} else {
+		prebuild = getLatestPrebuild(prebuilds.Items, importedTemplates)
+		if prebuild != nil {
+			run = prebuild.Status.LatestPrebuild
+		}
+	}
+	if prebuild == nil {
+		return nil
+	}
+
+	if value := fmt.Sprintf("%s/%s", prebuild.Name, run); value != recorded {
+		patch := client.MergeFrom(workspace.DeepCopy())
+		if workspace.Annotations == nil {
+			workspace.Annotations = map[string]string{}
+		}
+		workspace.Annotations[constants.DevWorkspacePrebuildAnnotation] = value
+		if err := api.Client.Patch(api.Ctx, workspace, patch); err != nil {
+			return fmt.Errorf("failed to record prebuild used for workspace: %w", err)
+		}
+	}
+
+	podAdditions.Volumes = append(podAdditions.Volumes, corev1.Volume{
+		Name: prebuildVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: prebuild.Status.ClaimName,
+				ReadOnly:  true,
+			},
+		},
+	})
+	cloneContainer.VolumeMounts = append(cloneContainer.VolumeMounts, corev1.VolumeMount{
+		Name:      prebuildVolumeName,
+		MountPath: prebuildMountPath,
+		SubPath:   run,
+		ReadOnly:  true,
+	})
+	cloneContainer.Env = append(cloneContainer.Env, corev1.EnvVar{
+		Name:  constants.ProjectClonePrebuildPath,
+		Value: prebuildMountPath,
+	})
+
+	return nil
+}
+
+// MarkPrebuildRestored updates the DevWorkspacePrebuildAnnotation on a workspace that has started successfully to
+// record that its projects were set up from a prebuild, so that prebuilds are not used on subsequent starts.
+func MarkPrebuildRestored(workspace *dw.DevWorkspace) {
+	if workspace.Annotations[constants.DevWorkspacePrebuildAnnotation] != "" {
+		workspace.Annotations[constants.DevWorkspacePrebuildAnnotation] = constants.DevWorkspacePrebuildRestored
+	}
+}
+
+// getDeploymentState returns whether the workspace's deployment exists on the cluster, and whether it is scaled up.
+func getDeploymentState(workspace *dw.DevWorkspace, api sync.ClusterAPI) (exists, running bool, err error) {
+	deployment := &appsv1.Deployment{}
+	namespacedName := types.NamespacedName{
+		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
+		Namespace: workspace.Namespace,
+	}
+	if err := api.Client.Get(api.Ctx, namespacedName, deployment); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, deployment.Spec.Replicas == nil || *deployment.Spec.Replicas > 0, nil
+}
+
+// getLatestPrebuild returns the DevWorkspacePrebuild for an imported template with the most recent successful
+// prebuild, or nil if no prebuild has completed.
+func getLatestPrebuild(prebuilds []v1alpha1.DevWorkspacePrebuild, importedTemplates map[string]bool) *v1alpha1.DevWorkspacePrebuild {
+	var latestPrebuild *v1alpha1.DevWorkspacePrebuild
+	for idx, prebuild := range prebuilds {
+		if !importedTemplates[prebuild.Spec.Template] || prebuild.Status.LatestPrebuild == "" || prebuild.Status.ClaimName == "" {
+			continue
+		}
+		if latestPrebuild == nil || latestPrebuild.Status.LastCompletionTime.Before(prebuild.Status.LastCompletionTime) {
+			latestPrebuild = &prebuilds[idx]
+		}
+	}
+	return latestPrebuild
+}
+
+// getRecordedPrebuild returns the DevWorkspacePrebuild and prebuild run recorded in the DevWorkspacePrebuildAnnotation
+// value recorded. Returns nil if the recorded DevWorkspacePrebuild no longer exists or is not for an imported template.
+func getRecordedPrebuild(recorded string, prebuilds []v1alpha1.DevWorkspacePrebuild, importedTemplates map[string]bool) (*v1alpha1.DevWorkspacePrebuild, string) {
+	parts := strings.SplitN(recorded, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, ""
+	}
+	for idx, prebuild := range prebuilds {
+		if prebuild.Name == parts[0] && importedTemplates[prebuild.Spec.Template] && prebuild.Status.ClaimName != "" {
+			return &prebuilds[idx], parts[1]
+		}
+	}
+	return nil, ""
+}
+
+// getImportedTemplates returns the set of names of DevWorkspaceTemplates in the workspace's namespace that
+// are referenced by the workspace's parent or plugins.
+func getImportedTemplates(workspace *dw.DevWorkspace) map[string]bool {
+	importedTemplates := map[string]bool{}
+	addRef := func(ref *dw.KubernetesCustomResourceImportReference) {
+		if ref == nil {
+			return
+		}
+		if ref.Namespace == "" || ref.Namespace == workspace.Namespace {
+			importedTemplates[ref.Name] = true
+		}
+	}
+	if workspace.Spec.Template.Parent != nil {
+		addRef(workspace.Spec.Template.Parent.Kubernetes)
+	}
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Plugin != nil {
+			addRef(component.Plugin.Kubernetes)
+		}
+	}
+	return importedTemplates
+}
@@ -0,0 +1,197 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package prebuild
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testNamespace = "test-namespace"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dw.AddToScheme(scheme))
+}
+
+func TestProvisionPrebuildInto(t *testing.T) {
+	oldRun := "prebuild-old"
+	newRun := "prebuild-new"
+	tests := []struct {
+		name               string
+		annotation         string
+		deploymentReplicas *int32
+		expectedRun        string
+		expectedAnnotation string
+	}{
+		{
+			name:               "Uses latest prebuild on first start",
+			expectedRun:        newRun,
+			expectedAnnotation: "test-prebuild/" + newRun,
+		},
+		{
+			name:               "Does not use prebuild once projects are restored",
+			annotation:         constants.DevWorkspacePrebuildRestored,
+			expectedAnnotation: constants.DevWorkspacePrebuildRestored,
+		},
+		{
+			name:               "Does not use prebuild for workspaces set up before prebuild was available",
+			deploymentReplicas: int32Ptr(0),
+		},
+		{
+			name:               "Keeps recorded prebuild while workspace is running",
+			annotation:         "test-prebuild/" + oldRun,
+			deploymentReplicas: int32Ptr(1),
+			expectedRun:        oldRun,
+			expectedAnnotation: "test-prebuild/" + oldRun,
+		},
+		{
+			name:               "Uses latest prebuild when restarting a workspace that did not start successfully",
+			annotation:         "test-prebuild/" + oldRun,
+			deploymentReplicas: int32Ptr(0),
+			expectedRun:        newRun,
+			expectedAnnotation: "test-prebuild/" + newRun,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getTestWorkspace()
+			if tt.annotation != "" {
+				workspace.Annotations = map[string]string{constants.DevWorkspacePrebuildAnnotation: tt.annotation}
+			}
+			objs := []client.Object{workspace, getTestPrebuild(newRun)}
+			if tt.deploymentReplicas != nil {
+				objs = append(objs, &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
+						Namespace: testNamespace,
+					},
+					Spec: appsv1.DeploymentSpec{Replicas: tt.deploymentReplicas},
+				})
+			}
+			api := sync.ClusterAPI{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+				Scheme: scheme,
+				Logger: zap.New(),
+				Ctx:    context.Background(),
+			}
+			podAdditions := &v1alpha1.PodAdditions{
+				InitContainers: []corev1.Container{{Name: projects.GetProjectCloneContainerName()}},
+			}
+
+			err := ProvisionPrebuildInto(podAdditions, workspace, api)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			cloneContainer := podAdditions.InitContainers[0]
+			if tt.expectedRun == "" {
+				assert.Empty(t, podAdditions.Volumes, "Prebuild should not be mounted")
+				assert.Empty(t, cloneContainer.VolumeMounts, "Prebuild should not be mounted")
+			} else if assert.Len(t, cloneContainer.VolumeMounts, 1, "Prebuild should be mounted") {
+				assert.Equal(t, tt.expectedRun, cloneContainer.VolumeMounts[0].SubPath)
+				assert.Equal(t, "test-prebuild-claim", podAdditions.Volumes[0].PersistentVolumeClaim.ClaimName)
+			}
+
+			clusterWorkspace := &dw.DevWorkspace{}
+			err = api.Client.Get(api.Ctx, types.NamespacedName{Name: workspace.Name, Namespace: testNamespace}, clusterWorkspace)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expectedAnnotation, clusterWorkspace.Annotations[constants.DevWorkspacePrebuildAnnotation])
+			}
+		})
+	}
+}
+
+func TestMarkPrebuildRestored(t *testing.T) {
+	workspace := getTestWorkspace()
+	workspace.Annotations = map[string]string{}
+	MarkPrebuildRestored(workspace)
+	assert.NotContains(t, workspace.Annotations, constants.DevWorkspacePrebuildAnnotation,
+		"Should not mark workspace that did not use a prebuild")
+
+	workspace.Annotations[constants.DevWorkspacePrebuildAnnotation] = "test-prebuild/prebuild-run"
+	MarkPrebuildRestored(workspace)
+	assert.Equal(t, constants.DevWorkspacePrebuildRestored, workspace.Annotations[constants.DevWorkspacePrebuildAnnotation])
+}
+
+func getTestWorkspace() *dw.DevWorkspace {
+	return &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: testNamespace,
+		},
+		Spec: dw.DevWorkspaceSpec{
+			Template: dw.DevWorkspaceTemplateSpec{
+				Parent: &dw.Parent{
+					ImportReference: dw.ImportReference{
+						ImportReferenceUnion: dw.ImportReferenceUnion{
+							Kubernetes: &dw.KubernetesCustomResourceImportReference{
+								Name: "test-template",
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: dw.DevWorkspaceStatus{
+			DevWorkspaceId: "test-workspace-id",
+		},
+	}
+}
+
+func getTestPrebuild(latestRun string) *v1alpha1.DevWorkspacePrebuild {
+	return &v1alpha1.DevWorkspacePrebuild{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-prebuild",
+			Namespace: testNamespace,
+		},
+		Spec: v1alpha1.DevWorkspacePrebuildSpec{
+			Template: "test-template",
+		},
+		Status: v1alpha1.DevWorkspacePrebuildStatus{
+			ClaimName:          "test-prebuild-claim",
+			LatestPrebuild:     latestRun,
+			LastCompletionTime: &metav1.Time{Time: time.Now()},
+		},
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
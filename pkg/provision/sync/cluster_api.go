@@ -20,15 +20,19 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ClusterAPI struct {
 	Client           crclient.Client
 	NonCachingClient crclient.Client
-	Scheme           *runtime.Scheme
-	Logger           logr.Logger
-	Ctx              context.Context
+	// Clientset is used for requests that are not supported by controller-runtime clients, such as reading
+	// pod logs. May be nil.
+	Clientset kubernetes.Interface
+	Scheme    *runtime.Scheme
+	Logger    logr.Logger
+	Ctx       context.Context
 }
 
 // NotInSyncError is returned when a spec object is out-of-sync with its cluster counterpart
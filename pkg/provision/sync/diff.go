@@ -43,6 +43,7 @@ var diffFuncs = map[reflect.Type]diffFunc{
 	reflect.TypeOf(corev1.Secret{}):                allDiffFuncs(labelsAndAnnotationsDiffFunc, basicDiffFunc(secretDiffOpts)),
 	reflect.TypeOf(v1alpha1.DevWorkspaceRouting{}): allDiffFuncs(routingDiffFunc, labelsAndAnnotationsDiffFunc, basicDiffFunc(routingDiffOpts)),
 	reflect.TypeOf(batchv1.Job{}):                  allDiffFuncs(labelsAndAnnotationsDiffFunc, jobDiffFunc),
+	reflect.TypeOf(batchv1.CronJob{}):              allDiffFuncs(labelsAndAnnotationsDiffFunc, cronJobDiffFunc),
 	reflect.TypeOf(corev1.Service{}):               allDiffFuncs(labelsAndAnnotationsDiffFunc, serviceDiffFunc),
 	reflect.TypeOf(networkingv1.Ingress{}):         allDiffFuncs(labelsAndAnnotationsDiffFunc, basicDiffFunc(ingressDiffOpts)),
 	reflect.TypeOf(routev1.Route{}):                allDiffFuncs(labelsAndAnnotationsDiffFunc, basicDiffFunc(routeDiffOpts)),
@@ -113,6 +114,12 @@ func jobDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
 	return !equality.Semantic.DeepDerivative(specJob.Spec, clusterJob.Spec), false
 }
 
+func cronJobDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
+	specCronJob := spec.(*batchv1.CronJob)
+	clusterCronJob := cluster.(*batchv1.CronJob)
+	return false, !equality.Semantic.DeepDerivative(specCronJob.Spec, clusterCronJob.Spec)
+}
+
 func serviceDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
 	specService := spec.(*corev1.Service)
 	clusterService := cluster.(*corev1.Service)
@@ -0,0 +1,229 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// ContainerStatusFilename is the key in the container status configmap that stores the status of each container in a
+// workspace as JSON, keyed by container (i.e. component) name.
+const ContainerStatusFilename = "containers.json"
+
+// ContainerState is the state of a container in a workspace pod
+type ContainerState string
+
+const (
+	ContainerStateWaiting    ContainerState = "waiting"
+	ContainerStateRunning    ContainerState = "running"
+	ContainerStateTerminated ContainerState = "terminated"
+)
+
+// ContainerStatus is the status of a container in a workspace pod, as published in the container status configmap
+type ContainerStatus struct {
+	// Image is the image the container is running
+	Image string `json:"image"`
+	// InitContainer is true if the container is an init container
+	InitContainer bool `json:"initContainer,omitempty"`
+	// Ready is whether the container is ready. For init containers, this is true once the container completed
+	Ready bool `json:"ready"`
+	// State is the current state of the container
+	State ContainerState `json:"state"`
+	// WaitingReason is the reason the container is waiting, if it is in the waiting state
+	WaitingReason string `json:"waitingReason,omitempty"`
+	// TerminationReason is the reason the container terminated, if it is in the terminated state
+	TerminationReason string `json:"terminationReason,omitempty"`
+	// LastTerminationReason is the reason the previous instance of the container terminated, if it has restarted
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+	// RestartCount is the number of times the container has restarted
+	RestartCount int32 `json:"restartCount"`
+}
+
+// GetContainerStatuses returns the status of each init container and container in the pods of a workspace, including
+// the workspace's dedicated pods, keyed by container name. If no pods exist for the workspace, nil is returned.
+func GetContainerStatuses(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) (map[string]ContainerStatus, error) {
+	podList, err := getPods(workspace, clusterAPI.Client)
+	if err != nil {
+		return nil, err
+	}
+	if len(podList.Items) == 0 {
+		return nil, nil
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	statuses := map[string]ContainerStatus{}
+	for _, pod := range pods {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if _, ok := statuses[status.Name]; !ok {
+				statuses[status.Name] = getContainerStatus(status, true)
+			}
+		}
+		for _, container := range pod.Spec.Containers {
+			// Containers are reported as waiting until the kubelet reports their status
+			if _, ok := statuses[container.Name]; !ok {
+				statuses[container.Name] = ContainerStatus{Image: container.Image, State: ContainerStateWaiting}
+			}
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			statuses[status.Name] = getContainerStatus(status, false)
+		}
+	}
+	return statuses, nil
+}
+
+// FormatContainersReady returns whether all (non-init) containers in statuses are ready, along with a message listing
+// the containers that are not ready, sorted by name, with the reason each container is not ready and its restarts.
+func FormatContainersReady(workspace *dw.DevWorkspace, statuses map[string]ContainerStatus) (allReady bool, message string) {
+	var names []string
+	for name, status := range statuses {
+		if !status.InitContainer && !status.Ready {
+			names = append(names, name)
+		}
+	}
+	cmName := common.ContainerStatusConfigMapName(workspace.Status.DevWorkspaceId)
+	if len(names) == 0 {
+		return true, fmt.Sprintf("All containers are ready; container status is stored in configmap %s", cmName)
+	}
+	sort.Strings(names)
+	var notReady []string
+	for _, name := range names {
+		notReady = append(notReady, formatContainerStatus(name, statuses[name]))
+	}
+	return false, fmt.Sprintf("Containers not ready: %s; container status is stored in configmap %s", strings.Join(notReady, ", "), cmName)
+}
+
+// formatContainerStatus returns a short description of a container's status, e.g.
+// "tools: CrashLoopBackOff (last terminated: OOMKilled, 4 restarts)". If the container's current state has no reason,
+// the state itself is used.
+func formatContainerStatus(name string, status ContainerStatus) string {
+	reason := string(status.State)
+	switch {
+	case status.State == ContainerStateWaiting && status.WaitingReason != "":
+		reason = status.WaitingReason
+	case status.State == ContainerStateTerminated && status.TerminationReason != "":
+		reason = status.TerminationReason
+	}
+	var details []string
+	if status.LastTerminationReason != "" {
+		details = append(details, fmt.Sprintf("last terminated: %s", status.LastTerminationReason))
+	}
+	switch {
+	case status.RestartCount == 1:
+		details = append(details, "1 restart")
+	case status.RestartCount > 1:
+		details = append(details, fmt.Sprintf("%d restarts", status.RestartCount))
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("%s: %s", name, reason)
+	}
+	return fmt.Sprintf("%s: %s (%s)", name, reason, strings.Join(details, ", "))
+}
+
+// SyncContainerStatusToCluster stores statuses as JSON in a workspace-owned configmap, so that the state of the
+// workspace's containers can be read by tools without access to its pods. The configmap is kept when the workspace
+// is stopped, in order to preserve the state of containers at the time of a failure.
+func SyncContainerStatusToCluster(workspace *dw.DevWorkspace, statuses map[string]ContainerStatus, clusterAPI sync.ClusterAPI) error {
+	statusJson, err := json.Marshal(statuses)
+	if err != nil {
+		return fmt.Errorf("failed to marshal container status: %w", err)
+	}
+	cmLabels := constants.ControllerAppLabels()
+	cmLabels[constants.DevWorkspaceWatchConfigMapLabel] = "true"
+	cmLabels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ContainerStatusConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    cmLabels,
+		},
+		Data: map[string]string{
+			ContainerStatusFilename: string(statusJson),
+		},
+	}
+	if err := controllerutil.SetControllerReference(workspace, cm, clusterAPI.Scheme); err != nil {
+		return err
+	}
+	_, err = sync.SyncObjectWithCluster(cm, clusterAPI)
+	switch t := err.(type) {
+	case nil:
+		return nil
+	case *sync.NotInSyncError:
+		if t.Reason != sync.CreatedObjectReason && t.Reason != sync.UpdatedObjectReason {
+			return err
+		}
+		return nil
+	case *sync.UnrecoverableSyncError:
+		return t.Cause
+	default:
+		return err
+	}
+}
+
+// DeleteContainerStatus deletes the configmap storing the status of a workspace's containers, if it exists.
+func DeleteContainerStatus(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ContainerStatusConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+		},
+	}
+	if err := clusterAPI.Client.Delete(clusterAPI.Ctx, cm); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func getContainerStatus(status corev1.ContainerStatus, isInit bool) ContainerStatus {
+	containerStatus := ContainerStatus{
+		Image:         status.Image,
+		InitContainer: isInit,
+		Ready:         status.Ready,
+		RestartCount:  status.RestartCount,
+	}
+	switch {
+	case status.State.Waiting != nil:
+		containerStatus.State = ContainerStateWaiting
+		containerStatus.WaitingReason = status.State.Waiting.Reason
+	case status.State.Terminated != nil:
+		containerStatus.State = ContainerStateTerminated
+		containerStatus.TerminationReason = status.State.Terminated.Reason
+		if isInit {
+			containerStatus.Ready = status.State.Terminated.ExitCode == 0
+		}
+	default:
+		containerStatus.State = ContainerStateRunning
+	}
+	if lastTerminated := status.LastTerminationState.Terminated; lastTerminated != nil {
+		containerStatus.LastTerminationReason = lastTerminated.Reason
+	}
+	return containerStatus
+}
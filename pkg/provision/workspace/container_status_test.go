@@ -0,0 +1,207 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"encoding/json"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetContainerStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   corev1.ContainerStatus
+		isInit   bool
+		expected ContainerStatus
+	}{
+		{
+			name: "Ready container",
+			status: corev1.ContainerStatus{
+				Name:  "tools",
+				Image: "test-image",
+				Ready: true,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			},
+			expected: ContainerStatus{Image: "test-image", Ready: true, State: ContainerStateRunning},
+		},
+		{
+			name: "Crashlooping container",
+			status: corev1.ContainerStatus{
+				Name:                 "tools",
+				Image:                "test-image",
+				RestartCount:         3,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+			},
+			expected: ContainerStatus{
+				Image:                 "test-image",
+				State:                 ContainerStateWaiting,
+				WaitingReason:         "CrashLoopBackOff",
+				LastTerminationReason: "OOMKilled",
+				RestartCount:          3,
+			},
+		},
+		{
+			name: "Completed init container",
+			status: corev1.ContainerStatus{
+				Name:  "project-clone",
+				Image: "test-image",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+			},
+			isInit: true,
+			expected: ContainerStatus{
+				Image:             "test-image",
+				InitContainer:     true,
+				Ready:             true,
+				State:             ContainerStateTerminated,
+				TerminationReason: "Completed",
+			},
+		},
+		{
+			name: "Failed init container",
+			status: corev1.ContainerStatus{
+				Name:  "project-clone",
+				Image: "test-image",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			},
+			isInit: true,
+			expected: ContainerStatus{
+				Image:             "test-image",
+				InitContainer:     true,
+				State:             ContainerStateTerminated,
+				TerminationReason: "Error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, getContainerStatus(tt.status, tt.isInit))
+		})
+	}
+}
+
+func TestSyncContainerStatusToCluster(t *testing.T) {
+	workspace := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: testNamespace,
+			UID:       "test-uid",
+		},
+		Status: dw.DevWorkspaceStatus{DevWorkspaceId: "test-id"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-id-abc",
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: "test-id"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "tools", Image: "tools-image"},
+				{Name: "web", Image: "web-image"},
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "project-clone",
+					Image: "clone-image",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "tools",
+					Image: "tools-image",
+					Ready: true,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	api := getTestClusterAPI(workspace, pod)
+
+	statuses, err := GetContainerStatuses(workspace, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, map[string]ContainerStatus{
+		"project-clone": {Image: "clone-image", InitContainer: true, Ready: true, State: ContainerStateTerminated, TerminationReason: "Completed"},
+		"tools":         {Image: "tools-image", Ready: true, State: ContainerStateRunning},
+		"web":           {Image: "web-image", State: ContainerStateWaiting},
+	}, statuses, "Should return status of each container keyed by name")
+
+	ready, message := FormatContainersReady(workspace, statuses)
+	assert.False(t, ready, "Should not be ready when a container is not ready")
+	assert.Equal(t, "Containers not ready: web: waiting; container status is stored in configmap test-id-container-status", message)
+
+	if !assert.NoError(t, SyncContainerStatusToCluster(workspace, statuses, api), "Should not return error") {
+		return
+	}
+	cm := &corev1.ConfigMap{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{Name: common.ContainerStatusConfigMapName("test-id"), Namespace: testNamespace}, cm)
+	if !assert.NoError(t, err, "Should create container status configmap") {
+		return
+	}
+	stored := map[string]ContainerStatus{}
+	if assert.NoError(t, json.Unmarshal([]byte(cm.Data[ContainerStatusFilename]), &stored), "Should store status as JSON") {
+		assert.Equal(t, statuses, stored)
+	}
+
+	if assert.NoError(t, DeleteContainerStatus(workspace, api), "Should not return error") {
+		err = api.Client.Get(api.Ctx, types.NamespacedName{Name: cm.Name, Namespace: testNamespace}, &corev1.ConfigMap{})
+		assert.Error(t, err, "Should delete container status configmap")
+	}
+}
+
+func TestFormatContainersReady(t *testing.T) {
+	workspace := &dw.DevWorkspace{Status: dw.DevWorkspaceStatus{DevWorkspaceId: "test-id"}}
+	ready, message := FormatContainersReady(workspace, map[string]ContainerStatus{
+		"project-clone": {InitContainer: true, State: ContainerStateRunning},
+		"tools":         {Ready: true, State: ContainerStateRunning, RestartCount: 2},
+	})
+	assert.True(t, ready, "Should ignore init containers")
+	assert.Equal(t, "All containers are ready; container status is stored in configmap test-id-container-status", message)
+}
+
+func TestFormatContainersReadyListsReasons(t *testing.T) {
+	workspace := &dw.DevWorkspace{Status: dw.DevWorkspaceStatus{DevWorkspaceId: "test-id"}}
+	ready, message := FormatContainersReady(workspace, map[string]ContainerStatus{
+		"tools": {
+			State:                 ContainerStateWaiting,
+			WaitingReason:         "CrashLoopBackOff",
+			LastTerminationReason: "OOMKilled",
+			RestartCount:          4,
+		},
+		"web":           {State: ContainerStateWaiting, WaitingReason: "ImagePullBackOff"},
+		"db":            {State: ContainerStateRunning, RestartCount: 1},
+		"cache":         {State: ContainerStateTerminated, TerminationReason: "Error"},
+		"project-clone": {InitContainer: true, State: ContainerStateRunning},
+	})
+	assert.False(t, ready, "Should not be ready when a container is not ready")
+	assert.Equal(t, "Containers not ready: cache: Error, db: running (1 restart), "+
+		"tools: CrashLoopBackOff (last terminated: OOMKilled, 4 restarts), web: ImagePullBackOff; "+
+		"container status is stored in configmap test-id-container-status", message)
+}
@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	containerlib "github.com/devfile/devworkspace-operator/pkg/library/container"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// hasDedicatedPods returns whether any container component in a workspace has dedicatedPod set
+func hasDedicatedPods(workspace *dw.DevWorkspace) bool {
+	for _, component := range workspace.Spec.Template.Components {
+		if containerlib.IsDedicatedPod(&component) {
+			return true
+		}
+	}
+	return false
+}
+
+// syncDedicatedPodsToCluster syncs a deployment and service to the cluster for each container component with
+// dedicatedPod set, and removes deployments and services for components that no longer require a dedicated pod.
+// Each element of dedicatedPodAdditions is expected to contain a single container. Returns a status with Continue
+// set if all dedicated pod deployments are ready.
+func syncDedicatedPodsToCluster(
+	workspace *dw.DevWorkspace,
+	dedicatedPodAdditions []v1alpha1.PodAdditions,
+	saName string,
+	podTolerations []corev1.Toleration,
+	nodeSelector map[string]string,
+	affinity *corev1.Affinity,
+	topologySpreadConstraints []corev1.TopologySpreadConstraint,
+	clusterAPI sync.ClusterAPI) DeploymentProvisioningStatus {
+
+	var specObjects []k8sclient.Object
+	for _, podAdditions := range dedicatedPodAdditions {
+		if len(podAdditions.Containers) == 0 {
+			continue
+		}
+		componentName := podAdditions.Containers[0].Name
+		deployment, err := getSpecDeployment(workspace, []v1alpha1.PodAdditions{podAdditions}, saName, podTolerations, nodeSelector, affinity, topologySpreadConstraints, clusterAPI.Scheme)
+		if err != nil {
+			return DeploymentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{
+					Err:         fmt.Errorf("failed to create deployment for component %s: %w", componentName, err),
+					FailStartup: true,
+				},
+			}
+		}
+		setDedicatedPodMetadata(workspace, componentName, deployment)
+		if mountsPersistentVolumeClaim(&deployment.Spec.Template.Spec) {
+			deployment.Spec.Template.Spec.Affinity = withWorkspacePodAffinity(workspace, deployment.Spec.Template.Spec.Affinity)
+		}
+		specObjects = append(specObjects, deployment)
+
+		service, err := getSpecDedicatedPodService(workspace, componentName, podAdditions.Containers[0].Ports, clusterAPI)
+		if err != nil {
+			return DeploymentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{
+					Err:         fmt.Errorf("failed to create service for component %s: %w", componentName, err),
+					FailStartup: true,
+				},
+			}
+		}
+		if service != nil {
+			specObjects = append(specObjects, service)
+		}
+	}
+
+	if err := deleteStaleDedicatedPodObjects(workspace, specObjects, clusterAPI); err != nil {
+		return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+
+	var notReady []string
+	for _, specObj := range specObjects {
+		clusterObj, err := sync.SyncObjectWithCluster(specObj, clusterAPI)
+		switch t := err.(type) {
+		case nil:
+			break
+		case *sync.NotInSyncError:
+			return DeploymentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{Requeue: true},
+			}
+		case *sync.UnrecoverableSyncError:
+			return DeploymentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{FailStartup: true, Err: t.Cause},
+			}
+		default:
+			return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+		}
+		clusterDeployment, ok := clusterObj.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		if healthy, errMsg := checkDeploymentConditions(clusterDeployment); !healthy {
+			return DeploymentProvisioningStatus{
+				ProvisioningStatus: ProvisioningStatus{
+					FailStartup: true,
+					Message:     errMsg,
+				},
+			}
+		}
+		if !checkDeploymentStatus(clusterDeployment) {
+			notReady = append(notReady, clusterDeployment.Labels[constants.DevWorkspaceDedicatedPodLabel])
+		}
+	}
+
+	if len(notReady) > 0 {
+		return DeploymentProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
+				Message: fmt.Sprintf("Waiting for dedicated pods for components: %v", notReady),
+			},
+		}
+	}
+	return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Continue: true}}
+}
+
+// setDedicatedPodMetadata updates the name, labels, and selector of a deployment created via getSpecDeployment so
+// that it can be used as the dedicated pod deployment for a component.
+func setDedicatedPodMetadata(workspace *dw.DevWorkspace, componentName string, deployment *appsv1.Deployment) {
+	name := common.DedicatedPodName(workspace.Status.DevWorkspaceId, componentName)
+	deployment.Name = name
+	deployment.Spec.Template.Name = name
+	deployment.Labels[constants.DevWorkspaceDedicatedPodLabel] = componentName
+	deployment.Spec.Template.Labels[constants.DevWorkspaceDedicatedPodLabel] = componentName
+	deployment.Spec.Selector.MatchLabels[constants.DevWorkspaceDedicatedPodLabel] = componentName
+}
+
+// withWorkspacePodAffinity returns a copy of affinity that additionally requires a pod to be scheduled on the same
+// node as the other pods in the workspace. This allows the main workspace pod and dedicated pods to share
+// ReadWriteOnce persistent volumes. As the term also matches the pod it is applied to, the first pod of the workspace
+// can be scheduled on any node.
+func withWorkspacePodAffinity(workspace *dw.DevWorkspace, affinity *corev1.Affinity) *corev1.Affinity {
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	} else {
+		affinity = affinity.DeepCopy()
+	}
+	if affinity.PodAffinity == nil {
+		affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+		affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
+				},
+			},
+			TopologyKey: corev1.LabelHostname,
+		})
+	return affinity
+}
+
+// mountsPersistentVolumeClaim returns whether a pod spec mounts any persistent volume claim
+func mountsPersistentVolumeClaim(podSpec *corev1.PodSpec) bool {
+	for _, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getSpecDedicatedPodService returns a service exposing the ports of a dedicated pod container. Returns nil if the
+// container does not expose any ports.
+func getSpecDedicatedPodService(workspace *dw.DevWorkspace, componentName string, ports []corev1.ContainerPort, clusterAPI sync.ClusterAPI) (*corev1.Service, error) {
+	if len(ports) == 0 {
+		return nil, nil
+	}
+	var components []dw.Component
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Name == componentName {
+			components = append(components, component)
+		}
+	}
+	annotations, err := getServiceAnnotations(components)
+	if err != nil {
+		return nil, err
+	}
+	var servicePorts []corev1.ServicePort
+	for _, port := range ports {
+		servicePorts = append(servicePorts, corev1.ServicePort{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.ContainerPort,
+			TargetPort: intstr.FromInt(int(port.ContainerPort)),
+		})
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.DedicatedPodName(workspace.Status.DevWorkspaceId, componentName),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceDedicatedPodLabel: componentName,
+			},
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceDedicatedPodLabel: componentName,
+			},
+			Type:  corev1.ServiceTypeClusterIP,
+			Ports: servicePorts,
+		},
+	}
+	if err := controllerutil.SetControllerReference(workspace, service, clusterAPI.Scheme); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// deleteStaleDedicatedPodObjects deletes dedicated pod deployments and services for a workspace that are not in
+// specObjects, e.g. because a component was removed or no longer has dedicatedPod set.
+func deleteStaleDedicatedPodObjects(workspace *dw.DevWorkspace, specObjects []k8sclient.Object, clusterAPI sync.ClusterAPI) error {
+	expected := map[string]bool{}
+	for _, obj := range specObjects {
+		expected[fmt.Sprintf("%T/%s", obj, obj.GetName())] = true
+	}
+
+	deployments, err := getDedicatedPodDeployments(workspace, clusterAPI.Client)
+	if err != nil {
+		return err
+	}
+	var toDelete []k8sclient.Object
+	for idx := range deployments.Items {
+		toDelete = append(toDelete, &deployments.Items[idx])
+	}
+	listOptions, err := dedicatedPodListOptions(workspace)
+	if err != nil {
+		return err
+	}
+	services := &corev1.ServiceList{}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, services, listOptions...); err != nil {
+		return err
+	}
+	for idx := range services.Items {
+		toDelete = append(toDelete, &services.Items[idx])
+	}
+
+	for _, obj := range toDelete {
+		if expected[fmt.Sprintf("%T/%s", obj, obj.GetName())] || !metav1.IsControlledBy(obj, workspace) {
+			continue
+		}
+		if err := clusterAPI.Client.Delete(clusterAPI.Ctx, obj); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaleDedicatedPodDeploymentsToZero scales all dedicated pod deployments for a workspace to zero replicas
+func scaleDedicatedPodDeploymentsToZero(ctx context.Context, workspace *dw.DevWorkspace, client k8sclient.Client) error {
+	deployments, err := getDedicatedPodDeployments(workspace, client)
+	if err != nil {
+		return err
+	}
+	patch := []byte(`{"spec":{"replicas": 0}}`)
+	for idx := range deployments.Items {
+		err := client.Patch(ctx, &deployments.Items[idx], k8sclient.RawPatch(types.StrategicMergePatchType, patch))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func getDedicatedPodDeployments(workspace *dw.DevWorkspace, client k8sclient.Client) (*appsv1.DeploymentList, error) {
+	listOptions, err := dedicatedPodListOptions(workspace)
+	if err != nil {
+		return nil, err
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := client.List(context.TODO(), deployments, listOptions...); err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// dedicatedPodListOptions returns list options that select the dedicated pod objects of a workspace. The workspace ID
+// and dedicated pod label requirements are combined in a single selector, as MatchingLabels and HasLabels each
+// replace the label selector of the list options.
+func dedicatedPodListOptions(workspace *dw.DevWorkspace) ([]k8sclient.ListOption, error) {
+	workspaceIDRequirement, err := labels.NewRequirement(constants.DevWorkspaceIDLabel, selection.Equals, []string{workspace.Status.DevWorkspaceId})
+	if err != nil {
+		return nil, err
+	}
+	dedicatedPodRequirement, err := labels.NewRequirement(constants.DevWorkspaceDedicatedPodLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []k8sclient.ListOption{
+		k8sclient.InNamespace(workspace.Namespace),
+		k8sclient.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*workspaceIDRequirement, *dedicatedPodRequirement)},
+	}, nil
+}
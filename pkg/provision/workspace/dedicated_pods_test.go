@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func TestSyncDedicatedPodsToCluster(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetConfigForTesting(nil)
+	workspace := getDedicatedPodTestWorkspace("db")
+	api := getTestClusterAPI(workspace)
+	podAdditions := []v1alpha1.PodAdditions{getDedicatedPodTestPodAdditions("db")}
+	name := common.DedicatedPodName(workspace.Status.DevWorkspaceId, "db")
+
+	status := syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	assert.True(t, status.Requeue, "Should requeue after creating objects")
+	status = syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	assert.True(t, status.Requeue, "Should requeue after creating objects")
+	status = syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	if !assert.NoError(t, status.Err, "Should not return error") {
+		return
+	}
+	assert.False(t, status.Continue, "Should wait for dedicated pod to be ready")
+	assert.Contains(t, status.Message, "db")
+
+	deployment := &appsv1.Deployment{}
+	if !assert.NoError(t, api.Client.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, deployment)) {
+		return
+	}
+	assert.Equal(t, "db", deployment.Spec.Selector.MatchLabels[constants.DevWorkspaceDedicatedPodLabel], "Should select dedicated pod by component")
+	assert.Equal(t, "db", deployment.Spec.Template.Labels[constants.DevWorkspaceDedicatedPodLabel], "Should label dedicated pod with component")
+	assert.NotContains(t, deployment.Spec.Template.Labels, constants.DevWorkspaceMainPodLabel, "Dedicated pod should not be labelled as main pod")
+	if assert.NotNil(t, deployment.Spec.Template.Spec.Affinity, "Should set affinity for pod mounting PVC") &&
+		assert.NotNil(t, deployment.Spec.Template.Spec.Affinity.PodAffinity) {
+		terms := deployment.Spec.Template.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if assert.Len(t, terms, 1) {
+			assert.Equal(t, corev1.LabelHostname, terms[0].TopologyKey)
+			assert.Equal(t, workspace.Status.DevWorkspaceId, terms[0].LabelSelector.MatchLabels[constants.DevWorkspaceIDLabel])
+		}
+	}
+	service := &corev1.Service{}
+	if assert.NoError(t, api.Client.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, service)) {
+		assert.Equal(t, "db", service.Spec.Selector[constants.DevWorkspaceDedicatedPodLabel], "Service should select dedicated pod")
+	}
+
+	deployment.Status.ReadyReplicas = 1
+	if !assert.NoError(t, api.Client.Status().Update(api.Ctx, deployment)) {
+		return
+	}
+	status = syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	assert.NoError(t, status.Err, "Should not return error")
+	assert.True(t, status.Continue, "Should continue once dedicated pods are ready")
+
+	// Removing the component deletes its deployment and service
+	status = syncDedicatedPodsToCluster(workspace, nil, "test-sa", nil, nil, nil, nil, api)
+	assert.NoError(t, status.Err, "Should not return error")
+	assert.True(t, status.Continue, "Should continue when there are no dedicated pods")
+	err := api.Client.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, &appsv1.Deployment{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete stale deployment")
+	err = api.Client.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, &corev1.Service{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete stale service")
+}
+
+func TestSyncDedicatedPodsToClusterFailsOnUnrecoverableCondition(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetConfigForTesting(nil)
+	workspace := getDedicatedPodTestWorkspace("db")
+	api := getTestClusterAPI(workspace)
+	podAdditions := []v1alpha1.PodAdditions{getDedicatedPodTestPodAdditions("db")}
+	for i := 0; i < 2; i++ {
+		syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	}
+	deployment := &appsv1.Deployment{}
+	name := common.DedicatedPodName(workspace.Status.DevWorkspaceId, "db")
+	if !assert.NoError(t, api.Client.Get(api.Ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, deployment)) {
+		return
+	}
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue, Reason: "FailedCreate"},
+	}
+	if !assert.NoError(t, api.Client.Status().Update(api.Ctx, deployment)) {
+		return
+	}
+	status := syncDedicatedPodsToCluster(workspace, podAdditions, "test-sa", nil, nil, nil, nil, api)
+	assert.True(t, status.FailStartup, "Should fail workspace on unrecoverable deployment condition")
+}
+
+func TestGetDedicatedPodDeploymentsSelectsOnlyWorkspaceDedicatedPods(t *testing.T) {
+	workspace := getDedicatedPodTestWorkspace("db")
+	getDeployment := func(name, workspaceId string, dedicatedPod bool) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+				Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspaceId},
+			},
+		}
+		if dedicatedPod {
+			deployment.Labels[constants.DevWorkspaceDedicatedPodLabel] = "db"
+		}
+		return deployment
+	}
+	api := getTestClusterAPI(
+		getDeployment("test-id-db", "test-id", true),
+		getDeployment("test-id", "test-id", false),
+		getDeployment("other-id-db", "other-id", true),
+	)
+
+	deployments, err := getDedicatedPodDeployments(workspace, api.Client)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, deployments.Items, 1, "Should only select the workspace's dedicated pod deployments") {
+		assert.Equal(t, "test-id-db", deployments.Items[0].Name)
+	}
+}
+
+func TestDedicatedPodName(t *testing.T) {
+	workspaceId := "workspace0123456789abcdef"
+	assert.Equal(t, workspaceId+"-db", common.DedicatedPodName(workspaceId, "db"))
+
+	longPrefix := "a-very-long-component-name-that-is-truncated"
+	first := common.DedicatedPodName(workspaceId, longPrefix+"-first")
+	second := common.DedicatedPodName(workspaceId, longPrefix+"-second")
+	assert.LessOrEqual(t, len(first), 63, "Name should be truncated")
+	assert.LessOrEqual(t, len(second), 63, "Name should be truncated")
+	assert.NotEqual(t, first, second, "Truncated names should not collide")
+}
+
+func getDedicatedPodTestWorkspace(componentName string) *dw.DevWorkspace {
+	dedicatedPod := true
+	return &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: testNamespace,
+			UID:       "test-uid",
+			Labels:    map[string]string{constants.DevWorkspaceCreatorLabel: "test-creator"},
+		},
+		Spec: dw.DevWorkspaceSpec{
+			Template: dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Components: []dw.Component{
+						{
+							Name: componentName,
+							ComponentUnion: dw.ComponentUnion{
+								Container: &dw.ContainerComponent{
+									Container: dw.Container{Image: "test-image", DedicatedPod: &dedicatedPod},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: dw.DevWorkspaceStatus{
+			DevWorkspaceId: "test-id",
+		},
+	}
+}
+
+func getDedicatedPodTestPodAdditions(componentName string) v1alpha1.PodAdditions {
+	return v1alpha1.PodAdditions{
+		Containers: []corev1.Container{
+			{
+				Name:  componentName,
+				Image: "test-image",
+				Ports: []corev1.ContainerPort{{Name: "db", ContainerPort: 5432, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{
+				Name: "claim-devworkspace",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "claim-devworkspace"},
+				},
+			},
+		},
+	}
+}
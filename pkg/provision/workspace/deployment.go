@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
@@ -29,9 +30,11 @@ import (
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	maputils "github.com/devfile/devworkspace-operator/internal/map"
 	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
 	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -39,6 +42,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	runtimeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -63,24 +67,37 @@ var unrecoverablePodEventReasons = map[string]int32{
 	"ReplicaSetCreateError": 1,
 }
 
+// podDisruptionTargetCondition is the pod condition set by Kubernetes when a pod is about to be terminated due to a
+// disruption, such as preemption
+const podDisruptionTargetCondition corev1.PodConditionType = "DisruptionTarget"
+
 var unrecoverableDeploymentConditionReasons = []string{
 	"FailedCreate",
 }
 
 type DeploymentProvisioningStatus struct {
 	ProvisioningStatus
+	// ProjectCloneWarning is a message reported by the project-clone init container about project changes that
+	// could not be applied
+	ProjectCloneWarning string
+	// Preempted is true if the workspace pod was preempted by a pod with a higher priority. If set, Message
+	// describes the preemption
+	Preempted bool
 }
 
+// SyncDeploymentToCluster syncs the main workspace deployment, built from podAdditions, and a deployment for each
+// element of dedicatedPodAdditions to the cluster. The returned status has Continue set once all deployments are ready.
 func SyncDeploymentToCluster(
 	workspace *dw.DevWorkspace,
 	podAdditions []v1alpha1.PodAdditions,
+	dedicatedPodAdditions []v1alpha1.PodAdditions,
 	saName string,
 	clusterAPI sync.ClusterAPI) DeploymentProvisioningStatus {
 
 	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
 	if err != nil {
 		return DeploymentProvisioningStatus{
-			ProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
 				Message:     "failed to read pod tolerations and node selector from namespace",
 				Err:         err,
 				FailStartup: true,
@@ -88,20 +105,55 @@ func SyncDeploymentToCluster(
 		}
 	}
 
+	nsAffinity, nsTopologySpreadConstraints, err := nsconfig.GetNamespacePodAffinityAndTopologySpreadConstraints(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return DeploymentProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
+				Message:     "failed to read pod affinity and topology spread constraints from namespace",
+				Err:         err,
+				FailStartup: true,
+			},
+		}
+	}
+	affinity, topologySpreadConstraints, err := getPodScheduling(workspace, nsAffinity, nsTopologySpreadConstraints)
+	if err != nil {
+		return DeploymentProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
+				Err:         err,
+				FailStartup: true,
+			},
+		}
+	}
+
 	// [design] we have to pass components and routing pod additions separately because we need mountsources from each
 	// component.
-	specDeployment, err := getSpecDeployment(workspace, podAdditions, saName, podTolerations, nodeSelector, clusterAPI.Scheme)
+	specDeployment, err := getSpecDeployment(workspace, podAdditions, saName, podTolerations, nodeSelector, affinity, topologySpreadConstraints, clusterAPI.Scheme)
 	if err != nil {
 		return DeploymentProvisioningStatus{
-			ProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
 				Err:         err,
 				FailStartup: true,
 			},
 		}
 	}
+
+	dedicatedPodStatus := syncDedicatedPodsToCluster(workspace, dedicatedPodAdditions, saName, podTolerations, nodeSelector, affinity, topologySpreadConstraints, clusterAPI)
+	if dedicatedPodStatus.Err != nil || dedicatedPodStatus.FailStartup || dedicatedPodStatus.Requeue {
+		return dedicatedPodStatus
+	}
+
 	if len(specDeployment.Spec.Template.Spec.Containers) == 0 {
 		// DevWorkspace defines no container components, cannot create a deployment
-		return DeploymentProvisioningStatus{ProvisioningStatus{Continue: true}}
+		return dedicatedPodStatus
+	}
+	if len(dedicatedPodAdditions) > 0 {
+		// Distinguish the main workspace pod from dedicated pods, so that routing and the main deployment only target
+		// the main pod
+		specDeployment.Spec.Template.Labels[constants.DevWorkspaceMainPodLabel] = "true"
+		specDeployment.Spec.Selector.MatchLabels[constants.DevWorkspaceMainPodLabel] = "true"
+		if mountsPersistentVolumeClaim(&specDeployment.Spec.Template.Spec) {
+			specDeployment.Spec.Template.Spec.Affinity = withWorkspacePodAffinity(workspace, specDeployment.Spec.Template.Spec.Affinity)
+		}
 	}
 
 	clusterObj, err := sync.SyncObjectWithCluster(specDeployment, clusterAPI)
@@ -110,23 +162,41 @@ func SyncDeploymentToCluster(
 		break
 	case *sync.NotInSyncError:
 		return DeploymentProvisioningStatus{
-			ProvisioningStatus{Requeue: true},
+			ProvisioningStatus: ProvisioningStatus{Requeue: true},
 		}
 	case *sync.UnrecoverableSyncError:
 		return DeploymentProvisioningStatus{
-			ProvisioningStatus{FailStartup: true, Err: t.Cause},
+			ProvisioningStatus: ProvisioningStatus{FailStartup: true, Err: t.Cause},
 		}
 	default:
-		return DeploymentProvisioningStatus{ProvisioningStatus{Err: err}}
+		return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
 	}
 	clusterDeployment := clusterObj.(*appsv1.Deployment)
 
-	deploymentReady := checkDeploymentStatus(clusterDeployment)
+	deploymentReady := checkDeploymentStatus(clusterDeployment) && dedicatedPodStatus.Continue
 	if deploymentReady {
+		projectCloneWarning, err := checkProjectCloneWarning(workspace, clusterAPI)
+		if err != nil {
+			return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+		}
 		return DeploymentProvisioningStatus{
 			ProvisioningStatus: ProvisioningStatus{
 				Continue: true,
 			},
+			ProjectCloneWarning: projectCloneWarning,
+		}
+	}
+
+	preemptedMsg, err := checkPodsPreempted(workspace, clusterAPI)
+	if err != nil {
+		return DeploymentProvisioningStatus{ProvisioningStatus: ProvisioningStatus{Err: err}}
+	}
+	if preemptedMsg != "" {
+		return DeploymentProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
+				Message: preemptedMsg,
+			},
+			Preempted: true,
 		}
 	}
 
@@ -150,14 +220,22 @@ func SyncDeploymentToCluster(
 	}
 	if failureMsg != "" {
 		return DeploymentProvisioningStatus{
-			ProvisioningStatus{
+			ProvisioningStatus: ProvisioningStatus{
 				FailStartup: true,
 				Message:     failureMsg,
 			},
 		}
 	}
 
-	return DeploymentProvisioningStatus{}
+	message := getProjectCloneProgressMessage(workspace, clusterAPI)
+	if message == "" && checkDeploymentStatus(clusterDeployment) {
+		message = dedicatedPodStatus.Message
+	}
+	return DeploymentProvisioningStatus{
+		ProvisioningStatus: ProvisioningStatus{
+			Message: message,
+		},
+	}
 }
 
 // DeleteWorkspaceDeployment deletes the deployment for the DevWorkspace
@@ -177,8 +255,11 @@ func DeleteWorkspaceDeployment(ctx context.Context, workspace *dw.DevWorkspace,
 	return true, nil
 }
 
-// ScaleDeploymentToZero scales the cluster deployment to zero
+// ScaleDeploymentToZero scales the cluster deployment, and any deployments for dedicated pods, to zero
 func ScaleDeploymentToZero(ctx context.Context, workspace *dw.DevWorkspace, client runtimeClient.Client) error {
+	if err := scaleDedicatedPodDeploymentsToZero(ctx, workspace, client); err != nil {
+		return err
+	}
 	patch := []byte(`{"spec":{"replicas": 0}}`)
 	err := client.Patch(ctx, &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -223,6 +304,8 @@ func getSpecDeployment(
 	saName string,
 	podTolerations []corev1.Toleration,
 	nodeSelector map[string]string,
+	affinity *corev1.Affinity,
+	topologySpreadConstraints []corev1.TopologySpreadConstraint,
 	scheme *runtime.Scheme) (*appsv1.Deployment, error) {
 	replicas := int32(1)
 	terminationGracePeriod := int64(10)
@@ -243,7 +326,14 @@ func getSpecDeployment(
 	labels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
 	labels[constants.DevWorkspaceNameLabel] = workspace.Name
 
-	annotations, err := getAdditionalAnnotations(workspace)
+	annotations, err := getAdditionalAnnotations(workspace, podAdditions.Containers)
+	if err != nil {
+		return nil, err
+	}
+	var templateAnnotations map[string]string
+	for k, v := range annotations {
+		templateAnnotations = maputils.Append(templateAnnotations, k, v)
+	}
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -270,6 +360,7 @@ func getSpecDeployment(
 						constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
 						constants.DevWorkspaceNameLabel: workspace.Name,
 					},
+					Annotations: templateAnnotations,
 				},
 				Spec: corev1.PodSpec{
 					InitContainers:                podAdditions.InitContainers,
@@ -292,12 +383,21 @@ func getSpecDeployment(
 	if nodeSelector != nil && len(nodeSelector) > 0 {
 		deployment.Spec.Template.Spec.NodeSelector = nodeSelector
 	}
+	deployment.Spec.Template.Spec.Affinity = affinity
+	if len(topologySpreadConstraints) > 0 {
+		deployment.Spec.Template.Spec.TopologySpreadConstraints = topologySpreadConstraints
+	}
 	if workspace.Spec.Template.Attributes.Exists(constants.RuntimeClassNameAttribute) {
 		runtimeClassName := workspace.Spec.Template.Attributes.GetString(constants.RuntimeClassNameAttribute, nil)
 		if runtimeClassName != "" {
 			deployment.Spec.Template.Spec.RuntimeClassName = &runtimeClassName
 		}
 	}
+	priorityClassName, err := getPriorityClassName(workspace)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Spec.Template.Spec.PriorityClassName = priorityClassName
 
 	if needPVC, pvcName := needsPVCWorkaround(podAdditions); needPVC {
 		// Kubernetes creates directories in a PVC to support subpaths such that only the leaf directory has g+rwx permissions.
@@ -329,6 +429,12 @@ func getSpecDeployment(
 		deployment.Spec.Template.Annotations = maputils.Append(deployment.Spec.Template.Annotations, constants.DevWorkspaceRestrictedAccessAnnotation, restrictedAccess)
 	}
 
+	podTemplate, err := applyPodOverrides(workspace, &deployment.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Spec.Template = *podTemplate
+
 	err = controllerutil.SetControllerReference(workspace, deployment, scheme)
 	if err != nil {
 		return nil, err
@@ -337,6 +443,32 @@ func getSpecDeployment(
 	return deployment, nil
 }
 
+// getPriorityClassName returns the priorityClassName that should be used for the workspace pod, taken from the
+// PriorityClassAttribute if set, or the default priority class in the operator configuration otherwise. Returns an
+// error if the priority class requested by the attribute is not in the list of allowed priority classes.
+func getPriorityClassName(workspace *dw.DevWorkspace) (string, error) {
+	if !workspace.Spec.Template.Attributes.Exists(constants.PriorityClassAttribute) {
+		return config.Workspace.DefaultPriorityClassName, nil
+	}
+	var err error
+	priorityClassName := workspace.Spec.Template.Attributes.GetString(constants.PriorityClassAttribute, &err)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attribute %s: %w", constants.PriorityClassAttribute, err)
+	}
+	if priorityClassName == "" {
+		return config.Workspace.DefaultPriorityClassName, nil
+	}
+	if config.Workspace.AllowedPriorityClassNames == nil {
+		return priorityClassName, nil
+	}
+	for _, allowed := range config.Workspace.AllowedPriorityClassNames {
+		if allowed == priorityClassName {
+			return priorityClassName, nil
+		}
+	}
+	return "", fmt.Errorf("priority class %s is not allowed for DevWorkspaces", priorityClassName)
+}
+
 func getPods(workspace *dw.DevWorkspace, client runtimeClient.Client) (*corev1.PodList, error) {
 	pods := &corev1.PodList{}
 	if err := client.List(context.TODO(), pods, k8sclient.InNamespace(workspace.Namespace), k8sclient.MatchingLabels{
@@ -362,12 +494,12 @@ func checkPodsState(workspace *dw.DevWorkspace,
 	for _, pod := range podList.Items {
 		for _, containerStatus := range pod.Status.ContainerStatuses {
 			if !checkContainerStatusForFailure(&containerStatus) {
-				return fmt.Sprintf("Container %s has state %s", containerStatus.Name, containerStatus.State.Waiting.Reason), nil
+				return fmt.Sprintf("Container %s has state %s%s", containerStatus.Name, containerStatus.State.Waiting.Reason, getLastTerminationReason(&containerStatus)), nil
 			}
 		}
 		for _, initContainerStatus := range pod.Status.InitContainerStatuses {
 			if !checkContainerStatusForFailure(&initContainerStatus) {
-				return fmt.Sprintf("Init Container %s has state %s", initContainerStatus.Name, initContainerStatus.State.Waiting.Reason), nil
+				return fmt.Sprintf("Init Container %s has state %s%s", initContainerStatus.Name, initContainerStatus.State.Waiting.Reason, getLastTerminationReason(&initContainerStatus)), nil
 			}
 		}
 		if msg, err := checkPodEvents(&pod, workspace.Status.DevWorkspaceId, clusterAPI); err != nil || msg != "" {
@@ -377,6 +509,138 @@ func checkPodsState(workspace *dw.DevWorkspace,
 	return "", nil
 }
 
+// checkPodsPreempted checks if a workspace pod was preempted by the scheduler since the workspace was started. Pods
+// being preempted are detected via their DisruptionTarget condition; pods that were already removed are detected via
+// "Preempted" events. As the names of removed pods are not known, events are selected by reason rather than by
+// involved object, and are only checked when no workspace pod is ready. Returns a message describing the preemption,
+// or an empty string if no preemption was detected.
+func checkPodsPreempted(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) (string, error) {
+	podList, err := getPods(workspace, clusterAPI.Client)
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range podList.Items {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == podDisruptionTargetCondition && condition.Status == corev1.ConditionTrue &&
+				(condition.Reason == "PreemptionByScheduler" || condition.Reason == "PreemptionByKubeScheduler") {
+				return fmt.Sprintf("Pod %s was preempted: %s", pod.Name, condition.Message), nil
+			}
+		}
+	}
+	for _, pod := range podList.Items {
+		if isPodReady(&pod) {
+			return "", nil
+		}
+	}
+
+	var startedTime time.Time
+	if startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started); startedCondition != nil {
+		startedTime = startedCondition.LastTransitionTime.Time
+	}
+	podPrefix := common.DeploymentName(workspace.Status.DevWorkspaceId) + "-"
+	evs := &corev1.EventList{}
+	selector, err := fields.ParseSelector("reason=Preempted")
+	if err != nil {
+		return "", fmt.Errorf("failed to parse field selector: %s", err)
+	}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, evs, k8sclient.InNamespace(workspace.Namespace), k8sclient.MatchingFieldsSelector{Selector: selector}); err != nil {
+		return "", fmt.Errorf("failed to list events in namespace %s: %w", workspace.Namespace, err)
+	}
+	for _, ev := range evs.Items {
+		if ev.Reason != "Preempted" || ev.InvolvedObject.Kind != "Pod" || !strings.HasPrefix(ev.InvolvedObject.Name, podPrefix) {
+			continue
+		}
+		eventTime := ev.LastTimestamp.Time
+		if eventTime.IsZero() {
+			eventTime = ev.EventTime.Time
+		}
+		if eventTime.Before(startedTime) {
+			continue
+		}
+		return fmt.Sprintf("Pod %s was preempted: %s", ev.InvolvedObject.Name, ev.Message), nil
+	}
+	return "", nil
+}
+
+// isPodReady returns whether a pod has the Ready condition set to true
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func checkProjectCloneWarning(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) (string, error) {
+	podList, err := getPods(workspace, clusterAPI.Client)
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range podList.Items {
+		if warning := projects.GetProjectCloneWarning(&pod); warning != "" {
+			return warning, nil
+		}
+	}
+	return "", nil
+}
+
+// cloneProgressReadInterval is the minimum interval between reads of a pod's project-clone container logs. Workspaces
+// are reconciled frequently while starting, and reading logs on every reconcile would put unnecessary load on the API
+// server.
+const cloneProgressReadInterval = 10 * time.Second
+
+// cloneProgressMessages stores the most recently read project-clone progress message for each pod, keyed by pod UID.
+// Entries expire after cloneProgressReadInterval.
+var cloneProgressMessages = cache.NewLRUExpireCache(1000)
+
+// getProjectCloneProgressMessage returns a message describing the progress of the project-clone init container,
+// if it is currently running. Logs are read at most once per cloneProgressReadInterval for each pod. Errors reading
+// progress are logged and otherwise ignored, as progress is informational.
+func getProjectCloneProgressMessage(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) string {
+	if clusterAPI.Clientset == nil {
+		return ""
+	}
+	podList, err := getPods(workspace, clusterAPI.Client)
+	if err != nil {
+		clusterAPI.Logger.Info("Failed to list workspace pods to check project-clone progress", "error", err.Error())
+		return ""
+	}
+	for _, pod := range podList.Items {
+		if !projects.IsProjectCloneRunning(&pod) {
+			continue
+		}
+		if message, ok := cloneProgressMessages.Get(pod.UID); ok {
+			return message.(string)
+		}
+		logs, err := getContainerLogs(&pod, projects.GetProjectCloneContainerName(), projects.CloneProgressLogTailLines, clusterAPI)
+		if err != nil {
+			clusterAPI.Logger.Info("Failed to read project-clone logs to check progress", "error", err.Error())
+			return ""
+		}
+		message := "Cloning projects"
+		if progress := projects.ParseCloneProgress(logs); progress != nil {
+			message = progress.Message()
+		}
+		cloneProgressMessages.Add(pod.UID, message, cloneProgressReadInterval)
+		return message
+	}
+	return ""
+}
+
+// getContainerLogs returns the last tailLines lines of logs for a container in pod.
+func getContainerLogs(pod *corev1.Pod, containerName string, tailLines int64, clusterAPI sync.ClusterAPI) (string, error) {
+	req := clusterAPI.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: containerName,
+		TailLines: &tailLines,
+	})
+	logBytes, err := req.DoRaw(clusterAPI.Ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(logBytes), nil
+}
+
 func mergePodAdditions(toMerge []v1alpha1.PodAdditions) (*v1alpha1.PodAdditions, error) {
 	podAdditions := &v1alpha1.PodAdditions{}
 
@@ -459,21 +723,45 @@ func needsPVCWorkaround(podAdditions *v1alpha1.PodAdditions) (needs bool, pvcNam
 	return false, ""
 }
 
-func getAdditionalAnnotations(workspace *dw.DevWorkspace) (map[string]string, error) {
-	annotations := map[string]string{}
-
+// getAdditionalAnnotations returns the deployment annotations defined on the container components that provide the
+// given containers. Returns an error if two components define different values for the same annotation.
+func getAdditionalAnnotations(workspace *dw.DevWorkspace, containers []corev1.Container) (map[string]string, error) {
+	containerNames := map[string]bool{}
+	for _, container := range containers {
+		containerNames[container.Name] = true
+	}
+	var components []dw.Component
 	for _, component := range workspace.Spec.Template.Components {
-		if component.Container == nil || component.Container.Annotation == nil || component.Container.Annotation.Deployment == nil {
+		if containerNames[component.Name] {
+			components = append(components, component)
+		}
+	}
+	return mergeComponentAnnotations(components, func(annotation *dw.Annotation) map[string]string {
+		return annotation.Deployment
+	})
+}
+
+// getServiceAnnotations returns the service annotations defined on the given container components. Returns an error
+// if two components define different values for the same annotation.
+func getServiceAnnotations(components []dw.Component) (map[string]string, error) {
+	return mergeComponentAnnotations(components, func(annotation *dw.Annotation) map[string]string {
+		return annotation.Service
+	})
+}
+
+func mergeComponentAnnotations(components []dw.Component, getAnnotations func(*dw.Annotation) map[string]string) (map[string]string, error) {
+	var annotations map[string]string
+	for _, component := range components {
+		if component.Container == nil || component.Container.Annotation == nil {
 			continue
 		}
-		for k, v := range component.Container.Annotation.Deployment {
+		for k, v := range getAnnotations(component.Container.Annotation) {
 			if currValue, exists := annotations[k]; exists && v != currValue {
 				return nil, fmt.Errorf("conflicting annotations found on container components for key %s", k)
 			}
-			annotations[k] = v
+			annotations = maputils.Append(annotations, k, v)
 		}
 	}
-
 	return annotations, nil
 }
 
@@ -525,6 +813,15 @@ func checkContainerStatusForFailure(containerStatus *corev1.ContainerStatus) (ok
 	return true
 }
 
+// getLastTerminationReason returns a message describing why a container last terminated, e.g. " (last terminated:
+// OOMKilled)", or an empty string if the container has not terminated.
+func getLastTerminationReason(containerStatus *corev1.ContainerStatus) string {
+	if lastTerminated := containerStatus.LastTerminationState.Terminated; lastTerminated != nil && lastTerminated.Reason != "" {
+		return fmt.Sprintf(" (last terminated: %s)", lastTerminated.Reason)
+	}
+	return ""
+}
+
 func checkIfUnrecoverableEventIgnored(reason string) (ignored bool) {
 	for _, ignoredReason := range config.Workspace.IgnoredUnrecoverableEvents {
 		if ignoredReason == reason {
@@ -0,0 +1,309 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testNamespace = "test-namespace"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dw.AddToScheme(scheme))
+}
+
+func getTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	return sync.ClusterAPI{
+		Ctx:    context.Background(),
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+		Logger: zap.New(),
+	}
+}
+
+func getTestComponentWithAnnotations(name string, deploymentAnnotations, serviceAnnotations map[string]string) dw.Component {
+	return dw.Component{
+		Name: name,
+		ComponentUnion: dw.ComponentUnion{
+			Container: &dw.ContainerComponent{
+				Container: dw.Container{
+					Annotation: &dw.Annotation{
+						Deployment: deploymentAnnotations,
+						Service:    serviceAnnotations,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetAdditionalAnnotations(t *testing.T) {
+	workspace := &dw.DevWorkspace{}
+	workspace.Spec.Template.Components = []dw.Component{
+		getTestComponentWithAnnotations("tools", map[string]string{"tools-annotation": "tools"}, nil),
+		getTestComponentWithAnnotations("other", map[string]string{"other-annotation": "other"}, nil),
+		getTestComponentWithAnnotations("dedicated", map[string]string{"dedicated-annotation": "dedicated"}, nil),
+	}
+	containers := []corev1.Container{{Name: "tools"}, {Name: "other"}}
+
+	annotations, err := getAdditionalAnnotations(workspace, containers)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{"tools-annotation": "tools", "other-annotation": "other"}, annotations,
+		"Should only include annotations from components that provide the containers")
+}
+
+func TestGetAdditionalAnnotationsConflict(t *testing.T) {
+	workspace := &dw.DevWorkspace{}
+	workspace.Spec.Template.Components = []dw.Component{
+		getTestComponentWithAnnotations("tools", map[string]string{"test-annotation": "tools"}, nil),
+		getTestComponentWithAnnotations("other", map[string]string{"test-annotation": "other"}, nil),
+	}
+	containers := []corev1.Container{{Name: "tools"}, {Name: "other"}}
+
+	_, err := getAdditionalAnnotations(workspace, containers)
+	assert.Error(t, err, "Should return error when components define conflicting annotations")
+}
+
+func TestGetServiceAnnotations(t *testing.T) {
+	components := []dw.Component{
+		getTestComponentWithAnnotations("tools", map[string]string{"deployment-annotation": "tools"}, map[string]string{"service-annotation": "tools"}),
+		getTestComponentWithAnnotations("other", nil, map[string]string{"service-annotation": "tools"}),
+	}
+	annotations, err := getServiceAnnotations(components)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{"service-annotation": "tools"}, annotations)
+}
+
+func TestGetPriorityClassName(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute *string
+		allowed   []string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "Uses default priority class when attribute is not set",
+			expected: "default-priority",
+		},
+		{
+			name:      "Uses default priority class when attribute is empty",
+			attribute: stringPtr(""),
+			expected:  "default-priority",
+		},
+		{
+			name:      "Uses priority class from attribute when all are allowed",
+			attribute: stringPtr("high-priority"),
+			expected:  "high-priority",
+		},
+		{
+			name:      "Uses priority class from attribute when allowed",
+			attribute: stringPtr("high-priority"),
+			allowed:   []string{"low-priority", "high-priority"},
+			expected:  "high-priority",
+		},
+		{
+			name:      "Returns error when priority class is not allowed",
+			attribute: stringPtr("high-priority"),
+			allowed:   []string{"low-priority"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.SetConfigForTesting(&v1alpha1.OperatorConfiguration{
+				Workspace: &v1alpha1.WorkspaceConfig{
+					DefaultPriorityClassName:  "default-priority",
+					AllowedPriorityClassNames: tt.allowed,
+				},
+			})
+			workspace := &dw.DevWorkspace{}
+			if tt.attribute != nil {
+				workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.PriorityClassAttribute, *tt.attribute)
+			}
+			priorityClassName, err := getPriorityClassName(workspace)
+			if tt.expectErr {
+				assert.Error(t, err, "Should return error")
+			} else if assert.NoError(t, err, "Should not return error") {
+				assert.Equal(t, tt.expected, priorityClassName)
+			}
+		})
+	}
+	config.SetConfigForTesting(nil)
+}
+
+func TestCheckPodsPreempted(t *testing.T) {
+	workspaceId := "test-id"
+	startedTime := time.Now().Add(-10 * time.Minute)
+	podName := common.DeploymentName(workspaceId) + "-abc"
+	getPod := func(ready bool, podConditions ...corev1.PodCondition) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: testNamespace,
+				Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspaceId},
+			},
+			Status: corev1.PodStatus{
+				Conditions: append(podConditions, corev1.PodCondition{Type: corev1.PodReady, Status: readyStatus}),
+			},
+		}
+	}
+	getEvent := func(name, reason, involvedPod string, timestamp time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Reason:         reason,
+			Message:        "Preempted by pod high-priority",
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: involvedPod, Namespace: testNamespace},
+			LastTimestamp:  metav1.NewTime(timestamp),
+		}
+	}
+	removedPod := common.DeploymentName(workspaceId) + "-removed"
+
+	tests := []struct {
+		name            string
+		objects         []client.Object
+		expectPreempted bool
+	}{
+		{
+			name: "Detects pods being preempted",
+			objects: []client.Object{getPod(false, corev1.PodCondition{
+				Type:   podDisruptionTargetCondition,
+				Status: corev1.ConditionTrue,
+				Reason: "PreemptionByScheduler",
+			})},
+			expectPreempted: true,
+		},
+		{
+			name:            "Detects removed pods that were preempted",
+			objects:         []client.Object{getEvent("preempted", "Preempted", removedPod, time.Now())},
+			expectPreempted: true,
+		},
+		{
+			name:    "Ignores preemption events from before the workspace was started",
+			objects: []client.Object{getEvent("preempted", "Preempted", removedPod, startedTime.Add(-time.Minute))},
+		},
+		{
+			name:    "Ignores events for pods of other workspaces",
+			objects: []client.Object{getEvent("preempted", "Preempted", "other-workspace-abc", time.Now())},
+		},
+		{
+			name:    "Ignores events with other reasons",
+			objects: []client.Object{getEvent("killing", "Killing", removedPod, time.Now())},
+		},
+		{
+			name:    "Does not check events when a pod is ready",
+			objects: []client.Object{getPod(true), getEvent("preempted", "Preempted", removedPod, time.Now())},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &dw.DevWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: testNamespace},
+				Status: dw.DevWorkspaceStatus{
+					DevWorkspaceId: workspaceId,
+					Conditions: []dw.DevWorkspaceCondition{
+						{
+							Type:               conditions.Started,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(startedTime),
+						},
+					},
+				},
+			}
+			msg, err := checkPodsPreempted(workspace, getTestClusterAPI(tt.objects...))
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			if tt.expectPreempted {
+				assert.Contains(t, msg, "was preempted", "Should detect preemption")
+			} else {
+				assert.Empty(t, msg, "Should not detect preemption")
+			}
+		})
+	}
+}
+
+func TestGetProjectCloneProgressMessageRateLimitsLogReads(t *testing.T) {
+	workspace := getDedicatedPodTestWorkspace("tools")
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: testNamespace,
+			UID:       "project-clone-progress-test-uid",
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  projects.GetProjectCloneContainerName(),
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	clientset := k8sfake.NewSimpleClientset()
+	api := getTestClusterAPI(workspace, pod)
+	api.Clientset = clientset
+
+	assert.Equal(t, "Cloning projects", getProjectCloneProgressMessage(workspace, api))
+	assert.Equal(t, "Cloning projects", getProjectCloneProgressMessage(workspace, api))
+	assert.Len(t, clientset.Actions(), 1, "Should read logs at most once per interval")
+
+	pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	api = getTestClusterAPI(workspace, pod)
+	api.Clientset = clientset
+	assert.Empty(t, getProjectCloneProgressMessage(workspace, api), "Should not report progress once project-clone completes")
+	assert.Len(t, clientset.Actions(), 1, "Should not read logs when project-clone is not running")
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
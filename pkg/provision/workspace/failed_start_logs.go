@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"sort"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	// failedStartLogContainerMaxBytes is the maximum size of the logs stored for a single container
+	failedStartLogContainerMaxBytes = 64 * 1024
+	// failedStartLogsMaxBytes is the maximum total size of the logs stored for a workspace. This is kept well below
+	// the 1MiB limit on the size of a configmap.
+	failedStartLogsMaxBytes = 512 * 1024
+)
+
+// SyncFailedStartLogsToCluster reads the last lines (as configured in the operator config) of logs of each container
+// and init container in a workspace's pods that has failed, and stores them in a workspace-owned configmap, with one
+// key per container, so that they are available after the workspace is stopped. For containers that have restarted,
+// logs are read from the last terminated instance of the container. If includeNotReady is true (e.g. when the
+// workspace failed to start within the progress timeout), logs are also stored for containers that are running but
+// not ready. Logs are truncated to failedStartLogContainerMaxBytes per container, and logs are omitted once
+// failedStartLogsMaxBytes have been stored. Returns the names of containers whose logs were stored; if no containers
+// have failed, the configmap is not created.
+func SyncFailedStartLogsToCluster(workspace *dw.DevWorkspace, includeNotReady bool, clusterAPI sync.ClusterAPI) ([]string, error) {
+	if clusterAPI.Clientset == nil {
+		return nil, nil
+	}
+	podList, err := getPods(workspace, clusterAPI.Client)
+	if err != nil {
+		return nil, err
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	logs := map[string]string{}
+	var containerNames []string
+	remainingBytes := failedStartLogsMaxBytes
+	for _, pod := range pods {
+		var statuses []corev1.ContainerStatus
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			failed, previous := getFailedContainerState(status)
+			if !failed && !(includeNotReady && isContainerRunningNotReady(status)) {
+				continue
+			}
+			key := fmt.Sprintf("%s.log", status.Name)
+			if _, ok := logs[key]; ok {
+				continue
+			}
+			containerNames = append(containerNames, status.Name)
+			if remainingBytes <= 0 {
+				logs[key] = "Logs omitted: maximum size of stored logs reached"
+				continue
+			}
+			limitBytes := failedStartLogContainerMaxBytes
+			if remainingBytes < limitBytes {
+				limitBytes = remainingBytes
+			}
+			containerLogs, err := getFailedContainerLogs(&pod, status.Name, previous, int64(limitBytes), clusterAPI)
+			if err != nil {
+				clusterAPI.Logger.Info("Failed to read logs for failed container", "container", status.Name, "error", err.Error())
+				containerLogs = fmt.Sprintf("Failed to read logs: %s", err)
+			}
+			// The log limit is approximate, and may return slightly more than the requested number of bytes
+			if len(containerLogs) > limitBytes {
+				containerLogs = containerLogs[:limitBytes]
+			}
+			logs[key] = containerLogs
+			remainingBytes -= len(containerLogs)
+		}
+	}
+	if len(containerNames) == 0 {
+		return nil, nil
+	}
+
+	cmLabels := constants.ControllerAppLabels()
+	cmLabels[constants.DevWorkspaceWatchConfigMapLabel] = "true"
+	cmLabels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.FailedStartLogsConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    cmLabels,
+		},
+		Data: logs,
+	}
+	if err := controllerutil.SetControllerReference(workspace, cm, clusterAPI.Scheme); err != nil {
+		return nil, err
+	}
+	_, err = sync.SyncObjectWithCluster(cm, clusterAPI)
+	switch t := err.(type) {
+	case nil:
+		break
+	case *sync.NotInSyncError:
+		if t.Reason != sync.CreatedObjectReason && t.Reason != sync.UpdatedObjectReason {
+			return nil, err
+		}
+	case *sync.UnrecoverableSyncError:
+		return nil, t.Cause
+	default:
+		return nil, err
+	}
+	return containerNames, nil
+}
+
+// DeleteFailedStartLogs deletes the configmap storing logs from a previous failed start of a workspace, if it exists.
+func DeleteFailedStartLogs(workspace *dw.DevWorkspace, clusterAPI sync.ClusterAPI) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.FailedStartLogsConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+		},
+	}
+	if err := clusterAPI.Client.Delete(clusterAPI.Ctx, cm); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getFailedContainerState returns whether a container has failed, i.e. whether it has terminated with a non-zero exit
+// code. If the container has since restarted, previous is true, and logs should be read from the last terminated
+// instance of the container.
+func getFailedContainerState(status corev1.ContainerStatus) (failed, previous bool) {
+	if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		return true, false
+	}
+	if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		return true, true
+	}
+	return false, false
+}
+
+// isContainerRunningNotReady returns whether a container is running but has not passed its readiness checks.
+func isContainerRunningNotReady(status corev1.ContainerStatus) bool {
+	return status.State.Running != nil && !status.Ready
+}
+
+func getFailedContainerLogs(pod *corev1.Pod, containerName string, previous bool, limitBytes int64, clusterAPI sync.ClusterAPI) (string, error) {
+	tailLines := int64(*config.Workspace.FailedStartLogLines)
+	req := clusterAPI.Clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  containerName,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+		Previous:   previous,
+	})
+	logBytes, err := req.DoRaw(clusterAPI.Ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(logBytes), nil
+}
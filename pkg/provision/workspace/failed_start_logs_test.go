@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetFailedContainerState(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           corev1.ContainerStatus
+		expectedFailed   bool
+		expectedPrevious bool
+	}{
+		{
+			name: "Running container",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			},
+		},
+		{
+			name: "Completed init container",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed", ExitCode: 0}},
+			},
+		},
+		{
+			name: "Failed init container",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			},
+			expectedFailed: true,
+		},
+		{
+			name: "Crashlooping container",
+			status: corev1.ContainerStatus{
+				RestartCount:         3,
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			},
+			expectedFailed:   true,
+			expectedPrevious: true,
+		},
+		{
+			name: "Container waiting for image",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failed, previous := getFailedContainerState(tt.status)
+			assert.Equal(t, tt.expectedFailed, failed)
+			assert.Equal(t, tt.expectedPrevious, previous)
+		})
+	}
+}
+
+func TestIsContainerRunningNotReady(t *testing.T) {
+	assert.True(t, isContainerRunningNotReady(corev1.ContainerStatus{
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}), "Running container that is not ready")
+	assert.False(t, isContainerRunningNotReady(corev1.ContainerStatus{
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}), "Running container that is ready")
+	assert.False(t, isContainerRunningNotReady(corev1.ContainerStatus{
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+	}), "Waiting container")
+}
+
+func TestSyncFailedStartLogsToCluster(t *testing.T) {
+	tailLines := 20
+	config.SetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{FailedStartLogLines: &tailLines},
+	})
+	defer config.SetConfigForTesting(nil)
+	workspace := getDedicatedPodTestWorkspace("tools")
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "project-clone",
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}},
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "tools",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+				{
+					Name:                 "db",
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+				},
+			},
+		},
+	}
+	clientset := k8sfake.NewSimpleClientset()
+	api := getTestClusterAPI(workspace, pod)
+	api.Clientset = clientset
+
+	containerNames, err := SyncFailedStartLogsToCluster(workspace, false, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, []string{"db"}, containerNames, "Should store logs of failed containers only")
+	cm := &corev1.ConfigMap{}
+	cmName := types.NamespacedName{Name: common.FailedStartLogsConfigMapName(workspace.Status.DevWorkspaceId), Namespace: testNamespace}
+	if assert.NoError(t, api.Client.Get(api.Ctx, cmName, cm), "Should create configmap") {
+		assert.Equal(t, map[string]string{"db.log": "fake logs"}, cm.Data)
+	}
+	actions := clientset.Actions()
+	if assert.Len(t, actions, 1, "Should read logs once") {
+		opts, ok := actions[0].(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+		if assert.True(t, ok, "Should read pod logs") {
+			assert.Equal(t, "db", opts.Container)
+			assert.True(t, opts.Previous, "Should read logs of terminated instance of restarted container")
+			assert.Equal(t, int64(tailLines), *opts.TailLines, "Should read configured number of lines")
+			assert.Equal(t, int64(failedStartLogContainerMaxBytes), *opts.LimitBytes, "Should limit size of logs")
+		}
+	}
+
+	// Containers that are running but not ready are included when the workspace timed out
+	containerNames, err = SyncFailedStartLogsToCluster(workspace, true, api)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, []string{"tools", "db"}, containerNames, "Should store logs of containers that are not ready")
+	if assert.NoError(t, api.Client.Get(api.Ctx, cmName, cm), "Should update configmap") {
+		assert.Equal(t, map[string]string{"tools.log": "fake logs", "db.log": "fake logs"}, cm.Data)
+	}
+}
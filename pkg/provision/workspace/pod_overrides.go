@@ -0,0 +1,186 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// applyPodOverrides applies the pod-overrides attribute on a DevWorkspace, if present, to the pod template of the
+// workspace deployment as a strategic merge patch. The affinity and topologySpreadConstraints fields are ignored, as
+// they are merged with namespace and global configuration separately (see getPodScheduling). Returns an error if the
+// attribute cannot be applied or if it modifies fields that are managed by the DevWorkspace Operator.
+func applyPodOverrides(workspace *dw.DevWorkspace, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	if !workspace.Spec.Template.Attributes.Exists(constants.PodOverridesAttribute) {
+		return template, nil
+	}
+	overrides := map[string]interface{}{}
+	if err := workspace.Spec.Template.Attributes.GetInto(constants.PodOverridesAttribute, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse %s attribute: %w", constants.PodOverridesAttribute, err)
+	}
+	if spec, ok := overrides["spec"].(map[string]interface{}); ok {
+		delete(spec, "affinity")
+		delete(spec, "topologySpreadConstraints")
+	}
+	patch, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s attribute: %w", constants.PodOverridesAttribute, err)
+	}
+
+	originalBytes, err := json.Marshal(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pod template: %w", err)
+	}
+	patchedBytes, err := strategicpatch.StrategicMergePatch(originalBytes, patch, &corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s attribute: %w", constants.PodOverridesAttribute, err)
+	}
+	patched := &corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patchedBytes, patched); err != nil {
+		return nil, fmt.Errorf("failed to apply %s attribute: %w", constants.PodOverridesAttribute, err)
+	}
+
+	if err := checkProtectedPodFields(template, patched); err != nil {
+		return nil, fmt.Errorf("invalid %s attribute: %w", constants.PodOverridesAttribute, err)
+	}
+	return patched, nil
+}
+
+// checkProtectedPodFields returns an error if fields of the pod template that are managed by the DevWorkspace Operator
+// differ between original and patched. New labels, annotations, and volumes may be added, but existing ones cannot
+// be modified or removed. Containers can be modified only via the container-overrides attribute on components.
+func checkProtectedPodFields(original, patched *corev1.PodTemplateSpec) error {
+	for key, value := range original.Labels {
+		if patched.Labels[key] != value {
+			return fmt.Errorf("label %s cannot be overridden", key)
+		}
+	}
+	for key, value := range original.Annotations {
+		if patched.Annotations[key] != value {
+			return fmt.Errorf("annotation %s cannot be overridden", key)
+		}
+	}
+	if !jsonEqual(original.Spec.Containers, patched.Spec.Containers) ||
+		!jsonEqual(original.Spec.InitContainers, patched.Spec.InitContainers) {
+		return fmt.Errorf("containers cannot be overridden; use the %s attribute on components instead", constants.ContainerOverridesAttribute)
+	}
+	patchedVolumes := map[string]corev1.Volume{}
+	for _, volume := range patched.Spec.Volumes {
+		patchedVolumes[volume.Name] = volume
+	}
+	for _, volume := range original.Spec.Volumes {
+		if patchedVolume, ok := patchedVolumes[volume.Name]; !ok || !jsonEqual(volume, patchedVolume) {
+			return fmt.Errorf("volume %s cannot be overridden", volume.Name)
+		}
+	}
+	if original.Spec.ServiceAccountName != patched.Spec.ServiceAccountName {
+		return fmt.Errorf("serviceAccountName cannot be overridden")
+	}
+	if original.Spec.RestartPolicy != patched.Spec.RestartPolicy {
+		return fmt.Errorf("restartPolicy cannot be overridden")
+	}
+	if original.Spec.PriorityClassName != patched.Spec.PriorityClassName || !jsonEqual(original.Spec.Priority, patched.Spec.Priority) {
+		return fmt.Errorf("priorityClassName cannot be overridden; use the %s attribute instead", constants.PriorityClassAttribute)
+	}
+	return nil
+}
+
+// GetSensitivePodOverrideFields returns the sensitive fields that are set in the pod-overrides attribute of a
+// workspace template. Setting these fields requires the user to have permission to "use" the field in the
+// "podoverrides" resource in the "controller.devfile.io" API group.
+func GetSensitivePodOverrideFields(template *dw.DevWorkspaceTemplateSpec) ([]string, error) {
+	if !template.Attributes.Exists(constants.PodOverridesAttribute) {
+		return nil, nil
+	}
+	overrides := &corev1.PodTemplateSpec{}
+	if err := template.Attributes.GetInto(constants.PodOverridesAttribute, overrides); err != nil {
+		return nil, fmt.Errorf("failed to read %s attribute in DevWorkspace: %w", constants.PodOverridesAttribute, err)
+	}
+	var fields []string
+	if overrides.Spec.HostNetwork {
+		fields = append(fields, "hostNetwork")
+	}
+	if overrides.Spec.HostPID {
+		fields = append(fields, "hostPID")
+	}
+	if overrides.Spec.HostIPC {
+		fields = append(fields, "hostIPC")
+	}
+	for _, volume := range overrides.Spec.Volumes {
+		if volume.HostPath != nil {
+			fields = append(fields, "hostPath")
+			break
+		}
+	}
+	if overrides.Spec.NodeName != "" {
+		fields = append(fields, "nodeName")
+	}
+	if len(overrides.Spec.Tolerations) > 0 {
+		fields = append(fields, "tolerations")
+	}
+	if len(overrides.Spec.NodeSelector) > 0 {
+		fields = append(fields, "nodeSelector")
+	}
+	if overrides.Spec.RuntimeClassName != nil {
+		fields = append(fields, "runtimeClassName")
+	}
+	if overrides.Spec.SecurityContext != nil {
+		fields = append(fields, "securityContext")
+	}
+	return fields, nil
+}
+
+// CheckImportedPodOverrides returns an error if the pod-overrides attribute in a flattened workspace template sets
+// sensitive fields (see GetSensitivePodOverrideFields) but differs from the attribute defined in the original
+// workspace. As permissions for sensitive fields are only checked for the DevWorkspace itself, they cannot be set
+// by attributes imported from a parent.
+func CheckImportedPodOverrides(original, flattened *dw.DevWorkspaceTemplateSpec) error {
+	fields, err := GetSensitivePodOverrideFields(flattened)
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+	var originalOverrides, flattenedOverrides interface{}
+	if original.Attributes.Exists(constants.PodOverridesAttribute) {
+		if err := original.Attributes.GetInto(constants.PodOverridesAttribute, &originalOverrides); err != nil {
+			return fmt.Errorf("failed to read %s attribute in DevWorkspace: %w", constants.PodOverridesAttribute, err)
+		}
+	}
+	if err := flattened.Attributes.GetInto(constants.PodOverridesAttribute, &flattenedOverrides); err != nil {
+		return fmt.Errorf("failed to read %s attribute in DevWorkspace: %w", constants.PodOverridesAttribute, err)
+	}
+	if !reflect.DeepEqual(originalOverrides, flattenedOverrides) {
+		return fmt.Errorf("%s in the %s attribute must be set in the DevWorkspace, not imported from a parent", strings.Join(fields, ", "), constants.PodOverridesAttribute)
+	}
+	return nil
+}
+
+// jsonEqual compares the serialized forms of a and b, as values that contain e.g. resource.Quantity may not be
+// reflect.DeepEqual after being serialized and parsed again.
+func jsonEqual(a, b interface{}) bool {
+	aBytes, aErr := json.Marshal(a)
+	bBytes, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aBytes, bBytes)
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getTestPodTemplate() *corev1.PodTemplateSpec {
+	terminationGracePeriod := int64(10)
+	return &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{constants.DevWorkspaceIDLabel: "test-id"},
+		},
+		Spec: corev1.PodSpec{
+			Containers:                    []corev1.Container{{Name: "tools", Image: "test-image"}},
+			Volumes:                       []corev1.Volume{{Name: "projects", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			RestartPolicy:                 corev1.RestartPolicyAlways,
+			TerminationGracePeriodSeconds: &terminationGracePeriod,
+			ServiceAccountName:            "test-sa",
+		},
+	}
+}
+
+func getTestWorkspaceWithPodOverrides(overrides interface{}) *dw.DevWorkspace {
+	workspace := &dw.DevWorkspace{}
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.Put(constants.PodOverridesAttribute, overrides, nil)
+	return workspace
+}
+
+func TestApplyPodOverrides(t *testing.T) {
+	workspace := getTestWorkspaceWithPodOverrides(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"test-label": "test-value"},
+		},
+		"spec": map[string]interface{}{
+			"terminationGracePeriodSeconds": 30,
+			"volumes": []interface{}{
+				map[string]interface{}{"name": "extra", "emptyDir": map[string]interface{}{}},
+			},
+			"affinity": map[string]interface{}{
+				"podAntiAffinity": map[string]interface{}{},
+			},
+		},
+	})
+	patched, err := applyPodOverrides(workspace, getTestPodTemplate())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "test-value", patched.Labels["test-label"])
+	assert.Equal(t, "test-id", patched.Labels[constants.DevWorkspaceIDLabel])
+	assert.Equal(t, int64(30), *patched.Spec.TerminationGracePeriodSeconds)
+	assert.Len(t, patched.Spec.Volumes, 2, "Volume should be added to existing volumes")
+	assert.Nil(t, patched.Spec.Affinity, "Affinity should not be applied by pod overrides")
+}
+
+func TestApplyPodOverridesProtectedFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides interface{}
+		errRegexp string
+	}{
+		{
+			name: "Overrides controller label",
+			overrides: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{constants.DevWorkspaceIDLabel: "other-id"},
+				},
+			},
+			errRegexp: "label controller.devfile.io/devworkspace_id cannot be overridden",
+		},
+		{
+			name: "Overrides container",
+			overrides: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "tools", "image": "other-image"},
+					},
+				},
+			},
+			errRegexp: "containers cannot be overridden",
+		},
+		{
+			name: "Overrides volume",
+			overrides: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"volumes": []interface{}{
+						map[string]interface{}{"name": "projects", "hostPath": map[string]interface{}{"path": "/"}},
+					},
+				},
+			},
+			errRegexp: "volume projects cannot be overridden",
+		},
+		{
+			name: "Overrides service account",
+			overrides: map[string]interface{}{
+				"spec": map[string]interface{}{"serviceAccountName": "other-sa"},
+			},
+			errRegexp: "serviceAccountName cannot be overridden",
+		},
+		{
+			name: "Overrides priority class",
+			overrides: map[string]interface{}{
+				"spec": map[string]interface{}{"priorityClassName": "system-cluster-critical"},
+			},
+			errRegexp: "priorityClassName cannot be overridden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := applyPodOverrides(getTestWorkspaceWithPodOverrides(tt.overrides), getTestPodTemplate())
+			if assert.Error(t, err) {
+				assert.Regexp(t, tt.errRegexp, err.Error())
+			}
+		})
+	}
+}
+
+func TestGetSensitivePodOverrideFields(t *testing.T) {
+	workspace := getTestWorkspaceWithPodOverrides(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"hostNetwork":                   true,
+			"nodeName":                      "test-node",
+			"tolerations":                   []interface{}{map[string]interface{}{"operator": "Exists"}},
+			"nodeSelector":                  map[string]interface{}{"node-role.kubernetes.io/infra": ""},
+			"runtimeClassName":              "test-runtime",
+			"securityContext":               map[string]interface{}{"runAsUser": 0},
+			"terminationGracePeriodSeconds": 30,
+			"volumes": []interface{}{
+				map[string]interface{}{"name": "host", "hostPath": map[string]interface{}{"path": "/"}},
+			},
+		},
+	})
+	fields, err := GetSensitivePodOverrideFields(&workspace.Spec.Template)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"hostNetwork", "hostPath", "nodeName", "tolerations", "nodeSelector", "runtimeClassName", "securityContext"}, fields)
+}
+
+func TestCheckImportedPodOverrides(t *testing.T) {
+	sensitiveOverrides := map[string]interface{}{
+		"spec": map[string]interface{}{"hostNetwork": true},
+	}
+	otherOverrides := map[string]interface{}{
+		"spec": map[string]interface{}{"terminationGracePeriodSeconds": 30},
+	}
+	tests := []struct {
+		name      string
+		original  interface{}
+		flattened interface{}
+		expectErr bool
+	}{
+		{
+			name:      "Allows sensitive fields defined in the DevWorkspace",
+			original:  sensitiveOverrides,
+			flattened: sensitiveOverrides,
+		},
+		{
+			name:      "Allows imported overrides without sensitive fields",
+			flattened: otherOverrides,
+		},
+		{
+			name:      "Rejects sensitive fields imported from parent",
+			flattened: sensitiveOverrides,
+			expectErr: true,
+		},
+		{
+			name:      "Rejects sensitive fields replacing DevWorkspace overrides",
+			original:  otherOverrides,
+			flattened: sensitiveOverrides,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &dw.DevWorkspace{}
+			if tt.original != nil {
+				original = getTestWorkspaceWithPodOverrides(tt.original)
+			}
+			flattened := getTestWorkspaceWithPodOverrides(tt.flattened)
+			err := CheckImportedPodOverrides(&original.Spec.Template, &flattened.Spec.Template)
+			if tt.expectErr {
+				assert.Error(t, err, "Should return error")
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
+	containerlib "github.com/devfile/devworkspace-operator/pkg/library/container"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,7 +47,7 @@ func SyncRoutingToCluster(
 	specRouting, err := getSpecRouting(workspace, clusterAPI.Scheme)
 	if err != nil {
 		return RoutingProvisioningStatus{
-			ProvisioningStatus: ProvisioningStatus{Err: err},
+			ProvisioningStatus: ProvisioningStatus{Err: err, FailStartup: true},
 		}
 	}
 
@@ -92,16 +93,27 @@ func getSpecRouting(
 	scheme *runtime.Scheme) (*v1alpha1.DevWorkspaceRouting, error) {
 
 	endpoints := map[string]v1alpha1.EndpointList{}
+	var mainPodComponents []dw.Component
 	for _, component := range workspace.Spec.Template.Components {
 		if component.Container == nil {
 			continue
 		}
+		if containerlib.IsDedicatedPod(&component) {
+			// Endpoints for dedicated pods are exposed by the service created alongside the pod
+			continue
+		}
+		mainPodComponents = append(mainPodComponents, component)
 		componentEndpoints := component.Container.Endpoints
 		if len(componentEndpoints) > 0 {
 			endpoints[component.Name] = append(endpoints[component.Name], conversion.ConvertAllDevfileEndpoints(componentEndpoints)...)
 		}
 	}
 
+	serviceAnnotations, err := getServiceAnnotations(mainPodComponents)
+	if err != nil {
+		return nil, err
+	}
+
 	var annotations map[string]string
 	if val, ok := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; ok {
 		annotations = maputils.Append(annotations, constants.DevWorkspaceRestrictedAccessAnnotation, val)
@@ -121,6 +133,13 @@ func getSpecRouting(
 		routingClass = config.Routing.DefaultRoutingClass
 	}
 
+	podSelector := map[string]string{
+		constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
+	}
+	if hasDedicatedPods(workspace) {
+		podSelector[constants.DevWorkspaceMainPodLabel] = "true"
+	}
+
 	routing := &v1alpha1.DevWorkspaceRouting{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      common.DevWorkspaceRoutingName(workspace.Status.DevWorkspaceId),
@@ -131,15 +150,14 @@ func getSpecRouting(
 			Annotations: annotations,
 		},
 		Spec: v1alpha1.DevWorkspaceRoutingSpec{
-			DevWorkspaceId: workspace.Status.DevWorkspaceId,
-			RoutingClass:   v1alpha1.DevWorkspaceRoutingClass(routingClass),
-			Endpoints:      endpoints,
-			PodSelector: map[string]string{
-				constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
-			},
+			DevWorkspaceId:     workspace.Status.DevWorkspaceId,
+			RoutingClass:       v1alpha1.DevWorkspaceRoutingClass(routingClass),
+			Endpoints:          endpoints,
+			PodSelector:        podSelector,
+			ServiceAnnotations: serviceAnnotations,
 		},
 	}
-	err := controllerutil.SetControllerReference(workspace, routing, scheme)
+	err = controllerutil.SetControllerReference(workspace, routing, scheme)
 	if err != nil {
 		return nil, err
 	}
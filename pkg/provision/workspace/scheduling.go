@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// getPodScheduling returns the affinity and topology spread constraints to be used for the workspace pod. Values are
+// merged from the global configuration, the namespace, and the workspace's pod-overrides attribute, in increasing
+// order of precedence:
+//   - for affinity, each of nodeAffinity, podAffinity, and podAntiAffinity is replaced by the more specific value, if set
+//   - for topology spread constraints, constraints are replaced by more specific constraints with the same topologyKey
+func getPodScheduling(workspace *dw.DevWorkspace, nsAffinity *corev1.Affinity, nsConstraints []corev1.TopologySpreadConstraint) (
+	*corev1.Affinity, []corev1.TopologySpreadConstraint, error) {
+
+	var affinity *corev1.Affinity
+	var constraints []corev1.TopologySpreadConstraint
+	if config.Workspace != nil {
+		affinity = mergeAffinity(affinity, config.Workspace.Affinity)
+		constraints = mergeTopologySpreadConstraints(constraints, config.Workspace.TopologySpreadConstraints)
+	}
+	affinity = mergeAffinity(affinity, nsAffinity)
+	constraints = mergeTopologySpreadConstraints(constraints, nsConstraints)
+
+	if workspace.Spec.Template.Attributes.Exists(constants.PodOverridesAttribute) {
+		overrides := &corev1.PodTemplateSpec{}
+		if err := workspace.Spec.Template.Attributes.GetInto(constants.PodOverridesAttribute, overrides); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s attribute: %w", constants.PodOverridesAttribute, err)
+		}
+		affinity = mergeAffinity(affinity, overrides.Spec.Affinity)
+		constraints = mergeTopologySpreadConstraints(constraints, overrides.Spec.TopologySpreadConstraints)
+	}
+	return affinity, constraints, nil
+}
+
+// mergeAffinity returns a copy of base with each of nodeAffinity, podAffinity, and podAntiAffinity replaced by the
+// corresponding value in override, if set.
+func mergeAffinity(base, override *corev1.Affinity) *corev1.Affinity {
+	if override == nil {
+		return base
+	}
+	merged := &corev1.Affinity{}
+	if base != nil {
+		merged = base.DeepCopy()
+	}
+	if override.NodeAffinity != nil {
+		merged.NodeAffinity = override.NodeAffinity.DeepCopy()
+	}
+	if override.PodAffinity != nil {
+		merged.PodAffinity = override.PodAffinity.DeepCopy()
+	}
+	if override.PodAntiAffinity != nil {
+		merged.PodAntiAffinity = override.PodAntiAffinity.DeepCopy()
+	}
+	return merged
+}
+
+// mergeTopologySpreadConstraints returns the constraints in base, with constraints that have the same topologyKey as
+// a constraint in override replaced. Constraints in override that do not match a constraint in base are appended.
+func mergeTopologySpreadConstraints(base, override []corev1.TopologySpreadConstraint) []corev1.TopologySpreadConstraint {
+	if len(override) == 0 {
+		return base
+	}
+	overridden := map[string]bool{}
+	for _, constraint := range override {
+		overridden[constraint.TopologyKey] = true
+	}
+	var merged []corev1.TopologySpreadConstraint
+	for _, constraint := range base {
+		if !overridden[constraint.TopologyKey] {
+			merged = append(merged, constraint)
+		}
+	}
+	for _, constraint := range override {
+		merged = append(merged, *constraint.DeepCopy())
+	}
+	return merged
+}
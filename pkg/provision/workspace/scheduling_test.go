@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetPodSchedulingMergesByPrecedence(t *testing.T) {
+	globalNodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu", Operator: corev1.NodeSelectorOpDoesNotExist}},
+			}},
+		},
+	}
+	globalAntiAffinity := &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight:          10,
+			PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"},
+		}},
+	}
+	config.SetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			Affinity: &corev1.Affinity{
+				NodeAffinity:    globalNodeAffinity,
+				PodAntiAffinity: globalAntiAffinity,
+			},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 1, WhenUnsatisfiable: corev1.ScheduleAnyway},
+				{TopologyKey: "kubernetes.io/hostname", MaxSkew: 3, WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+	})
+
+	nsNodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"dev"}}},
+			}},
+		},
+	}
+	nsConstraints := []corev1.TopologySpreadConstraint{
+		{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 2, WhenUnsatisfiable: corev1.DoNotSchedule},
+	}
+
+	workspaceAntiAffinity := &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "kubernetes.io/hostname"}},
+	}
+	workspace := &dw.DevWorkspace{}
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.Put(constants.PodOverridesAttribute, corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{PodAntiAffinity: workspaceAntiAffinity},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{TopologyKey: "kubernetes.io/hostname", MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule},
+			},
+		},
+	}, nil)
+
+	affinity, constraints, err := getPodScheduling(workspace, &corev1.Affinity{NodeAffinity: nsNodeAffinity}, nsConstraints)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, nsNodeAffinity, affinity.NodeAffinity, "Namespace node affinity should override global value")
+	assert.Nil(t, affinity.PodAffinity)
+	assert.Equal(t, workspaceAntiAffinity, affinity.PodAntiAffinity, "Workspace anti-affinity should override global value")
+	assert.Equal(t, []corev1.TopologySpreadConstraint{
+		{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: 2, WhenUnsatisfiable: corev1.DoNotSchedule},
+		{TopologyKey: "kubernetes.io/hostname", MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule},
+	}, constraints, "Constraints should be replaced by topologyKey")
+}
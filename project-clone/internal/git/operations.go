@@ -24,6 +24,7 @@ import (
 	gitConfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 
+	"github.com/devfile/devworkspace-operator/project-clone/internal"
 	"github.com/devfile/devworkspace-operator/project-clone/internal/shell"
 )
 
@@ -64,7 +65,8 @@ func CloneProject(project *dw.Project, projectPath string) error {
 	}
 
 	// Delegate to standard git binary because git.PlainClone takes a lot of memory for large repos
-	err := shell.GitCloneProject(defaultRemoteURL, defaultRemoteName, projectPath)
+	progress := internal.NewProgressReporter(project.Name)
+	err := shell.GitCloneProject(defaultRemoteURL, defaultRemoteName, projectPath, progress.GitProgressWriter())
 	if err != nil {
 		return fmt.Errorf("failed to git clone from %s: %s", defaultRemoteURL, err)
 	}
@@ -77,11 +79,15 @@ func CloneProject(project *dw.Project, projectPath string) error {
 func SetupRemotes(repo *git.Repository, project *dw.Project, projectPath string) error {
 	log.Printf("Setting up remotes for project %s", project.Name)
 	for remoteName, remoteUrl := range project.Git.Remotes {
-		_, err := repo.CreateRemote(&gitConfig.RemoteConfig{
+		remoteConfig := &gitConfig.RemoteConfig{
 			Name: remoteName,
 			URLs: []string{remoteUrl},
-		})
-		if err != nil && err != git.ErrRemoteExists {
+		}
+		_, err := repo.CreateRemote(remoteConfig)
+		if err == git.ErrRemoteExists {
+			err = updateRemoteURL(repo, remoteConfig)
+		}
+		if err != nil {
 			return fmt.Errorf("failed to add remote %s: %s", remoteName, err)
 		}
 		err = shell.GitFetchRemote(projectPath, remoteName)
@@ -142,6 +148,24 @@ func CheckoutReference(repo *git.Repository, project *dw.Project, projectPath st
 	return nil
 }
 
+// updateRemoteURL replaces an existing remote in the repository if its URLs do not match the provided config
+func updateRemoteURL(repo *git.Repository, remoteConfig *gitConfig.RemoteConfig) error {
+	existing, err := repo.Remote(remoteConfig.Name)
+	if err != nil {
+		return err
+	}
+	existingURLs := existing.Config().URLs
+	if len(existingURLs) == 1 && existingURLs[0] == remoteConfig.URLs[0] {
+		return nil
+	}
+	log.Printf("Updating URL for remote %s to %s", remoteConfig.Name, remoteConfig.URLs[0])
+	if err := repo.DeleteRemote(remoteConfig.Name); err != nil {
+		return err
+	}
+	_, err = repo.CreateRemote(remoteConfig)
+	return err
+}
+
 func checkoutLocalBranch(projectPath, branchName, remote string) error {
 	log.Printf("Checking out local branch %s", branchName)
 	if err := shell.GitCheckoutBranchLocal(projectPath, branchName); err != nil {
@@ -24,21 +24,36 @@ import (
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 
 	"github.com/devfile/devworkspace-operator/project-clone/internal"
+	"github.com/devfile/devworkspace-operator/project-clone/internal/shell"
 )
 
-func SetupGitProject(project dw.Project) error {
+// SetupGitProject ensures the git project is cloned into $PROJECTS_ROOT and matches its configuration in the
+// DevWorkspace. If the project was previously set up with configuration lastApplied, changes to remotes and
+// checkoutFrom are applied to the existing clone. If the existing clone has uncommitted changes, the checkout is
+// left as-is and a *internal.ProjectUpdateSkippedError is returned.
+func SetupGitProject(project dw.Project, lastApplied *dw.Project) error {
 	needClone, needRemotes, err := internal.CheckProjectState(&project)
 	if err != nil {
 		return fmt.Errorf("failed to check state of repo on disk: %s", err)
 	}
 	if needClone {
 		return doInitialGitClone(&project)
-	} else if needRemotes {
-		return setupRemotesForExistingProject(&project)
-	} else {
+	}
+	if needRemotes {
+		if err := setupRemotesForExistingProject(&project); err != nil {
+			return err
+		}
+	}
+	if lastApplied == nil || lastApplied.Git == nil {
+		// No record of what was applied previously; assume checkout is up to date
 		log.Printf("Project '%s' is already cloned and has all remotes configured", project.Name)
 		return nil
 	}
+	if checkoutFromEqual(project.Git.CheckoutFrom, lastApplied.Git.CheckoutFrom) {
+		log.Printf("Project '%s' is already cloned and checkout is up to date", project.Name)
+		return nil
+	}
+	return updateCheckoutForExistingProject(&project, !needRemotes)
 }
 
 func doInitialGitClone(project *dw.Project) error {
@@ -83,3 +98,44 @@ func setupRemotesForExistingProject(project *dw.Project) error {
 	}
 	return nil
 }
+
+func updateCheckoutForExistingProject(project *dw.Project, needFetch bool) error {
+	projectPath := path.Join(internal.ProjectsRoot, internal.GetClonePath(project))
+	repo, err := internal.OpenRepo(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to open existing project in filesystem: %s", err)
+	} else if repo == nil {
+		return fmt.Errorf("unexpected error while updating checkout for project: git repository not present")
+	}
+	hasChanges, err := shell.GitHasUncommittedChanges(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to check project for uncommitted changes: %s", err)
+	}
+	if hasChanges {
+		return &internal.ProjectUpdateSkippedError{
+			Message: fmt.Sprintf("project %s has uncommitted changes; checkoutFrom was updated but was not applied", project.Name),
+		}
+	}
+	if needFetch {
+		for remoteName := range project.Git.Remotes {
+			if err := shell.GitFetchRemote(projectPath, remoteName); err != nil {
+				return fmt.Errorf("failed to fetch from remote %s: %s", remoteName, err)
+			}
+		}
+	}
+	log.Printf("Updating checkout for project '%s'", project.Name)
+	if err := CheckoutReference(repo, project, projectPath); err != nil {
+		return fmt.Errorf("failed to checkout revision: %s", err)
+	}
+	return nil
+}
+
+func checkoutFromEqual(a, b *dw.CheckoutFrom) bool {
+	if a == nil {
+		a = &dw.CheckoutFrom{}
+	}
+	if b == nil {
+		b = &dw.CheckoutFrom{}
+	}
+	return a.Remote == b.Remote && a.Revision == b.Revision
+}
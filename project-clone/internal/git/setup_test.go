@@ -0,0 +1,179 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package git
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/project-clone/internal"
+)
+
+func TestCheckoutFromEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        *dw.CheckoutFrom
+		b        *dw.CheckoutFrom
+		expected bool
+	}{
+		{
+			name:     "Both nil",
+			expected: true,
+		},
+		{
+			name:     "Nil and empty",
+			a:        &dw.CheckoutFrom{},
+			expected: true,
+		},
+		{
+			name:     "Same remote and revision",
+			a:        &dw.CheckoutFrom{Remote: "origin", Revision: "main"},
+			b:        &dw.CheckoutFrom{Remote: "origin", Revision: "main"},
+			expected: true,
+		},
+		{
+			name: "Revision changed",
+			a:    &dw.CheckoutFrom{Remote: "origin", Revision: "main"},
+			b:    &dw.CheckoutFrom{Remote: "origin", Revision: "v1.0.0"},
+		},
+		{
+			name: "Remote changed",
+			a:    &dw.CheckoutFrom{Remote: "origin", Revision: "main"},
+			b:    &dw.CheckoutFrom{Remote: "upstream", Revision: "main"},
+		},
+		{
+			name: "Revision added",
+			b:    &dw.CheckoutFrom{Revision: "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, checkoutFromEqual(tt.a, tt.b))
+			assert.Equal(t, tt.expected, checkoutFromEqual(tt.b, tt.a), "Should be symmetric")
+		})
+	}
+}
+
+func TestUpdateRemoteURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		existingURL string
+		newURL      string
+	}{
+		{
+			name:        "URL unchanged",
+			existingURL: "https://github.com/example/test-project",
+			newURL:      "https://github.com/example/test-project",
+		},
+		{
+			name:        "URL changed",
+			existingURL: "https://github.com/example/old-project",
+			newURL:      "https://github.com/example/test-project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := git.PlainInit(t.TempDir(), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := repo.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{tt.existingURL}}); err != nil {
+				t.Fatal(err)
+			}
+			err = updateRemoteURL(repo, &gitConfig.RemoteConfig{Name: "origin", URLs: []string{tt.newURL}})
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			remote, err := repo.Remote("origin")
+			if assert.NoError(t, err, "Remote should exist") {
+				assert.Equal(t, []string{tt.newURL}, remote.Config().URLs, "Should set remote URL")
+			}
+		})
+	}
+}
+
+func TestSetupGitProjectExistingProject(t *testing.T) {
+	tests := []struct {
+		name               string
+		lastApplied        *dw.Project
+		uncommittedChanges bool
+		expectSkipped      bool
+	}{
+		{
+			name: "No previous state",
+		},
+		{
+			name:        "Checkout unchanged",
+			lastApplied: getTestProject("main"),
+		},
+		{
+			name:               "Checkout changed with uncommitted changes",
+			lastApplied:        getTestProject("v1.0.0"),
+			uncommittedChanges: true,
+			expectSkipped:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			internal.ProjectsRoot = t.TempDir()
+			project := getTestProject("main")
+			projectPath := path.Join(internal.ProjectsRoot, project.Name)
+			repo, err := git.PlainInit(projectPath, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, url := range project.Git.Remotes {
+				if _, err := repo.CreateRemote(&gitConfig.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.uncommittedChanges {
+				if err := os.WriteFile(path.Join(projectPath, "README.md"), []byte("changes"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = SetupGitProject(*project, tt.lastApplied)
+			if tt.expectSkipped {
+				var skippedErr *internal.ProjectUpdateSkippedError
+				assert.True(t, errors.As(err, &skippedErr), "Should return ProjectUpdateSkippedError")
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+		})
+	}
+}
+
+func getTestProject(revision string) *dw.Project {
+	return &dw.Project{
+		Name: "test-project",
+		ProjectSource: dw.ProjectSource{
+			Git: &dw.GitProjectSource{
+				GitLikeProjectSource: dw.GitLikeProjectSource{
+					Remotes:      map[string]string{"origin": "https://github.com/example/test-project"},
+					CheckoutFrom: &dw.CheckoutFrom{Remote: "origin", Revision: revision},
+				},
+			},
+		},
+	}
+}
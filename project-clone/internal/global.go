@@ -16,6 +16,7 @@
 package internal
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -27,20 +28,20 @@ var (
 	CloneTmpDir  string
 )
 
-// Read and store ProjectsRoot env var for reuse throughout project-clone.
-func init() {
+// Init reads and stores ProjectsRoot env var for reuse throughout project-clone, and creates the temporary directory
+// used for setting up projects. Init must be called before any other functions in this package are used.
+func Init() error {
 	ProjectsRoot = os.Getenv(constants.ProjectsRootEnvVar)
 	if ProjectsRoot == "" {
-		log.Printf("Required environment variable %s is unset", constants.ProjectsRootEnvVar)
-		os.Exit(1)
+		return fmt.Errorf("required environment variable %s is unset", constants.ProjectsRootEnvVar)
 	}
 	// Have to use path within PROJECTS_ROOT in case it is a mounted directory; otherwise, moving files will fail
 	// (os.Rename fails when source and dest are on different partitions)
 	tmpDir, err := os.MkdirTemp(ProjectsRoot, "project-clone-")
 	if err != nil {
-		log.Printf("Failed to get temporary directory for setting up projects: %s", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to get temporary directory for setting up projects: %s", err)
 	}
 	log.Printf("Using temporary directory %s", tmpDir)
 	CloneTmpDir = tmpDir
+	return nil
 }
@@ -0,0 +1,114 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// RestoreFromPrebuild copies the contents of the prebuild mounted at $PROJECT_CLONE_PREBUILD_PATH into
+// $PROJECTS_ROOT, including the project-clone state file recorded by the prebuild. A prebuild is only used when
+// projects have not been set up before (i.e. there is no state file in $PROJECTS_ROOT), and files that already
+// exist in $PROJECTS_ROOT are never overwritten.
+func RestoreFromPrebuild() error {
+	prebuildPath := os.Getenv(constants.ProjectClonePrebuildPath)
+	if prebuildPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(path.Join(ProjectsRoot, stateFileName)); err == nil {
+		log.Printf("Projects have already been set up; ignoring prebuild")
+		return nil
+	}
+	entries, err := os.ReadDir(prebuildPath)
+	if err != nil {
+		return fmt.Errorf("failed to read prebuild: %w", err)
+	}
+	log.Printf("Restoring projects from prebuild")
+	for _, entry := range entries {
+		// Skip temporary directories and logs left by project-clone in the prebuild
+		if strings.HasPrefix(entry.Name(), "project-clone-") {
+			continue
+		}
+		destPath := path.Join(ProjectsRoot, entry.Name())
+		if _, err := os.Lstat(destPath); err == nil {
+			log.Printf("Skipping %s from prebuild as it already exists", entry.Name())
+			continue
+		}
+		// Copy into temporary directory first to avoid leaving a partial copy in $PROJECTS_ROOT
+		tmpPath := path.Join(CloneTmpDir, entry.Name())
+		if err := copyPath(path.Join(prebuildPath, entry.Name()), tmpPath); err != nil {
+			return fmt.Errorf("failed to copy %s from prebuild: %w", entry.Name(), err)
+		}
+		if err := os.Rename(tmpPath, destPath); err != nil {
+			return fmt.Errorf("failed to move %s from prebuild to PROJECTS_ROOT: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// copyPath recursively copies src to dest, preserving file modes and symbolic links.
+func copyPath(src, dest string) error {
+	return filepath.Walk(src, func(srcPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, srcPath)
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(dest, relPath)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(destPath, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(srcPath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, destPath)
+		case info.Mode().IsRegular():
+			return copyFile(srcPath, destPath, info.Mode().Perm())
+		default:
+			// Skip special files (sockets, devices, etc.)
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dest string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+)
+
+// progressReportInterval is the minimum interval between progress reports, to avoid flooding logs
+const progressReportInterval = 2 * time.Second
+
+var gitReceivingObjectsRegexp = regexp.MustCompile(`Receiving objects:\s+(\d+)%`)
+
+// ProgressReporter prints progress for setting up a project to stdout in the format expected by the
+// DevWorkspace Operator (see projects.ParseCloneProgress).
+type ProgressReporter struct {
+	project    string
+	lastReport time.Time
+	// out is where progress reports are written
+	out io.Writer
+}
+
+func NewProgressReporter(projectName string) *ProgressReporter {
+	reporter := &ProgressReporter{project: projectName, out: os.Stdout}
+	reporter.report(projects.CloneProgress{Project: projectName}, true)
+	return reporter
+}
+
+// ReportPercent reports that the current project is percent complete.
+func (r *ProgressReporter) ReportPercent(percent int) {
+	r.report(projects.CloneProgress{Project: r.project, Percent: &percent}, percent == 100)
+}
+
+// ReportBytes reports that bytes have been downloaded for the current project. If the total size is known,
+// it should be passed as total to report percentage complete as well; otherwise total should be <= 0.
+func (r *ProgressReporter) ReportBytes(bytes, total int64) {
+	progress := projects.CloneProgress{Project: r.project, Bytes: bytes}
+	if total > 0 {
+		percent := int(bytes * 100 / total)
+		progress.Percent = &percent
+	}
+	r.report(progress, bytes == total)
+}
+
+func (r *ProgressReporter) report(progress projects.CloneProgress, force bool) {
+	now := time.Now()
+	if !force && now.Sub(r.lastReport) < progressReportInterval {
+		return
+	}
+	r.lastReport = now
+	fmt.Fprintln(r.out, projects.FormatCloneProgress(progress))
+}
+
+// GitProgressWriter returns a writer that parses the progress output of git commands run with '--progress' and
+// reports it through the ProgressReporter. Progress updates (lines terminated by '\r') are consumed, while all
+// other output is forwarded to os.Stderr.
+func (r *ProgressReporter) GitProgressWriter() io.Writer {
+	return &gitProgressWriter{reporter: r, out: os.Stderr}
+}
+
+type gitProgressWriter struct {
+	reporter *ProgressReporter
+	// out is where output other than progress updates is forwarded
+	out io.Writer
+	// buf stores output that has not yet been terminated by '\r' or '\n'
+	buf []byte
+}
+
+func (w *gitProgressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexAny(w.buf, "\r\n")
+		if idx < 0 {
+			break
+		}
+		segment := w.buf[:idx]
+		terminator := w.buf[idx]
+		w.buf = w.buf[idx+1:]
+		if match := gitReceivingObjectsRegexp.FindSubmatch(segment); match != nil {
+			if percent, err := strconv.Atoi(string(match[1])); err == nil {
+				w.reporter.ReportPercent(percent)
+			}
+		}
+		if terminator == '\n' {
+			if _, err := w.out.Write(append(segment, '\n')); err != nil {
+				return len(p), err
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// ProgressReader wraps a reader to report the number of bytes read through the ProgressReporter
+func (r *ProgressReporter) ProgressReader(reader io.Reader, total int64) io.Reader {
+	return &progressReader{reporter: r, reader: reader, total: total}
+}
+
+type progressReader struct {
+	reporter *ProgressReporter
+	reader   io.Reader
+	read     int64
+	total    int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	r.reporter.ReportBytes(r.read, r.total)
+	return n, err
+}
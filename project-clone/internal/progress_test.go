@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+)
+
+// gitCloneOutput is an excerpt of the output of 'git clone --progress'. Progress updates are terminated by '\r' so
+// that they are updated in place on a terminal.
+const gitCloneOutput = "Cloning into 'test-project'...\n" +
+	"remote: Enumerating objects: 302, done.        \n" +
+	"remote: Counting objects:   0% (1/302)        \rremote: Counting objects:  50% (151/302)        \r" +
+	"remote: Counting objects: 100% (302/302)        \rremote: Counting objects: 100% (302/302), done.        \n" +
+	"remote: Compressing objects:   0% (1/302)        \rremote: Compressing objects: 100% (302/302), done.        \n" +
+	"Receiving objects:   0% (1/302)\rReceiving objects:  49% (148/302)\rReceiving objects:  99% (299/302)\r" +
+	"Receiving objects: 100% (302/302)\rReceiving objects: 100% (302/302), 616.95 KiB | 9.49 MiB/s, done.\n" +
+	"remote: Total 302 (delta 0), reused 0 (delta 0), pack-reused 0        \n"
+
+// expectedGitCloneStderr is the output of 'git clone --progress' that is not a progress update
+const expectedGitCloneStderr = "Cloning into 'test-project'...\n" +
+	"remote: Enumerating objects: 302, done.        \n" +
+	"remote: Counting objects: 100% (302/302), done.        \n" +
+	"remote: Compressing objects: 100% (302/302), done.        \n" +
+	"Receiving objects: 100% (302/302), 616.95 KiB | 9.49 MiB/s, done.\n" +
+	"remote: Total 302 (delta 0), reused 0 (delta 0), pack-reused 0        \n"
+
+func TestGitProgressWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunkSize int
+	}{
+		{
+			name:      "Single write",
+			chunkSize: len(gitCloneOutput),
+		},
+		{
+			name:      "Writes split within lines",
+			chunkSize: 7,
+		},
+		{
+			name:      "Single byte writes",
+			chunkSize: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progressOut, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			reporter := &ProgressReporter{project: "test-project", out: progressOut}
+			writer := &gitProgressWriter{reporter: reporter, out: stderr}
+
+			output := []byte(gitCloneOutput)
+			for len(output) > 0 {
+				n := tt.chunkSize
+				if n > len(output) {
+					n = len(output)
+				}
+				written, err := writer.Write(output[:n])
+				if !assert.NoError(t, err, "Should not return error") || !assert.Equal(t, n, written, "Should consume all bytes") {
+					return
+				}
+				output = output[n:]
+			}
+
+			assert.Equal(t, expectedGitCloneStderr, stderr.String(), "Should forward output other than progress updates")
+			assert.Empty(t, writer.buf, "Should not buffer terminated output")
+			progress := projects.ParseCloneProgress(progressOut.String())
+			if assert.NotNil(t, progress, "Should report progress") && assert.NotNil(t, progress.Percent) {
+				assert.Equal(t, "test-project", progress.Project)
+				assert.Equal(t, 100, *progress.Percent, "Should report completed clone")
+			}
+		})
+	}
+}
+
+func TestGitProgressWriterRateLimitsReports(t *testing.T) {
+	progressOut := &bytes.Buffer{}
+	reporter := &ProgressReporter{project: "test-project", out: progressOut}
+	writer := &gitProgressWriter{reporter: reporter, out: &bytes.Buffer{}}
+
+	_, err := writer.Write([]byte("Receiving objects:   0% (1/302)\rReceiving objects:  49% (148/302)\rReceiving objects:  99% (299/302)\r"))
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, projects.FormatCloneProgress(projects.CloneProgress{Project: "test-project", Percent: intPtr(0)})+"\n",
+		progressOut.String(), "Should only report first update within report interval")
+
+	_, err = writer.Write([]byte("Receiving objects: 100% (302/302)\r"))
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	progress := projects.ParseCloneProgress(progressOut.String())
+	if assert.NotNil(t, progress) && assert.NotNil(t, progress.Percent) {
+		assert.Equal(t, 100, *progress.Percent, "Should always report completion")
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
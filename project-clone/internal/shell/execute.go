@@ -17,22 +17,27 @@ package shell
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
+	"strings"
 )
 
-// GitCloneProject constructs a command-line string for cloning a git project, and delegates execution
-// to the os/exec package.
-func GitCloneProject(repoUrl, defaultRemoteName, destPath string) error {
+// GitCloneProject clones the repository at repoUrl into destPath. Git's progress output is written to progress.
+func GitCloneProject(repoUrl, defaultRemoteName, destPath string, progress io.Writer) error {
 	args := []string{
 		"clone",
 		repoUrl,
 		"--origin", defaultRemoteName,
+		"--progress",
 		"--",
 		destPath,
 	}
-	return executeCommand("git", args...)
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = progress
+	cmd.Stdout = os.Stdout
+	return cmd.Run()
 }
 
 // GitResetProject runs `git reset --hard` in the project specified by projectPath
@@ -139,6 +144,30 @@ func GitSetTrackingRemoteBranch(projectPath, branchName, remote string) error {
 	return executeCommand("git", "branch", "--set-upstream-to", fmt.Sprintf("%s/%s", remote, branchName), branchName)
 }
 
+// GitHasUncommittedChanges returns true if the git repository at projectPath has modified, staged, or untracked files
+func GitHasUncommittedChanges(projectPath string) (bool, error) {
+	currDir, err := os.Getwd()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current working directory: %s", err)
+	}
+	defer func() {
+		if err := os.Chdir(currDir); err != nil {
+			log.Printf("failed to return to original working directory: %s", err)
+		}
+	}()
+	err = os.Chdir(projectPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to move to project directory %s: %s", projectPath, err)
+	}
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(output))) > 0, nil
+}
+
 func executeCommand(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stderr = os.Stderr
@@ -0,0 +1,127 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package starter
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/project-clone/internal"
+	"github.com/devfile/devworkspace-operator/project-clone/internal/git"
+	"github.com/devfile/devworkspace-operator/project-clone/internal/zip"
+)
+
+// SetupStarterProject clones or extracts the starter project into $PROJECTS_ROOT/<name>. If the starter project
+// defines a subDir, only the contents of that subdirectory are kept. If a directory with the starter project's
+// name already exists in $PROJECTS_ROOT, it is left untouched.
+func SetupStarterProject(starterProject dw.StarterProject) error {
+	if err := validateSubDir(starterProject.SubDir); err != nil {
+		return err
+	}
+	projectPath := path.Join(internal.ProjectsRoot, starterProject.Name)
+	if exists, err := internal.DirExists(projectPath); err != nil {
+		return fmt.Errorf("failed to check path %s: %s", projectPath, err)
+	} else if exists {
+		log.Printf("Directory for starter project '%s' already exists", starterProject.Name)
+		return nil
+	}
+
+	project := dw.Project{
+		Name:          starterProject.Name,
+		ProjectSource: starterProject.ProjectSource,
+	}
+	var err error
+	switch {
+	case project.Git != nil:
+		err = git.SetupGitProject(project, nil)
+	case project.Zip != nil:
+		err = zip.SetupZipProject(project, nil)
+	default:
+		err = fmt.Errorf("starter project does not specify Git or Zip source")
+	}
+	if err != nil {
+		return err
+	}
+
+	if starterProject.SubDir != "" {
+		if err := useSubDir(projectPath, starterProject.SubDir); err != nil {
+			// Remove the full project, as it would otherwise be treated as an already set up starter project on the
+			// next start
+			if removeErr := os.RemoveAll(projectPath); removeErr != nil {
+				log.Printf("Failed to remove starter project directory %s: %s", projectPath, removeErr)
+			}
+			return fmt.Errorf("failed to set up subDir %s for starter project: %s", starterProject.SubDir, err)
+		}
+	}
+	return nil
+}
+
+// validateSubDir checks that subDir is a relative path that does not point outside of the starter project.
+func validateSubDir(subDir string) error {
+	if subDir == "" {
+		return nil
+	}
+	cleanSubDir := path.Clean(subDir)
+	if path.IsAbs(cleanSubDir) || cleanSubDir == ".." || strings.HasPrefix(cleanSubDir, "../") {
+		return fmt.Errorf("subDir %s for starter project must be a relative path within the project", subDir)
+	}
+	return nil
+}
+
+// useSubDir replaces the directory at projectPath with its subdirectory subDir.
+func useSubDir(projectPath, subDir string) error {
+	if err := validateSubDir(subDir); err != nil {
+		return err
+	}
+	subDirPath := path.Join(projectPath, subDir)
+	if subDirPath == projectPath {
+		return nil
+	}
+	// Symbolic links in the project could otherwise be used to move files from outside the project
+	resolvedProjectPath, err := filepath.EvalSymlinks(projectPath)
+	if err != nil {
+		return err
+	}
+	resolvedSubDirPath, err := filepath.EvalSymlinks(subDirPath)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(resolvedSubDirPath, resolvedProjectPath+"/") {
+		return fmt.Errorf("%s resolves to a path outside of the project", subDir)
+	}
+	info, err := os.Lstat(subDirPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", subDir)
+	}
+	tmpSubDirPath := path.Join(internal.CloneTmpDir, path.Base(projectPath)+"-subdir")
+	log.Printf("Using subdirectory %s of starter project", subDir)
+	if err := os.Rename(subDirPath, tmpSubDirPath); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(projectPath); err != nil {
+		return err
+	}
+	return os.Rename(tmpSubDirPath, projectPath)
+}
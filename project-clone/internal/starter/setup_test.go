@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package starter
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/project-clone/internal"
+)
+
+func TestSetupStarterProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(getTestZip(t, map[string]string{
+			"starter-main/README.md":          "root readme",
+			"starter-main/backend/main.go":    "package main",
+			"starter-main/frontend/README.md": "frontend readme",
+		}))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		subDir        string
+		expectedFiles []string
+		expectedErr   string
+	}{
+		{
+			name:          "Sets up full starter project",
+			expectedFiles: []string{"README.md", "backend", "frontend"},
+		},
+		{
+			name:          "Sets up subDir of starter project",
+			subDir:        "backend",
+			expectedFiles: []string{"main.go"},
+		},
+		{
+			name:        "Removes starter project when subDir does not exist",
+			subDir:      "missing",
+			expectedErr: "failed to set up subDir missing for starter project",
+		},
+		{
+			name:        "Removes starter project when subDir is a file",
+			subDir:      "README.md",
+			expectedErr: "failed to set up subDir README.md for starter project: README.md is not a directory",
+		},
+		{
+			name:        "Rejects subDir outside of project",
+			subDir:      "../..",
+			expectedErr: "subDir ../.. for starter project must be a relative path within the project",
+		},
+		{
+			name:        "Rejects absolute subDir",
+			subDir:      "/etc",
+			expectedErr: "subDir /etc for starter project must be a relative path within the project",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestProjectsRoot(t)
+			starterProject := dw.StarterProject{
+				Name:   "test-starter",
+				SubDir: tt.subDir,
+				ProjectSource: dw.ProjectSource{
+					Zip: &dw.ZipProjectSource{Location: server.URL},
+				},
+			}
+			projectPath := path.Join(internal.ProjectsRoot, starterProject.Name)
+
+			err := SetupStarterProject(starterProject)
+			if tt.expectedErr != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Contains(t, err.Error(), tt.expectedErr)
+				}
+				exists, err := internal.DirExists(projectPath)
+				assert.NoError(t, err)
+				assert.False(t, exists, "Starter project directory should not be left in PROJECTS_ROOT")
+				return
+			}
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.ElementsMatch(t, tt.expectedFiles, readDirNames(t, projectPath))
+		})
+	}
+}
+
+func TestUseSubDirRejectsSymlinksOutsideProject(t *testing.T) {
+	setupTestProjectsRoot(t)
+	outsideDir := t.TempDir()
+	if err := os.WriteFile(path.Join(outsideDir, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	projectPath := path.Join(internal.ProjectsRoot, "test-starter")
+	if err := os.MkdirAll(projectPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outsideDir, path.Join(projectPath, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := useSubDir(projectPath, "link")
+	assert.Error(t, err, "Should not use subDir that links outside of the project")
+	assert.Equal(t, []string{"secret"}, readDirNames(t, outsideDir), "Should not move files outside of the project")
+	assert.Equal(t, []string{"link"}, readDirNames(t, projectPath), "Should not modify project")
+}
+
+func setupTestProjectsRoot(t *testing.T) {
+	internal.ProjectsRoot = t.TempDir()
+	tmpDir, err := os.MkdirTemp(internal.ProjectsRoot, "project-clone-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	internal.CloneTmpDir = tmpDir
+}
+
+func getTestZip(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buf)
+	for name, contents := range files {
+		fileWriter, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fileWriter.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readDirNames(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() == path.Base(internal.CloneTmpDir) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+)
+
+const (
+	stateFileName = ".project-clone-state.json"
+)
+
+// ProjectCloneState records the project configuration that was last successfully applied to $PROJECTS_ROOT, to allow
+// detecting changes to projects in the DevWorkspace across restarts.
+type ProjectCloneState struct {
+	// Projects maps project names to the configuration last applied for that project
+	Projects map[string]dw.Project `json:"projects"`
+	// StarterProject is the name of the starter project that was set up in $PROJECTS_ROOT, if any
+	StarterProject string `json:"starterProject,omitempty"`
+}
+
+// ProjectUpdateSkippedError is returned when a project on disk does not match its configuration in the DevWorkspace,
+// but cannot be safely updated (e.g. because the project has uncommitted changes).
+type ProjectUpdateSkippedError struct {
+	Message string
+}
+
+func (e *ProjectUpdateSkippedError) Error() string {
+	return e.Message
+}
+
+// ReadProjectCloneState reads the project-clone state file from $PROJECTS_ROOT. If the state file does not exist,
+// an empty state is returned.
+func ReadProjectCloneState() (*ProjectCloneState, error) {
+	state := &ProjectCloneState{
+		Projects: map[string]dw.Project{},
+	}
+	fileBytes, err := os.ReadFile(path.Join(ProjectsRoot, stateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("error reading project-clone state file: %s", err)
+	}
+	if err := json.Unmarshal(fileBytes, state); err != nil {
+		return nil, fmt.Errorf("error parsing project-clone state file: %s", err)
+	}
+	if state.Projects == nil {
+		state.Projects = map[string]dw.Project{}
+	}
+	return state, nil
+}
+
+// WriteProjectCloneState writes the provided state to the project-clone state file in $PROJECTS_ROOT.
+func WriteProjectCloneState(state *ProjectCloneState) error {
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error serializing project-clone state: %s", err)
+	}
+	// Write to a temporary file and move it into place to avoid leaving a partially-written state file
+	tmpStatePath := path.Join(CloneTmpDir, stateFileName)
+	if err := os.WriteFile(tmpStatePath, stateBytes, 0644); err != nil {
+		return fmt.Errorf("error writing project-clone state file: %s", err)
+	}
+	if err := os.Rename(tmpStatePath, path.Join(ProjectsRoot, stateFileName)); err != nil {
+		return fmt.Errorf("error moving project-clone state file to PROJECTS_ROOT: %s", err)
+	}
+	return nil
+}
+
+// GetLastApplied returns the configuration last applied for a project, or nil if the project has no recorded state.
+func (s *ProjectCloneState) GetLastApplied(projectName string) *dw.Project {
+	project, ok := s.Projects[projectName]
+	if !ok {
+		return nil
+	}
+	return &project
+}
+
+// KeepUnprocessed copies the configuration last applied for projects that are not recorded in s from previous, so
+// that state is not lost for projects that were not processed (e.g. because setting up an earlier project failed).
+func (s *ProjectCloneState) KeepUnprocessed(previous *ProjectCloneState) {
+	for name, project := range previous.Projects {
+		if _, ok := s.Projects[name]; !ok {
+			s.Projects[name] = project
+		}
+	}
+}
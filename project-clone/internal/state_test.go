@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadProjectCloneStateWithoutStateFile(t *testing.T) {
+	setupTestProjectsRoot(t)
+	state, err := ReadProjectCloneState()
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Empty(t, state.Projects, "Should return empty state")
+	assert.NotNil(t, state.Projects, "Should initialize projects map")
+	assert.Nil(t, state.GetLastApplied("test-project"), "Should not return last applied configuration")
+}
+
+func TestProjectCloneStateRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *ProjectCloneState
+	}{
+		{
+			name: "Git and zip projects",
+			state: &ProjectCloneState{
+				Projects: map[string]dw.Project{
+					"git-project": getTestGitProject("git-project", "main"),
+					"zip-project": {
+						Name: "zip-project",
+						ProjectSource: dw.ProjectSource{
+							Zip: &dw.ZipProjectSource{Location: "https://example.com/project.zip"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Starter project",
+			state: &ProjectCloneState{
+				Projects:       map[string]dw.Project{},
+				StarterProject: "test-starter",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestProjectsRoot(t)
+			if !assert.NoError(t, WriteProjectCloneState(tt.state), "Should write state") {
+				return
+			}
+			state, err := ReadProjectCloneState()
+			if !assert.NoError(t, err, "Should read state") {
+				return
+			}
+			assert.Equal(t, tt.state, state, "State should be unchanged after round trip")
+			assert.NoFileExists(t, path.Join(CloneTmpDir, stateFileName), "Should move temporary state file into place")
+		})
+	}
+}
+
+func TestReadProjectCloneStateInvalid(t *testing.T) {
+	setupTestProjectsRoot(t)
+	if err := os.WriteFile(path.Join(ProjectsRoot, stateFileName), []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadProjectCloneState()
+	assert.Error(t, err, "Should return error for invalid state file")
+}
+
+func TestKeepUnprocessed(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  map[string]dw.Project
+		previous map[string]dw.Project
+		expected map[string]dw.Project
+	}{
+		{
+			name:     "Keeps projects that were not processed",
+			current:  map[string]dw.Project{"first": getTestGitProject("first", "main")},
+			previous: map[string]dw.Project{"second": getTestGitProject("second", "main")},
+			expected: map[string]dw.Project{
+				"first":  getTestGitProject("first", "main"),
+				"second": getTestGitProject("second", "main"),
+			},
+		},
+		{
+			name:     "Does not overwrite processed projects",
+			current:  map[string]dw.Project{"first": getTestGitProject("first", "v2")},
+			previous: map[string]dw.Project{"first": getTestGitProject("first", "v1")},
+			expected: map[string]dw.Project{"first": getTestGitProject("first", "v2")},
+		},
+		{
+			name:     "No previous state",
+			current:  map[string]dw.Project{"first": getTestGitProject("first", "main")},
+			previous: map[string]dw.Project{},
+			expected: map[string]dw.Project{"first": getTestGitProject("first", "main")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &ProjectCloneState{Projects: tt.current}
+			state.KeepUnprocessed(&ProjectCloneState{Projects: tt.previous})
+			assert.Equal(t, tt.expected, state.Projects)
+		})
+	}
+}
+
+func setupTestProjectsRoot(t *testing.T) {
+	ProjectsRoot = t.TempDir()
+	tmpDir, err := os.MkdirTemp(ProjectsRoot, "project-clone-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	CloneTmpDir = tmpDir
+}
+
+func getTestGitProject(name, revision string) dw.Project {
+	return dw.Project{
+		Name: name,
+		ProjectSource: dw.ProjectSource{
+			Git: &dw.GitProjectSource{
+				GitLikeProjectSource: dw.GitLikeProjectSource{
+					Remotes:      map[string]string{"origin": "https://github.com/example/" + name},
+					CheckoutFrom: &dw.CheckoutFrom{Remote: "origin", Revision: revision},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package internal
+
+import (
+	"path"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProjectState(t *testing.T) {
+	tests := []struct {
+		name                string
+		cloned              bool
+		repoRemotes         map[string]string
+		expectedNeedClone   bool
+		expectedNeedRemotes bool
+	}{
+		{
+			name:              "Project not cloned",
+			expectedNeedClone: true,
+		},
+		{
+			name:        "Remotes up to date",
+			cloned:      true,
+			repoRemotes: map[string]string{"origin": "https://github.com/example/test-project"},
+		},
+		{
+			name:   "Additional remotes in repository are ignored",
+			cloned: true,
+			repoRemotes: map[string]string{
+				"origin":   "https://github.com/example/test-project",
+				"upstream": "https://github.com/upstream/test-project",
+			},
+		},
+		{
+			name:                "Remote missing in repository",
+			cloned:              true,
+			repoRemotes:         map[string]string{"upstream": "https://github.com/upstream/test-project"},
+			expectedNeedRemotes: true,
+		},
+		{
+			name:                "Remote URL changed",
+			cloned:              true,
+			repoRemotes:         map[string]string{"origin": "https://github.com/example/old-project"},
+			expectedNeedRemotes: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestProjectsRoot(t)
+			project := getTestGitProject("test-project", "main")
+			if tt.cloned {
+				repo, err := git.PlainInit(path.Join(ProjectsRoot, GetClonePath(&project)), false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for name, url := range tt.repoRemotes {
+					if _, err := repo.CreateRemote(&gitConfig.RemoteConfig{Name: name, URLs: []string{url}}); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			needClone, needRemotes, err := CheckProjectState(&project)
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.Equal(t, tt.expectedNeedClone, needClone, "Unexpected needClone")
+			assert.Equal(t, tt.expectedNeedRemotes, needRemotes, "Unexpected needRemotes")
+		})
+	}
+}
@@ -34,8 +34,10 @@ const (
 	tmpDir = "/tmp/"
 )
 
-// SetupZipProject downloads and extracts a zip-type project to the corresponding clonePath.
-func SetupZipProject(project v1alpha2.Project) error {
+// SetupZipProject downloads and extracts the zip project into $PROJECTS_ROOT if it is not already present. Existing
+// projects are never overwritten; if the zip location changed since the project was set up with configuration
+// lastApplied, a *internal.ProjectUpdateSkippedError is returned.
+func SetupZipProject(project v1alpha2.Project, lastApplied *v1alpha2.Project) error {
 	if project.Zip == nil {
 		return fmt.Errorf("project has no 'zip' source")
 	}
@@ -43,6 +45,11 @@ func SetupZipProject(project v1alpha2.Project) error {
 	clonePath := internal.GetClonePath(&project)
 	projectPath := path.Join(internal.ProjectsRoot, clonePath)
 	if exists, err := internal.DirExists(projectPath); exists {
+		if lastApplied != nil && lastApplied.Zip != nil && lastApplied.Zip.Location != url {
+			return &internal.ProjectUpdateSkippedError{
+				Message: fmt.Sprintf("project %s zip location was updated but project already exists at %s and was not updated", project.Name, clonePath),
+			}
+		}
 		// Assume project is already set up
 		log.Printf("Project '%s' is already configured", project.Name)
 		return nil
@@ -54,7 +61,7 @@ func SetupZipProject(project v1alpha2.Project) error {
 
 	zipFilePath := path.Join(tmpDir, fmt.Sprintf("%s.zip", clonePath))
 	log.Printf("Downloading project archive from %s", url)
-	err := downloadZip(url, zipFilePath)
+	err := downloadZip(url, zipFilePath, internal.NewProgressReporter(project.Name))
 	if err != nil {
 		return fmt.Errorf("failed to download archive: %s", err)
 	}
@@ -83,7 +90,7 @@ func SetupZipProject(project v1alpha2.Project) error {
 //
 // Adapted from the Che plugin broker:
 // https://github.com/eclipse/che-plugin-broker/blob/27e7c6953c92633cbe7e8ce746a16ca10d240ea2/utils/ioutil.go#L67
-func downloadZip(url, destPath string) error {
+func downloadZip(url, destPath string, progress *internal.ProgressReporter) error {
 	client := http.DefaultClient
 	resp, err := client.Get(url)
 	if err != nil {
@@ -101,7 +108,7 @@ func downloadZip(url, destPath string) error {
 	}
 	defer closeSafe(out)
 
-	_, err = io.Copy(out, resp.Body)
+	_, err = io.Copy(out, progress.ProgressReader(resp.Body, resp.ContentLength))
 	if err != nil {
 		return err
 	}
@@ -16,21 +16,32 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
 	"path"
+	"strings"
 	"syscall"
 
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
 	"github.com/devfile/devworkspace-operator/project-clone/internal"
 	"github.com/devfile/devworkspace-operator/project-clone/internal/git"
+	"github.com/devfile/devworkspace-operator/project-clone/internal/starter"
 	"github.com/devfile/devworkspace-operator/project-clone/internal/zip"
 )
 
 const (
 	logFileName    = "project-clone-errors.log"
 	tmpLogFilePath = "/tmp/" + logFileName
+
+	terminationMessagePath = "/dev/termination-log"
+	// Kubernetes truncates termination messages longer than 4096 bytes
+	maxTerminationMessageLength = 4096
 )
 
 // TODO: Handle sparse checkout
@@ -43,6 +54,11 @@ func main() {
 	mw := io.MultiWriter(os.Stdout, f)
 	log.SetOutput(mw)
 
+	if err := internal.Init(); err != nil {
+		log.Printf("Failed to initialize project-clone: %s", err)
+		os.Exit(1)
+	}
+
 	// Clean up temp dir on exit
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
@@ -60,24 +76,107 @@ func main() {
 		log.Printf("Failed to read current DevWorkspace: %s", err)
 		os.Exit(1)
 	}
+	if err := internal.RestoreFromPrebuild(); err != nil {
+		log.Printf("Failed to restore projects from prebuild, projects will be cloned instead: %s", err)
+	}
+	state, err := internal.ReadProjectCloneState()
+	if err != nil {
+		log.Printf("Failed to read project-clone state, treating all projects as new: %s", err)
+		state = &internal.ProjectCloneState{}
+	}
+	newState := &internal.ProjectCloneState{
+		Projects:       map[string]dw.Project{},
+		StarterProject: state.StarterProject,
+	}
+	var warnings []string
 	for _, project := range workspace.Projects {
 		log.Printf("Processing project %s", project.Name)
+		lastApplied := state.GetLastApplied(project.Name)
 		var err error
 		switch {
 		case project.Git != nil:
-			err = git.SetupGitProject(project)
+			err = git.SetupGitProject(project, lastApplied)
 		case project.Zip != nil:
-			err = zip.SetupZipProject(project)
+			err = zip.SetupZipProject(project, lastApplied)
 		default:
 			log.Printf("Project does not specify Git or Zip source")
+			newState.KeepUnprocessed(state)
+			writeProjectCloneState(newState)
 			copyLogFileToProjectsRoot()
 			os.Exit(0)
 		}
+		var skippedErr *internal.ProjectUpdateSkippedError
+		if errors.As(err, &skippedErr) {
+			log.Printf("Skipped updating project %s: %s", project.Name, skippedErr)
+			warnings = append(warnings, skippedErr.Error())
+			if lastApplied != nil {
+				newState.Projects[project.Name] = *lastApplied
+			}
+			continue
+		}
 		if err != nil {
 			log.Printf("Encountered error while setting up project %s: %s", project.Name, err)
+			newState.KeepUnprocessed(state)
+			writeProjectCloneState(newState)
 			copyLogFileToProjectsRoot()
 			os.Exit(0)
 		}
+		newState.Projects[project.Name] = project
+	}
+	if msg := setupStarterProject(workspace, newState); msg != "" {
+		warnings = append(warnings, msg)
+	}
+	writeProjectCloneState(newState)
+	writeTerminationMessage(warnings)
+}
+
+// setupStarterProject sets up the starter project selected in the workspace, if any, and records it in state. Starter
+// projects are only set up once: if state shows a starter project was set up previously, nothing is done. If the
+// starter project cannot be set up, a message describing the error is returned so that it can be reported in the
+// DevWorkspace's status.
+func setupStarterProject(workspace *dw.DevWorkspaceTemplateSpec, state *internal.ProjectCloneState) string {
+	starterProject, err := projects.GetStarterProject(workspace)
+	if err != nil {
+		log.Printf("Failed to get starter project: %s", err)
+		return fmt.Sprintf("failed to get starter project: %s", err)
+	}
+	if starterProject == nil {
+		return ""
+	}
+	if state.StarterProject != "" {
+		log.Printf("Starter project %s was already set up", state.StarterProject)
+		return ""
+	}
+	log.Printf("Processing starter project %s", starterProject.Name)
+	if err := starter.SetupStarterProject(*starterProject); err != nil {
+		log.Printf("Encountered error while setting up starter project %s: %s", starterProject.Name, err)
+		copyLogFileToProjectsRoot()
+		return fmt.Sprintf("failed to set up starter project %s: %s", starterProject.Name, err)
+	}
+	state.StarterProject = starterProject.Name
+	return ""
+}
+
+// writeProjectCloneState records the applied project configuration in $PROJECTS_ROOT. Errors are logged but otherwise
+// ignored, as a missing state file only prevents updating existing projects on the next start.
+func writeProjectCloneState(state *internal.ProjectCloneState) {
+	if err := internal.WriteProjectCloneState(state); err != nil {
+		log.Printf("Failed to write project-clone state: %s", err)
+	}
+}
+
+// writeTerminationMessage writes messages to the container's termination message path, where they can be read
+// by the DevWorkspace Operator and surfaced in the DevWorkspace's status.
+func writeTerminationMessage(messages []string) {
+	if len(messages) == 0 {
+		return
+	}
+	msg := strings.Join(messages, "; ")
+	if len(msg) > maxTerminationMessageLength {
+		msg = msg[:maxTerminationMessageLength]
+	}
+	if err := os.WriteFile(terminationMessagePath, []byte(msg), 0644); err != nil {
+		log.Printf("Failed to write termination message: %s", err)
 	}
 }
 
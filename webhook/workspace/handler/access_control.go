@@ -30,6 +30,18 @@ import (
 // cannot perform the requested changes, or if an unexpected error occurs.
 // Note: we only perform validation on v1alpha2 DevWorkspaces at the moment, as v1alpha1 DevWorkspaces do not support attributes.
 func (h *WebhookHandler) validateUserPermissions(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspace) error {
+	if err := h.validateSCCAttribute(ctx, req, newWksp, oldWksp); err != nil {
+		return err
+	}
+	if err := h.validatePodOverridesAttribute(ctx, req, newWksp, oldWksp); err != nil {
+		return err
+	}
+	return h.validateImageBuildPermissions(ctx, req, newWksp, oldWksp)
+}
+
+// validateSCCAttribute validates that the user making the request has permissions to use the SCC requested by the
+// workspace's SCC attribute, if present.
+func (h *WebhookHandler) validateSCCAttribute(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspace) error {
 	if !newWksp.Spec.Template.Attributes.Exists(constants.WorkspaceSCCAttribute) {
 		// Workspace is not requesting anything we need to check RBAC for.
 		return nil
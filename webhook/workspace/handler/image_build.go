@@ -0,0 +1,122 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// imageBuildsGroup and imageBuildsResource define the (virtual) resource users must be allowed to "use" in order
+	// to create DevWorkspaces with Dockerfile image components. As builds run as root, the "root" resource name is
+	// required for any image component; components that set rootRequired additionally require "privileged", e.g.
+	//
+	//   rules:
+	//     - apiGroups: ["controller.devfile.io"]
+	//       resources: ["imagebuilds"]
+	//       resourceNames: ["root", "privileged"]
+	//       verbs: ["use"]
+	imageBuildsGroup    = "controller.devfile.io"
+	imageBuildsResource = "imagebuilds"
+
+	imageBuildRoot       = "root"
+	imageBuildPrivileged = "privileged"
+)
+
+// validateImageBuildPermissions validates that the user making the request has permissions to build the Dockerfile
+// image components defined in the workspace. On update, only permissions that were not already required by the old
+// workspace are checked.
+func (h *WebhookHandler) validateImageBuildPermissions(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspace) error {
+	oldPermissions := map[string]bool{}
+	if oldWksp != nil {
+		for _, permission := range getImageBuildPermissions(oldWksp) {
+			oldPermissions[permission] = true
+		}
+	}
+	for _, permission := range getImageBuildPermissions(newWksp) {
+		if oldPermissions[permission] {
+			// RBAC has already been checked for this permission, don't recheck
+			continue
+		}
+		if err := h.validateImageBuildPermission(ctx, req, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *WebhookHandler) validateImageBuildPermission(ctx context.Context, req admission.Request, permission string) error {
+	sar := &v1.LocalSubjectAccessReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: req.Namespace,
+		},
+		Spec: v1.SubjectAccessReviewSpec{
+			ResourceAttributes: &v1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      "use",
+				Group:     imageBuildsGroup,
+				Resource:  imageBuildsResource,
+				Name:      permission,
+			},
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+		},
+	}
+
+	err := h.Client.Create(ctx, sar)
+	if err != nil {
+		return fmt.Errorf("failed to create subjectaccessreview for request: %w", err)
+	}
+
+	if !sar.Status.Allowed {
+		if sar.Status.Reason != "" {
+			return fmt.Errorf("user is not permitted to create %s image builds: %s", permission, sar.Status.Reason)
+		}
+		return fmt.Errorf("user is not permitted to create %s image builds", permission)
+	}
+
+	return nil
+}
+
+// getImageBuildPermissions returns the imagebuilds resource names required to build the Dockerfile image components
+// defined in a workspace.
+func getImageBuildPermissions(wksp *dwv2.DevWorkspace) []string {
+	needsRoot, needsPrivileged := false, false
+	for _, component := range wksp.Spec.Template.Components {
+		if component.Image == nil || component.Image.Dockerfile == nil {
+			continue
+		}
+		needsRoot = true
+		if component.Image.Dockerfile.RootRequired != nil && *component.Image.Dockerfile.RootRequired {
+			needsPrivileged = true
+		}
+	}
+	var permissions []string
+	if needsRoot {
+		permissions = append(permissions, imageBuildRoot)
+	}
+	if needsPrivileged {
+		permissions = append(permissions, imageBuildPrivileged)
+	}
+	return permissions
+}
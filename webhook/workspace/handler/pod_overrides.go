@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	v1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+const (
+	// podOverridesGroup and podOverridesResource define the (virtual) resource users must be allowed to "use" in order
+	// to set sensitive fields in the pod-overrides attribute. The name of the resource is the field being set, e.g.
+	//
+	//   rules:
+	//     - apiGroups: ["controller.devfile.io"]
+	//       resources: ["podoverrides"]
+	//       resourceNames: ["hostNetwork"]
+	//       verbs: ["use"]
+	podOverridesGroup    = "controller.devfile.io"
+	podOverridesResource = "podoverrides"
+)
+
+// validatePodOverridesAttribute validates that the user making the request has permissions to set each sensitive
+// field (e.g. hostNetwork or hostPath volumes) in the workspace's pod-overrides attribute. On update, only fields
+// that were not already set in the old workspace are checked.
+func (h *WebhookHandler) validatePodOverridesAttribute(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspace) error {
+	newFields, err := wsprovision.GetSensitivePodOverrideFields(&newWksp.Spec.Template)
+	if err != nil {
+		return err
+	}
+	if len(newFields) == 0 {
+		return nil
+	}
+	oldFields := map[string]bool{}
+	if oldWksp != nil {
+		// If the old workspace's attribute is invalid, check all fields on the new workspace
+		fields, _ := wsprovision.GetSensitivePodOverrideFields(&oldWksp.Spec.Template)
+		for _, field := range fields {
+			oldFields[field] = true
+		}
+	}
+	for _, field := range newFields {
+		if oldFields[field] {
+			// RBAC has already been checked for this field, don't recheck
+			continue
+		}
+		if err := h.validatePodOverrideField(ctx, req, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *WebhookHandler) validatePodOverrideField(ctx context.Context, req admission.Request, field string) error {
+	sar := &v1.LocalSubjectAccessReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: req.Namespace,
+		},
+		Spec: v1.SubjectAccessReviewSpec{
+			ResourceAttributes: &v1.ResourceAttributes{
+				Namespace: req.Namespace,
+				Verb:      "use",
+				Group:     podOverridesGroup,
+				Resource:  podOverridesResource,
+				Name:      field,
+			},
+			User:   req.UserInfo.Username,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+		},
+	}
+
+	err := h.Client.Create(ctx, sar)
+	if err != nil {
+		return fmt.Errorf("failed to create subjectaccessreview for request: %w", err)
+	}
+
+	if !sar.Status.Allowed {
+		if sar.Status.Reason != "" {
+			return fmt.Errorf("user is not permitted to set %s in the %s attribute: %s", field, constants.PodOverridesAttribute, sar.Status.Reason)
+		}
+		return fmt.Errorf("user is not permitted to set %s in the %s attribute", field, constants.PodOverridesAttribute)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// ValidatePriorityClass denies requests that set the priority class attribute on a DevWorkspace to a priority class
+// that is not in the list of allowed priority classes defined in the DevWorkspaceOperatorConfig. Requests that do not
+// change the attribute are always allowed.
+func (h *WebhookHandler) ValidatePriorityClass(_ context.Context, req admission.Request) admission.Response {
+	wksp := &dwv2.DevWorkspace{}
+	err := h.Decoder.Decode(req, wksp)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	priorityClass, err := getPriorityClassAttribute(wksp)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if priorityClass == "" {
+		return admission.Allowed("DevWorkspace does not request a priority class")
+	}
+	if req.Operation == admissionv1.Update {
+		oldWksp := &dwv2.DevWorkspace{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldWksp); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if oldPriorityClass, err := getPriorityClassAttribute(oldWksp); err == nil && oldPriorityClass == priorityClass {
+			return admission.Allowed("Priority class is not changed")
+		}
+	}
+
+	operatorConfig, err := config.ReadOperatorConfig(h.Client)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to read operator configuration: %w", err))
+	}
+	allowed := operatorConfig.Workspace.AllowedPriorityClassNames
+	if allowed == nil {
+		return admission.Allowed("All priority classes are allowed")
+	}
+	for _, allowedPriorityClass := range allowed {
+		if allowedPriorityClass == priorityClass {
+			return admission.Allowed("Priority class is allowed")
+		}
+	}
+	return admission.Denied(fmt.Sprintf("Priority class '%s' is not allowed for DevWorkspaces. Allowed priority classes are: %s",
+		priorityClass, strings.Join(allowed, ", ")))
+}
+
+func getPriorityClassAttribute(wksp *dwv2.DevWorkspace) (string, error) {
+	if !wksp.Spec.Template.Attributes.Exists(constants.PriorityClassAttribute) {
+		return "", nil
+	}
+	var err error
+	priorityClass := wksp.Spec.Template.Attributes.GetString(constants.PriorityClassAttribute, &err)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attribute %s: %w", constants.PriorityClassAttribute, err)
+	}
+	return priorityClass, nil
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func TestValidatePriorityClass(t *testing.T) {
+	allowedConfig := &v1alpha1.DevWorkspaceOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.OperatorConfigName,
+			Namespace: testOperatorNamespace,
+		},
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				AllowedPriorityClassNames: []string{"low-priority"},
+			},
+		},
+	}
+	getWorkspace := func(priorityClass string) *dwv2.DevWorkspace {
+		workspace := getQuotaTestWorkspace("test-workspace", true, "")
+		if priorityClass != "" {
+			workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.PriorityClassAttribute, priorityClass)
+		}
+		return workspace
+	}
+
+	tests := []struct {
+		name            string
+		workspace       *dwv2.DevWorkspace
+		oldWorkspace    *dwv2.DevWorkspace
+		existingObjects []client.Object
+		expectedAllowed bool
+	}{
+		{
+			name:            "Allows workspaces without priority class",
+			workspace:       getWorkspace(""),
+			existingObjects: []client.Object{allowedConfig},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Allows any priority class when no list is configured",
+			workspace:       getWorkspace("high-priority"),
+			expectedAllowed: true,
+		},
+		{
+			name:            "Allows priority class in allowed list",
+			workspace:       getWorkspace("low-priority"),
+			existingObjects: []client.Object{allowedConfig},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Denies priority class not in allowed list",
+			workspace:       getWorkspace("high-priority"),
+			existingObjects: []client.Object{allowedConfig},
+			expectedAllowed: false,
+		},
+		{
+			name:            "Denies changing priority class to one not in allowed list",
+			workspace:       getWorkspace("high-priority"),
+			oldWorkspace:    getWorkspace("low-priority"),
+			existingObjects: []client.Object{allowedConfig},
+			expectedAllowed: false,
+		},
+		{
+			name:            "Allows updates that do not change priority class",
+			workspace:       getWorkspace("high-priority"),
+			oldWorkspace:    getWorkspace("high-priority"),
+			existingObjects: []client.Object{allowedConfig},
+			expectedAllowed: true,
+		},
+	}
+
+	os.Setenv(infrastructure.WatchNamespaceEnvVar, testOperatorNamespace)
+	defer os.Unsetenv(infrastructure.WatchNamespaceEnvVar)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := getTestHandler(tt.existingObjects...)
+			req := getTestRequest(t, tt.workspace, tt.oldWorkspace)
+			resp := handler.ValidatePriorityClass(context.Background(), req)
+			assert.Equal(t, tt.expectedAllowed, resp.Allowed, "Unexpected response: %s", resp.Result.Message)
+			if !tt.expectedAllowed {
+				assert.Equal(t, int32(http.StatusForbidden), resp.Result.Code, "Should deny request rather than error: %s", resp.Result.Message)
+			}
+		})
+	}
+}
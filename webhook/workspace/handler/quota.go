@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// ValidateCreatorQuota denies requests that start a DevWorkspace (i.e. create a DevWorkspace with .spec.started = true
+// or change .spec.started from false to true) if doing so would exceed the creator quota defined in the
+// DevWorkspaceOperatorConfig.
+func (h *WebhookHandler) ValidateCreatorQuota(ctx context.Context, req admission.Request) admission.Response {
+	wksp := &dwv2.DevWorkspace{}
+	err := h.Decoder.Decode(req, wksp)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !wksp.Spec.Started {
+		return admission.Allowed("DevWorkspace is not started")
+	}
+	if req.Operation == admissionv1.Update {
+		oldWksp := &dwv2.DevWorkspace{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldWksp); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if oldWksp.Spec.Started {
+			return admission.Allowed("DevWorkspace is already started")
+		}
+	}
+
+	creator := wksp.Labels[constants.DevWorkspaceCreatorLabel]
+	if creator == "" {
+		return admission.Allowed("DevWorkspace does not have a creator")
+	}
+
+	operatorConfig, err := config.ReadOperatorConfig(h.Client)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to read operator configuration: %w", err))
+	}
+	quota := operatorConfig.Workspace.CreatorQuota
+	if quota == nil {
+		return admission.Allowed("No creator quota is configured")
+	}
+
+	if quota.MaxRunningWorkspaces != nil {
+		running, err := h.countRunningWorkspaces(ctx, creator, wksp)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if running+1 > *quota.MaxRunningWorkspaces {
+			return admission.Denied(fmt.Sprintf("Cannot start DevWorkspace: user already has %d running DevWorkspaces, "+
+				"and at most %d are allowed. Stop another DevWorkspace before starting this one", running, *quota.MaxRunningWorkspaces))
+		}
+	}
+
+	if len(quota.MaxResources) > 0 {
+		used, err := h.getCreatorResourceUsage(ctx, creator, wksp)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		required, err := getWorkspaceResources(wksp, operatorConfig.Workspace.DefaultContainerResources)
+		if err != nil {
+			return admission.Denied(fmt.Sprintf("Failed to compute DevWorkspace resources: %s", err))
+		}
+		for name, max := range quota.MaxResources {
+			total := used[name].DeepCopy()
+			total.Add(required[name])
+			if total.Cmp(max) > 0 {
+				usedQuantity := used[name]
+				requiredQuantity := required[name]
+				return admission.Denied(fmt.Sprintf("Cannot start DevWorkspace: it requires %s %s, and running DevWorkspaces for this user "+
+					"already use %s of the allowed %s. Stop another DevWorkspace before starting this one",
+					requiredQuantity.String(), name, usedQuantity.String(), max.String()))
+			}
+		}
+	}
+
+	return admission.Allowed("DevWorkspace is within creator quota")
+}
+
+// countRunningWorkspaces returns the number of DevWorkspaces with creator that are started and not failed, ignoring
+// the workspace being started. DevWorkspaces are read from the API server, to avoid caching every DevWorkspace in
+// the cluster in the webhook server.
+func (h *WebhookHandler) countRunningWorkspaces(ctx context.Context, creator string, wksp *dwv2.DevWorkspace) (int, error) {
+	workspaces := &dwv2.DevWorkspaceList{}
+	if err := h.APIReader.List(ctx, workspaces, client.MatchingLabels{constants.DevWorkspaceCreatorLabel: creator}); err != nil {
+		return 0, fmt.Errorf("failed to list DevWorkspaces: %w", err)
+	}
+	count := 0
+	for _, workspace := range workspaces.Items {
+		if workspace.Namespace == wksp.Namespace && workspace.Name == wksp.Name {
+			continue
+		}
+		if workspace.Spec.Started && workspace.Status.Phase != dwv2.DevWorkspaceStatusFailed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// getCreatorResourceUsage returns the total resources used by pods that belong to workspaces with creator, ignoring
+// pods that belong to the workspace being started. Pods are read from the API server, to avoid caching every pod in
+// the cluster in the webhook server.
+func (h *WebhookHandler) getCreatorResourceUsage(ctx context.Context, creator string, wksp *dwv2.DevWorkspace) (corev1.ResourceList, error) {
+	pods := &corev1.PodList{}
+	if err := h.APIReader.List(ctx, pods, client.MatchingLabels{constants.DevWorkspaceCreatorLabel: creator}); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	used := corev1.ResourceList{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if wksp.Status.DevWorkspaceId != "" && pod.Labels[constants.DevWorkspaceIDLabel] == wksp.Status.DevWorkspaceId {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			addResources(used, container.Resources.Limits, container.Resources.Requests)
+		}
+	}
+	return used, nil
+}
+
+// getWorkspaceResources estimates the resources required by a DevWorkspace from the container components defined
+// directly in its template. Components from parents and plugins are not resolved by the webhook server and are not
+// included, so the resource quota is best-effort.
+func getWorkspaceResources(wksp *dwv2.DevWorkspace, defaults *corev1.ResourceRequirements) (corev1.ResourceList, error) {
+	if defaults == nil {
+		defaults = &corev1.ResourceRequirements{}
+	}
+	required := corev1.ResourceList{}
+	for _, component := range wksp.Spec.Template.Components {
+		if component.Container == nil {
+			continue
+		}
+		limits, requests := defaults.Limits.DeepCopy(), defaults.Requests.DeepCopy()
+		if limits == nil {
+			limits = corev1.ResourceList{}
+		}
+		if requests == nil {
+			requests = corev1.ResourceList{}
+		}
+		for name, value := range map[corev1.ResourceName]string{
+			corev1.ResourceMemory: component.Container.MemoryLimit,
+			corev1.ResourceCPU:    component.Container.CpuLimit,
+		} {
+			if value == "" {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s limit for component %s: %w", name, component.Name, err)
+			}
+			limits[name] = quantity
+		}
+		for name, value := range map[corev1.ResourceName]string{
+			corev1.ResourceMemory: component.Container.MemoryRequest,
+			corev1.ResourceCPU:    component.Container.CpuRequest,
+		} {
+			if value == "" {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s request for component %s: %w", name, component.Name, err)
+			}
+			requests[name] = quantity
+		}
+		addResources(required, limits, requests)
+	}
+	return required, nil
+}
+
+// addResources adds the resources used by a container to total. For each resource, the container's limit is used
+// if set; otherwise, its request is used.
+func addResources(total, limits, requests corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceMemory, corev1.ResourceCPU} {
+		value, ok := limits[name]
+		if !ok {
+			value, ok = requests[name]
+		}
+		if !ok {
+			continue
+		}
+		sum := total[name]
+		sum.Add(value)
+		total[name] = sum
+	}
+}
@@ -0,0 +1,249 @@
+//
+// Copyright (c) 2019-2022 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	testNamespace         = "test-namespace"
+	testOperatorNamespace = "devworkspace-controller"
+	testCreator           = "test-creator"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(dwv2.AddToScheme(scheme))
+}
+
+func TestValidateCreatorQuota(t *testing.T) {
+	maxRunning := 1
+	quotaConfig := &v1alpha1.DevWorkspaceOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.OperatorConfigName,
+			Namespace: testOperatorNamespace,
+		},
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				CreatorQuota: &v1alpha1.CreatorQuota{
+					MaxRunningWorkspaces: &maxRunning,
+					MaxResources: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+			},
+		},
+	}
+	runningWorkspace := getQuotaTestWorkspace("running-workspace", true, "")
+	runningWorkspace.Status.Phase = dwv2.DevWorkspaceStatusRunning
+	failedWorkspace := getQuotaTestWorkspace("failed-workspace", true, "")
+	failedWorkspace.Status.Phase = dwv2.DevWorkspaceStatusFailed
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "running-pod",
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceCreatorLabel: testCreator},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test-container",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		workspace       *dwv2.DevWorkspace
+		oldWorkspace    *dwv2.DevWorkspace
+		existingObjects []client.Object
+		expectedAllowed bool
+	}{
+		{
+			name:            "Allows stopped workspaces",
+			workspace:       getQuotaTestWorkspace("test-workspace", false, "8Gi"),
+			existingObjects: []client.Object{quotaConfig, runningWorkspace, runningPod},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Allows workspaces that are already started",
+			workspace:       getQuotaTestWorkspace("test-workspace", true, "8Gi"),
+			oldWorkspace:    getQuotaTestWorkspace("test-workspace", true, "8Gi"),
+			existingObjects: []client.Object{quotaConfig, runningWorkspace, runningPod},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Allows workspaces when no quota is configured",
+			workspace:       getQuotaTestWorkspace("test-workspace", true, "8Gi"),
+			existingObjects: []client.Object{runningWorkspace, runningPod},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Allows workspaces within quota",
+			workspace:       getQuotaTestWorkspace("test-workspace", true, "1Gi"),
+			existingObjects: []client.Object{quotaConfig, failedWorkspace, runningPod},
+			expectedAllowed: true,
+		},
+		{
+			name:            "Denies workspaces exceeding running workspace quota",
+			workspace:       getQuotaTestWorkspace("test-workspace", true, "1Gi"),
+			existingObjects: []client.Object{quotaConfig, runningWorkspace},
+			expectedAllowed: false,
+		},
+		{
+			name:            "Denies workspaces exceeding resource quota",
+			workspace:       getQuotaTestWorkspace("test-workspace", true, "2Gi"),
+			oldWorkspace:    getQuotaTestWorkspace("test-workspace", false, "2Gi"),
+			existingObjects: []client.Object{quotaConfig, runningPod},
+			expectedAllowed: false,
+		},
+	}
+
+	os.Setenv(infrastructure.WatchNamespaceEnvVar, testOperatorNamespace)
+	defer os.Unsetenv(infrastructure.WatchNamespaceEnvVar)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := getTestHandler(tt.existingObjects...)
+			req := getTestRequest(t, tt.workspace, tt.oldWorkspace)
+			resp := handler.ValidateCreatorQuota(context.Background(), req)
+			assert.Equal(t, tt.expectedAllowed, resp.Allowed, "Unexpected response: %s", resp.Result.Message)
+			if !tt.expectedAllowed {
+				assert.Equal(t, int32(http.StatusForbidden), resp.Result.Code, "Should deny request rather than error: %s", resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestGetWorkspaceResources(t *testing.T) {
+	workspace := getQuotaTestWorkspace("test-workspace", true, "2Gi")
+	workspace.Spec.Template.Components = append(workspace.Spec.Template.Components, dwv2.Component{
+		Name: "default-container",
+		ComponentUnion: dwv2.ComponentUnion{
+			Container: &dwv2.ContainerComponent{},
+		},
+	})
+	defaults := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+	}
+	resources, err := getWorkspaceResources(workspace, defaults)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	expected := resource.MustParse("2176Mi")
+	actual := resources[corev1.ResourceMemory]
+	assert.Equal(t, 0, expected.Cmp(actual), "Should use container limits and defaults, got %s", actual.String())
+
+	workspace.Spec.Template.Components[0].Container.MemoryLimit = "invalid"
+	_, err = getWorkspaceResources(workspace, defaults)
+	assert.Error(t, err, "Should return error for invalid memory limit")
+}
+
+func getQuotaTestWorkspace(name string, started bool, memoryLimit string) *dwv2.DevWorkspace {
+	return &dwv2.DevWorkspace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DevWorkspace",
+			APIVersion: dwv2.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceCreatorLabel: testCreator},
+		},
+		Spec: dwv2.DevWorkspaceSpec{
+			Started: started,
+			Template: dwv2.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dwv2.DevWorkspaceTemplateSpecContent{
+					Components: []dwv2.Component{
+						{
+							Name: "test-container",
+							ComponentUnion: dwv2.ComponentUnion{
+								Container: &dwv2.ContainerComponent{
+									Container: dwv2.Container{MemoryLimit: memoryLimit},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTestRequest(t *testing.T, workspace, oldWorkspace *dwv2.DevWorkspace) admission.Request {
+	raw, err := json.Marshal(workspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Namespace: workspace.Namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+	if oldWorkspace != nil {
+		oldRaw, err := json.Marshal(oldWorkspace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Operation = admissionv1.Update
+		req.OldObject = runtime.RawExtension{Raw: oldRaw}
+	}
+	return req
+}
+
+func getTestHandler(objs ...client.Object) *WebhookHandler {
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		panic(err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return &WebhookHandler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		Decoder:   decoder,
+	}
+}
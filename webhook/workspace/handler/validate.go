@@ -24,6 +24,8 @@ import (
 	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	devfilevalidation "github.com/devfile/api/v2/pkg/validation"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/container"
 )
 
 func (h *WebhookHandler) ValidateDevfile(ctx context.Context, req admission.Request) admission.Response {
@@ -67,6 +69,13 @@ func (h *WebhookHandler) ValidateDevfile(ctx context.Context, req admission.Requ
 		}
 	}
 
+	// validate container overrides
+	for _, component := range workspace.Components {
+		if err := container.ValidateContainerOverrides(&component); err != nil {
+			devfileErrors = append(devfileErrors, err.Error())
+		}
+	}
+
 	if len(devfileErrors) > 0 {
 		return admission.Denied(fmt.Sprintf("\n%s\n", strings.Join(devfileErrors, "\n")))
 	}
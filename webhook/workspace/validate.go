@@ -41,6 +41,12 @@ func (v *ResourcesValidator) Handle(ctx context.Context, req admission.Request)
 		return v.ValidateExecOnConnect(ctx, req)
 	}
 	if req.Kind == handler.V1alpha2DevWorkspaceKind && (req.Operation == admissionv1.Create || req.Operation == admissionv1.Update) {
+		if resp := v.ValidateCreatorQuota(ctx, req); !resp.Allowed {
+			return resp
+		}
+		if resp := v.ValidatePriorityClass(ctx, req); !resp.Allowed {
+			return resp
+		}
 		return v.ValidateDevfile(ctx, req)
 	}
 
@@ -58,6 +64,15 @@ func (v *ResourcesValidator) InjectClient(c client.Client) error {
 	return nil
 }
 
+// ResourcesValidator implements inject.APIReader.
+// An uncached reader will be automatically injected.
+
+// InjectAPIReader injects the API reader.
+func (v *ResourcesValidator) InjectAPIReader(r client.Reader) error {
+	v.APIReader = r
+	return nil
+}
+
 // WorkspaceMutator implements admission.DecoderInjector.
 // A decoder will be automatically injected.
 